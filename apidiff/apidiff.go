@@ -24,7 +24,42 @@ import (
 // It classifies each difference as either compatible or incompatible (breaking.) For
 // a detailed discussion of what constitutes an incompatible change, see the README.
 func Changes(old, new *types.Package) Report {
-	return changesInternal(old, new, old.Path(), new.Path())
+	return ChangesWithOptions(old, new, Options{})
+}
+
+// Options are optional settings for ChangesWithOptions and
+// ModuleChangesWithOptions. The zero Options gives the behavior of Changes
+// and ModuleChanges.
+type Options struct {
+	// Renames maps old package path prefixes to new ones, for old and new
+	// modules whose package paths differ because some packages were renamed.
+	// Each key is a path prefix in the old module, and its value is the
+	// prefix that replaces it in the new module. A prefix matches a package
+	// path that equals it or that continues with a slash, and the longest
+	// matching prefix is used. For example, with the renames
+	//
+	//	{"example.com/a": "example.com/b"}
+	//
+	// the old package example.com/a/sub is compared with the new package
+	// example.com/b/sub, and the type example.com/a/sub.T corresponds to
+	// example.com/b/sub.T wherever it is mentioned.
+	//
+	// Packages of the old module that are not renamed are matched with the
+	// new module's packages by their paths relative to the module paths, as
+	// they are by ModuleChanges.
+	Renames map[string]string
+
+	// ParameterNames, if true, reports a change to the names of the
+	// parameters or results of an exported function or method whose type is
+	// otherwise unchanged. Such a change does not break any Go code, but it
+	// can affect generated documentation and code generators, so it is
+	// reported as a compatible change.
+	ParameterNames bool
+}
+
+// ChangesWithOptions is like Changes, but with the given options.
+func ChangesWithOptions(old, new *types.Package, opts Options) Report {
+	return changesInternal(old, new, old.Path(), new.Path(), opts)
 }
 
 // changesInternal contains the core logic for comparing a single package, shared
@@ -34,8 +69,10 @@ func Changes(old, new *types.Package) Report {
 // module. This is used to give change messages appropriate context for object names.
 // The old and new root must be tracked independently, since each side of the diff
 // operation may be a different path.
-func changesInternal(old, new *types.Package, oldRootPackagePath, newRootPackagePath string) Report {
+func changesInternal(old, new *types.Package, oldRootPackagePath, newRootPackagePath string, opts Options) Report {
 	d := newDiffer(old, new)
+	d.renames = opts.Renames
+	d.parameterNames = opts.ParameterNames
 	d.checkPackage(oldRootPackagePath)
 	r := Report{}
 	for _, m := range d.incompatibles.collect(oldRootPackagePath, newRootPackagePath) {
@@ -52,11 +89,21 @@ func changesInternal(old, new *types.Package, oldRootPackagePath, newRootPackage
 // (breaking). This includes the addition and removal of entire packages. For a
 // detailed discussion of what constitutes an incompatible change, see the README.
 func ModuleChanges(old, new *Module) Report {
+	return ModuleChangesWithOptions(old, new, Options{})
+}
+
+// ModuleChangesWithOptions is like ModuleChanges, but with the given options.
+func ModuleChangesWithOptions(old, new *Module, opts Options) Report {
+	renames := opts.Renames
 	var r Report
 
 	oldPkgs := make(map[string]*types.Package)
 	for _, p := range old.Packages {
-		oldPkgs[old.relativePath(p)] = p
+		if path, ok := renamePath(renames, p.Path()); ok {
+			oldPkgs[strings.TrimPrefix(path, new.Path)] = p
+		} else {
+			oldPkgs[old.relativePath(p)] = p
+		}
 	}
 
 	newPkgs := make(map[string]*types.Package)
@@ -67,7 +114,7 @@ func ModuleChanges(old, new *Module) Report {
 	for n, op := range oldPkgs {
 		if np, ok := newPkgs[n]; ok {
 			// shared package, compare surfaces
-			rr := changesInternal(op, np, old.Path, new.Path)
+			rr := changesInternal(op, np, old.Path, new.Path, opts)
 			r.Changes = append(r.Changes, rr.Changes...)
 		} else {
 			// old package was removed
@@ -92,6 +139,22 @@ func packageChange(p *types.Package, change string, compatible bool) Change {
 	}
 }
 
+// renamePath returns path with the longest of the renames' keys that is a
+// prefix of path replaced by the corresponding value, and whether there is
+// such a prefix.
+func renamePath(renames map[string]string, path string) (string, bool) {
+	best := ""
+	for prefix := range renames {
+		if len(prefix) > len(best) && (path == prefix || strings.HasPrefix(path, prefix+"/")) {
+			best = prefix
+		}
+	}
+	if best == "" {
+		return path, false
+	}
+	return renames[best] + path[len(best):], true
+}
+
 // Module is a convenience type for representing a Go module with a path and a
 // slice of Packages contained within.
 type Module struct {
@@ -112,6 +175,14 @@ type differ struct {
 	// The values can be either named types or basic types.
 	correspondMap typeutil.Map
 
+	// renames maps old package path prefixes to new ones, for types from
+	// packages other than old and new. See Options.Renames.
+	renames map[string]string
+
+	// parameterNames is whether to report changes to parameter and result
+	// names. See Options.ParameterNames.
+	parameterNames bool
+
 	// Messages.
 	incompatibles messageSet
 	compatibles   messageSet
@@ -251,6 +322,7 @@ func (d *differ) checkObjects(old, new types.Object) {
 		switch new := new.(type) {
 		case *types.Func:
 			d.checkCorrespondence(objectWithSide{old, false}, "", old.Type(), new.Type())
+			d.checkParameterNames(objectWithSide{old, false}, old, new)
 			return
 		case *types.Var:
 			d.compatible(objectWithSide{old, false}, "", "changed from func to var")
@@ -308,6 +380,38 @@ func (d *differ) checkCorrespondence(obj objectWithSide, part string, old, new t
 	}
 }
 
+// checkParameterNames reports a change to the names of the parameters or
+// results of the function or method old, if its type corresponds to that of
+// new. It does nothing unless d.parameterNames is set.
+func (d *differ) checkParameterNames(obj objectWithSide, old, new *types.Func) {
+	if !d.parameterNames {
+		return
+	}
+	oldSig := old.Type().(*types.Signature)
+	newSig := new.Type().(*types.Signature)
+	if !d.correspond(oldSig, newSig) {
+		// The type change has already been reported.
+		return
+	}
+	if sameNames(oldSig.Params(), newSig.Params()) && sameNames(oldSig.Results(), newSig.Results()) {
+		return
+	}
+	olds := types.TypeString(oldSig, types.RelativeTo(d.old))
+	news := types.TypeString(newSig, types.RelativeTo(d.new))
+	d.compatible(obj, "", "parameter or result names changed from %s to %s", olds, news)
+}
+
+// sameNames reports whether the variables of two tuples of the same length
+// have the same names.
+func sameNames(t1, t2 *types.Tuple) bool {
+	for i := 0; i < t1.Len(); i++ {
+		if t1.At(i).Name() != t2.At(i).Name() {
+			return false
+		}
+	}
+	return true
+}
+
 func (d *differ) typeChanged(obj objectWithSide, part string, old, new types.Type) {
 	old = removeNamesFromSignature(old)
 	new = removeNamesFromSignature(new)
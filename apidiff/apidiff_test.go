@@ -82,6 +82,78 @@ func testModuleChanges(t *testing.T, x packagestest.Exporter) {
 	}
 }
 
+func TestModuleChangesRenames(t *testing.T) {
+	packagestest.TestAll(t, testModuleChangesRenames)
+}
+
+func testModuleChangesRenames(t *testing.T, x packagestest.Exporter) {
+	// Module example.com/a is renamed to example.com/b, without API changes.
+	// The root package refers to a type in another package of the module.
+	e := packagestest.Export(t, x, []packagestest.Module{
+		{
+			Name: "example.com/a",
+			Files: map[string]any{
+				"a.go":       "package a\n\nimport \"example.com/a/sub\"\n\nfunc F() sub.T { return 0 }",
+				"sub/sub.go": "package sub\n\ntype T int",
+			},
+		},
+		{
+			Name: "example.com/b",
+			Files: map[string]any{
+				"a.go":       "package a\n\nimport \"example.com/b/sub\"\n\nfunc F() sub.T { return 0 }",
+				"sub/sub.go": "package sub\n\ntype T int",
+			},
+		},
+	})
+	defer e.Cleanup()
+
+	a, err := loadModule(t, e.Config, "example.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadModule(t, e.Config, "example.com/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without the rename, sub.T in the old and new modules are different
+	// types, so F's result type changed.
+	if got := ModuleChanges(a, b).messages(false); len(got) == 0 {
+		t.Error("ModuleChanges: got no incompatible changes, want some")
+	}
+
+	report := ModuleChangesWithOptions(a, b, Options{Renames: map[string]string{"example.com/a": "example.com/b"}})
+	if got := report.messages(false); len(got) != 0 {
+		t.Errorf("ModuleChangesWithOptions: got incompatible changes %q, want none", got)
+	}
+	if got := report.messages(true); len(got) != 0 {
+		t.Errorf("ModuleChangesWithOptions: got compatible changes %q, want none", got)
+	}
+}
+
+func TestRenamePath(t *testing.T) {
+	renames := map[string]string{
+		"example.com/a":     "example.com/b",
+		"example.com/a/old": "example.com/b/new",
+	}
+	for _, test := range []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"example.com/a", "example.com/b", true},
+		{"example.com/a/sub", "example.com/b/sub", true},
+		{"example.com/a/old/x", "example.com/b/new/x", true},
+		{"example.com/ab", "example.com/ab", false},
+		{"example.com/c", "example.com/c", false},
+	} {
+		got, ok := renamePath(renames, test.path)
+		if got != test.want || ok != test.ok {
+			t.Errorf("renamePath(%q): got %q, %t, want %q, %t", test.path, got, ok, test.want, test.ok)
+		}
+	}
+}
+
 func TestChanges(t *testing.T) {
 	testfiles, err := filepath.Glob(filepath.Join("testdata", "*.go"))
 	if err != nil {
@@ -118,6 +190,41 @@ func TestChanges(t *testing.T) {
 	}
 }
 
+func TestParameterNames(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "go")
+	wanti, wantc := splitIntoPackages(t, filepath.Join("testdata", "paramnames", "paramnames.go"), dir)
+	sort.Strings(wanti)
+	sort.Strings(wantc)
+
+	oldpkg, err := loadPackage(t, "apidiff/old", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newpkg, err := loadPackage(t, "apidiff/new", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		opts  Options
+		wantc []string
+	}{
+		{Options{}, nil},
+		{Options{ParameterNames: true}, wantc},
+	} {
+		report := ChangesWithOptions(oldpkg.Types, newpkg.Types, test.opts)
+
+		got := report.messages(false)
+		if diff := cmp.Diff(wanti, got); diff != "" {
+			t.Errorf("%+v: incompatibles: mismatch (-want, +got)\n%s", test.opts, diff)
+		}
+		got = report.messages(true)
+		if diff := cmp.Diff(test.wantc, got); diff != "" {
+			t.Errorf("%+v: compatibles: mismatch (-want, +got)\n%s", test.opts, diff)
+		}
+	}
+}
+
 func splitIntoPackages(t *testing.T, file, dir string) (incompatibles, compatibles []string) {
 	// Read the input file line by line.
 	// Write a line into the old or new package,
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"go/types"
 	"reflect"
+	"strings"
 )
 
 func (d *differ) checkCompatible(otn *types.TypeName, old, new types.Type) {
@@ -274,6 +275,46 @@ func (d *differ) checkCompatibleDefined(otn *types.TypeName, old *types.Named, n
 	// A new method set is compatible with an old if the new exported methods are a superset of the old.
 	d.checkMethodSet(otn, old, new, additionsCompatible)
 	d.checkMethodSet(otn, types.NewPointer(old), types.NewPointer(new), additionsCompatible)
+	d.checkNewlyImplemented(otn, old, new)
+}
+
+// wellKnownInterfaces are standard interfaces that change how a value is
+// treated by fmt and similar packages when its type implements them.
+var wellKnownInterfaces = []struct {
+	name  string
+	iface *types.Interface
+}{
+	{"error", types.Universe.Lookup("error").Type().Underlying().(*types.Interface)},
+	{"fmt.Stringer", stringMethodInterface("String")},
+	{"fmt.GoStringer", stringMethodInterface("GoString")},
+}
+
+// stringMethodInterface returns the interface type with a single method of
+// the given name, taking no arguments and returning a string.
+func stringMethodInterface(name string) *types.Interface {
+	res := types.NewTuple(types.NewVar(0, nil, "", types.Typ[types.String]))
+	sig := types.NewSignatureType(nil, nil, nil, nil, res, false)
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(0, nil, name, sig)}, nil).Complete()
+}
+
+// checkNewlyImplemented notes when added methods make a defined type implement
+// one of wellKnownInterfaces. The change is compatible, but it can alter the
+// behavior of code such as fmt.Print that checks for those interfaces.
+// Generic types are not checked.
+func (d *differ) checkNewlyImplemented(otn *types.TypeName, old *types.Named, new types.Type) {
+	// types.Implements is unspecified for uninstantiated generic types.
+	if old.TypeParams().Len() > 0 {
+		return
+	}
+	var names []string
+	for _, w := range wellKnownInterfaces {
+		if !types.Implements(types.NewPointer(old), w.iface) && types.Implements(types.NewPointer(new), w.iface) {
+			names = append(names, w.name)
+		}
+	}
+	if len(names) > 0 {
+		d.compatible(objectWithSide{otn, false}, "", "now implements %s", strings.Join(names, ", "))
+	}
 }
 
 const (
@@ -315,6 +356,7 @@ func (d *differ) checkMethodSet(otn *types.TypeName, oldt, newt types.Type, addc
 				obj = objectWithSide{newMethod, true}
 			}
 			d.checkCorrespondence(obj, "", oldMethod.Type(), newMethod.Type())
+			d.checkParameterNames(obj, oldMethod.(*types.Func), newMethod.(*types.Func))
 		}
 	}
 
@@ -191,14 +191,15 @@ func (d *differ) establishCorrespondence(old *types.Named, new types.Type) bool
 		oobj := old.Obj()
 		nobj := newn.Obj()
 		if oobj.Pkg() != d.old || nobj.Pkg() != d.new {
-			// Compare the fully qualified names of the types.
+			// Compare the fully qualified names of the types, after applying
+			// any renames to the old type's package path.
 			//
 			// TODO(jba): when comparing modules, we should only look at the
 			// paths relative to the module path, because the module paths may differ.
 			// See cmd/gorelease/testdata/internalcompat.
 			var opath, npath string
 			if oobj.Pkg() != nil {
-				opath = oobj.Pkg().Path()
+				opath, _ = renamePath(d.renames, oobj.Pkg().Path())
 			}
 			if nobj.Pkg() != nil {
 				npath = nobj.Pkg().Path()
@@ -51,3 +51,40 @@ const (
 	// i Cr4: value changed from (0 + 4.1i) to (4.1 + 0i)
 	Cr4 = complex(4.1, 0)
 )
+
+// iota enums
+// both
+type Color int
+
+// old
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+const (
+	Small = iota
+	Medium
+	Large
+)
+
+// new
+const (
+	Red Color = iota
+	// c Yellow: added
+	Yellow
+	// i Green: value changed from 1 to 2
+	Green
+	// i Blue: value changed from 2 to 3
+	Blue
+)
+
+const (
+	// OK: adding a constant at the end keeps the other values.
+	Small = iota
+	Medium
+	Large
+	// c Huge: added
+	Huge
+)
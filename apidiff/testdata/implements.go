@@ -0,0 +1,69 @@
+package p
+
+// Adding a method is compatible, but if it makes a type implement a
+// well-known interface, such as error or fmt.Stringer, that is noted because
+// it can change how fmt and similar packages treat values of the type.
+
+// both
+type G int
+
+func (G) GoString() string { return "" }
+
+type Already struct{}
+
+func (Already) Error() string { return "" }
+
+// old
+type E struct{}
+
+type S int
+
+type P struct{}
+
+type Both struct{}
+
+type Gen[T any] struct{ t T }
+
+// new
+type E struct{}
+
+// c E: now implements error
+// c E.Error: added
+func (E) Error() string { return "" }
+
+type S int
+
+// c S: now implements fmt.Stringer
+// c S.String: added
+func (S) String() string { return "" }
+
+type P struct{}
+
+// Pointer methods count, since a pointer is often what is printed.
+// c P: now implements fmt.Stringer
+// c (*P).String: added
+func (*P) String() string { return "" }
+
+// A method with a different signature does not implement anything.
+// c G.String: added
+func (G) String(int) string { return "" }
+
+type Both struct{}
+
+// c Both: now implements error, fmt.Stringer
+// c Both.Error: added
+// c Both.String: added
+func (Both) Error() string  { return "" }
+func (Both) String() string { return "" }
+
+// Only interfaces it did not already implement are listed.
+// c Already: now implements fmt.Stringer
+// c (*Already).String: added
+func (*Already) String() string { return "" }
+
+// Generic types are not checked, since whether an uninstantiated type
+// implements an interface is unspecified.
+type Gen[T any] struct{ t T }
+
+// c Gen[T].String: added
+func (Gen[T]) String() string { return "" }
@@ -0,0 +1,36 @@
+// This file is used by TestParameterNames, since the messages about
+// parameter and result names appear only with Options.ParameterNames.
+
+package p
+
+// both
+type T int
+
+// old
+func F1(a int, b string) (n int) { return 0 }
+func F2(int)                     {}
+func F3(a int)                   {}
+func F4(a int)                   {}
+func f5(a int)                   {}
+func (T) M1(a int)               {}
+func (*T) M2(a int) (err error)  { return nil }
+
+// new
+// c F1: parameter or result names changed from func(a int, b string) (n int) to func(a int, c string) (m int)
+func F1(a int, c string) (m int) { return 0 }
+
+// c F2: parameter or result names changed from func(int) to func(x int)
+func F2(x int) {}
+
+func F3(a int) {} //OK: unchanged
+
+// i F4: changed from func(int) to func(bool)
+func F4(b bool) {}
+
+func f5(b int) {} //OK: unexported
+
+// c T.M1: parameter or result names changed from func(a int) to func(b int)
+func (T) M1(b int) {}
+
+// c (*T).M2: parameter or result names changed from func(a int) (err error) to func(a int) error
+func (*T) M2(a int) error { return nil }
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// loadPackageAtRevisions loads the package path as of the git revisions
+// oldRev and newRev of the repository containing the current directory.
+// A relative path is interpreted relative to the current directory's
+// position in the repository.
+func loadPackageAtRevisions(oldRev, newRev, path string) (oldpkg, newpkg *types.Package, err error) {
+	// The path of the current directory relative to the top of the
+	// repository, so that relative package paths work in the worktrees.
+	prefix, err := runGit("", "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, nil, err
+	}
+	oldpkg, err = loadPackageAtRevision(oldRev, prefix, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	newpkg, err = loadPackageAtRevision(newRev, prefix, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return oldpkg, newpkg, nil
+}
+
+// loadPackageAtRevision checks out the git revision rev into a temporary
+// worktree, loads the package path from the directory prefix of the
+// worktree, and removes the worktree.
+func loadPackageAtRevision(rev, prefix, path string) (_ *types.Package, err error) {
+	tmp, err := os.MkdirTemp("", "apidiff-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	wt := filepath.Join(tmp, "worktree")
+	if _, err := runGit("", "worktree", "add", "--detach", wt, rev); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if _, rmErr := runGit("", "worktree", "remove", "--force", wt); err == nil {
+			err = rmErr
+		}
+	}()
+
+	pkg, err := loadPackage(filepath.Join(wt, filepath.FromSlash(prefix)), path)
+	if err != nil {
+		return nil, fmt.Errorf("at revision %s: %v", rev, err)
+	}
+	return pkg.Types, nil
+}
+
+// runGit runs git with the given arguments in dir, or in the current
+// directory if dir is empty, and returns its output with surrounding white
+// space removed.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
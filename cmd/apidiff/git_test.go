@@ -0,0 +1,86 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/apidiff"
+)
+
+func TestLoadPackageAtRevisions(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go not found")
+	}
+
+	// Create a repository with two revisions of a package.
+	repo := t.TempDir()
+	gitIn := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=apidiff", "-c", "user.email=apidiff@example.com"}, args...)
+		if _, err := runGit(repo, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile := func(name, contents string) {
+		t.Helper()
+		name = filepath.Join(repo, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitIn("init", "-q")
+	writeFile("go.mod", "module example.com/m\n\ngo 1.18\n")
+	writeFile("p/p.go", "package p\n\nfunc F(int) {}\n\nfunc G() {}\n")
+	gitIn("add", "-A")
+	gitIn("commit", "-q", "-m", "old")
+	writeFile("p/p.go", "package p\n\nfunc F(string) {}\n\nfunc H() {}\n")
+	gitIn("commit", "-q", "-a", "-m", "new")
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(repo); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	oldpkg, newpkg, err := loadPackageAtRevisions("HEAD~1", "HEAD", "./p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := apidiff.Changes(oldpkg, newpkg).String()
+	want := `Incompatible changes:
+- F: changed from func(int) to func(string)
+- G: removed
+Compatible changes:
+- H: added
+`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	// The temporary worktrees are removed.
+	out, err := runGit(repo, "worktree", "list", "--porcelain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "worktree ") {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("got %d worktrees after loading, want 1:\n%s", n, out)
+	}
+}
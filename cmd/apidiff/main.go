@@ -20,8 +20,25 @@ var (
 	incompatibleOnly  = flag.Bool("incompatible", false, "display only incompatible changes")
 	allowInternal     = flag.Bool("allow-internal", false, "allow apidiff to compare internal packages")
 	moduleMode        = flag.Bool("m", false, "compare modules instead of packages")
+	gitMode           = flag.Bool("git", false, "compare a package at two git revisions")
+	parameterNames    = flag.Bool("paramnames", false, "also report changes to the parameter and result names of functions and methods")
+
+	// renames holds the -rename flags, mapping old package path prefixes to
+	// new ones.
+	renames = map[string]string{}
 )
 
+func init() {
+	flag.Func("rename", "with -m, rename old package path prefix OLD to NEW, given as `OLD=NEW`; may be repeated", func(s string) error {
+		old, new, ok := strings.Cut(s, "=")
+		if !ok || old == "" || new == "" {
+			return fmt.Errorf("want OLD=NEW, got %q", s)
+		}
+		renames[old] = new
+		return nil
+	})
+}
+
 func main() {
 	flag.Usage = func() {
 		w := flag.CommandLine.Output()
@@ -32,6 +49,15 @@ func main() {
 		fmt.Fprintf(w, "apidiff -m OLD NEW\n")
 		fmt.Fprintf(w, "   compares OLD and NEW module APIs\n")
 		fmt.Fprintf(w, "   where OLD and NEW are module paths\n")
+		fmt.Fprintf(w, "apidiff -m -rename OLDPREFIX=NEWPREFIX OLD NEW\n")
+		fmt.Fprintf(w, "   compares OLD and NEW module APIs, matching packages and types\n")
+		fmt.Fprintf(w, "   whose paths start with OLDPREFIX in OLD to those with NEWPREFIX\n")
+		fmt.Fprintf(w, "   in NEW, such as after renaming the module\n")
+		fmt.Fprintf(w, "apidiff -git OLDREV NEWREV PACKAGE\n")
+		fmt.Fprintf(w, "   compares the APIs of PACKAGE at git revisions OLDREV and NEWREV\n")
+		fmt.Fprintf(w, "   of the repository containing the current directory, where\n")
+		fmt.Fprintf(w, "   PACKAGE is an import path or a relative path like ./foo\n")
+		fmt.Fprintf(w, "   Each revision is checked out into a temporary git worktree.\n")
 		fmt.Fprintf(w, "apidiff -w FILE IMPORT_PATH\n")
 		fmt.Fprintf(w, "   writes export data of the package at IMPORT_PATH to FILE\n")
 		fmt.Fprintf(w, "   NOTE: In a GOPATH-less environment, this option consults the\n")
@@ -58,17 +84,28 @@ func main() {
 		os.Exit(0)
 	}
 
-	if len(flag.Args()) != 2 {
+	wantArgs := 2
+	if *gitMode {
+		wantArgs = 3
+	}
+	if len(flag.Args()) != wantArgs || (*gitMode && *moduleMode) || (len(renames) > 0 && !*moduleMode) {
 		flag.Usage()
 		os.Exit(2)
 	}
 
+	opts := apidiff.Options{Renames: renames, ParameterNames: *parameterNames}
 	var report apidiff.Report
-	if *moduleMode {
+	if *gitMode {
+		oldpkg, newpkg, err := loadPackageAtRevisions(flag.Arg(0), flag.Arg(1), flag.Arg(2))
+		if err != nil {
+			die("loading %s: %v", flag.Arg(2), err)
+		}
+		report = apidiff.ChangesWithOptions(oldpkg, newpkg, opts)
+	} else if *moduleMode {
 		oldmod := mustLoadOrReadModule(flag.Arg(0))
 		newmod := mustLoadOrReadModule(flag.Arg(1))
 
-		report = apidiff.ModuleChanges(oldmod, newmod)
+		report = apidiff.ModuleChangesWithOptions(oldmod, newmod, opts)
 	} else {
 		oldpkg := mustLoadOrReadPackage(flag.Arg(0))
 		newpkg := mustLoadOrReadPackage(flag.Arg(1))
@@ -78,7 +115,7 @@ func main() {
 				os.Exit(0)
 			}
 		}
-		report = apidiff.Changes(oldpkg, newpkg)
+		report = apidiff.ChangesWithOptions(oldpkg, newpkg, opts)
 	}
 
 	var err error
@@ -117,16 +154,19 @@ func mustLoadOrReadPackage(importPathOrFile string) *types.Package {
 }
 
 func mustLoadPackage(importPath string) *packages.Package {
-	pkg, err := loadPackage(importPath)
+	pkg, err := loadPackage("", importPath)
 	if err != nil {
 		die("loading %s: %v", importPath, err)
 	}
 	return pkg
 }
 
-func loadPackage(importPath string) (*packages.Package, error) {
+// loadPackage loads the package with the given import path, relative to dir.
+// If dir is empty, the current directory is used.
+func loadPackage(dir, importPath string) (*packages.Package, error) {
 	cfg := &packages.Config{Mode: packages.LoadTypes |
 		packages.NeedName | packages.NeedTypes | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
 	}
 	pkgs, err := packages.Load(cfg, importPath)
 	if err != nil {
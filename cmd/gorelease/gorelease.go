@@ -87,6 +87,7 @@ import (
 	"flag"
 	"fmt"
 	"go/build"
+	"go/types"
 	"io"
 	"log"
 	"os"
@@ -560,18 +561,6 @@ func makeReleaseReport(ctx context.Context, base, release moduleInfo) (report, e
 	// If we don't have a base version to compare against just check the new
 	// packages for errors.
 	shouldCompare := base.version != "none"
-	isInternal := func(modPath, pkgPath string) bool {
-		if !hasPathPrefix(pkgPath, modPath) {
-			panic(fmt.Sprintf("package %s not in module %s", pkgPath, modPath))
-		}
-		for pkgPath != modPath {
-			if path.Base(pkgPath) == "internal" {
-				return true
-			}
-			pkgPath = path.Dir(pkgPath)
-		}
-		return false
-	}
 	r := report{
 		base:    base,
 		release: release,
@@ -634,6 +623,8 @@ func makeReleaseReport(ctx context.Context, base, release moduleInfo) (report, e
 		}
 	}
 
+	r.warnings = internalTypeLeaks(release.modPath, release.pkgs)
+
 	if r.canVerifyReleaseVersion() {
 		if release.version == "" {
 			r.suggestReleaseVersion()
@@ -645,6 +636,137 @@ func makeReleaseReport(ctx context.Context, base, release moduleInfo) (report, e
 	return r, nil
 }
 
+// isInternal reports whether pkgPath, a package in the module modPath, is
+// internal to that module. Only path components after the module path are
+// considered.
+func isInternal(modPath, pkgPath string) bool {
+	if !hasPathPrefix(pkgPath, modPath) {
+		panic(fmt.Sprintf("package %s not in module %s", pkgPath, modPath))
+	}
+	for pkgPath != modPath {
+		if path.Base(pkgPath) == "internal" {
+			return true
+		}
+		pkgPath = path.Dir(pkgPath)
+	}
+	return false
+}
+
+// internalTypeLeaks returns warnings for exported declarations in the
+// module's non-internal packages whose types refer to types defined in the
+// module's internal packages. Importers of the module can't name such types,
+// so they're usually leaked by accident. They don't break importers, so they
+// don't prevent a release.
+func internalTypeLeaks(modPath string, pkgs []*packages.Package) []string {
+	// Only the module's own packages are considered, not those of nested
+	// modules, whose paths also start with modPath.
+	modPkgs := make(map[string]bool)
+	for _, pkg := range pkgs {
+		modPkgs[pkg.PkgPath] = true
+	}
+
+	var warnings []string
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.Name == "main" || isInternal(modPath, pkg.PkgPath) {
+			continue
+		}
+		seen := make(map[string]bool)
+		var name string
+		report := func(t types.Type) {
+			msg := fmt.Sprintf("%s.%s refers to %s, which is in an internal package and can't be named by importers", pkg.PkgPath, name, t)
+			if !seen[msg] {
+				seen[msg] = true
+				warnings = append(warnings, msg)
+			}
+		}
+		isInternalType := func(obj types.Object) bool {
+			p := obj.Pkg()
+			return p != nil && modPkgs[p.Path()] && isInternal(modPath, p.Path())
+		}
+		var walk func(t types.Type)
+		walk = func(t types.Type) {
+			switch t := t.(type) {
+			case *types.Alias:
+				// An alias declared outside an internal package can be named.
+				// One declared inside an internal package can't, but the type
+				// it denotes may be nameable elsewhere, as int is.
+				if isInternalType(t.Obj()) {
+					walk(types.Unalias(t))
+				}
+			case *types.Named:
+				if isInternalType(t.Obj()) {
+					report(t)
+					return
+				}
+				for i := 0; i < t.TypeArgs().Len(); i++ {
+					walk(t.TypeArgs().At(i))
+				}
+			case *types.Pointer:
+				walk(t.Elem())
+			case *types.Slice:
+				walk(t.Elem())
+			case *types.Array:
+				walk(t.Elem())
+			case *types.Chan:
+				walk(t.Elem())
+			case *types.Map:
+				walk(t.Key())
+				walk(t.Elem())
+			case *types.Signature:
+				for i := 0; i < t.Params().Len(); i++ {
+					walk(t.Params().At(i).Type())
+				}
+				for i := 0; i < t.Results().Len(); i++ {
+					walk(t.Results().At(i).Type())
+				}
+			case *types.Struct:
+				for i := 0; i < t.NumFields(); i++ {
+					if f := t.Field(i); f.Exported() {
+						walk(f.Type())
+					}
+				}
+			case *types.Interface:
+				for i := 0; i < t.NumExplicitMethods(); i++ {
+					if m := t.ExplicitMethod(i); m.Exported() {
+						walk(m.Type())
+					}
+				}
+				for i := 0; i < t.NumEmbeddeds(); i++ {
+					walk(t.EmbeddedType(i))
+				}
+			}
+		}
+
+		scope := pkg.Types.Scope()
+		for _, n := range scope.Names() {
+			obj := scope.Lookup(n)
+			if !obj.Exported() {
+				continue
+			}
+			name = n
+			tn, ok := obj.(*types.TypeName)
+			if !ok {
+				walk(obj.Type())
+				continue
+			}
+			named, ok := tn.Type().(*types.Named)
+			if !ok || tn.IsAlias() {
+				// An exported alias makes the aliased type nameable, even if
+				// it is defined in an internal package.
+				continue
+			}
+			walk(named.Underlying())
+			for i := 0; i < named.NumMethods(); i++ {
+				if m := named.Method(i); m.Exported() {
+					name = n + "." + m.Name()
+					walk(m.Type())
+				}
+			}
+		}
+	}
+	return warnings
+}
+
 // existingVersions returns the versions that already exist for the given
 // modPath.
 func existingVersions(ctx context.Context, modPath, modRoot string) (versions []string, err error) {
@@ -34,6 +34,11 @@ type report struct {
 	// versionInvalid explains why the proposed or suggested version is not valid.
 	versionInvalid *versionMessage
 
+	// warnings describe likely mistakes in the release version that don't
+	// prevent it from being released, such as exported declarations that
+	// refer to types in internal packages.
+	warnings []string
+
 	// haveCompatibleChanges is true if there are any backward-compatible
 	// changes in non-internal packages.
 	haveCompatibleChanges bool
@@ -73,6 +78,14 @@ func (r *report) String() string {
 		buf.WriteByte('\n')
 	}
 
+	if len(r.warnings) > 0 {
+		buf.WriteString("# warnings\n")
+		for _, w := range r.warnings {
+			fmt.Fprintln(buf, w)
+		}
+		buf.WriteByte('\n')
+	}
+
 	buf.WriteString("# summary\n")
 	baseVersion := r.base.version
 	if r.base.modPath != r.release.modPath {
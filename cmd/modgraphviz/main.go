@@ -10,13 +10,31 @@
 //	go mod graph | modgraphviz > graph.dot
 //	go mod graph | modgraphviz | dot -Tpng -o graph.png
 //
-// Modgraphviz takes no options or arguments; it reads a graph in the format
+// Modgraphviz takes no arguments; it reads a graph in the format
 // generated by “go mod graph” on standard input and writes DOT language
 // on standard output.
 //
 // For each module, the node representing the greatest version (i.e., the
 // version chosen by Go's minimal version selection algorithm) is colored green.
 // Other nodes, which aren't in the final build list, are colored grey.
+// The main module, which is the source of the first edge, is colored light blue.
+//
+// The -highlight flag names a node, in path@version form, to be colored
+// yellow so that it stands out in a large graph.
+//
+// The -collapse flag merges all versions of a module into a single node
+// named by the module path, and removes the duplicate edges that result.
+// Nodes that stand for more than one version are labeled with the number
+// of versions seen. As a collapsed graph has no versions to choose between,
+// its nodes are not colored green or grey. The go@version and
+// toolchain@version nodes are not modules, and are left as they are.
+//
+// The -gomod flag names the main module's go.mod file. If it declares go 1.17
+// or later, module graph pruning applies (see
+// https://go.dev/ref/mod#graph-pruning), and edges whose source module's
+// requirements are pruned out of the graph are drawn grey and dashed. The go
+// version of each dependency is taken from the go@version nodes that
+// “go mod graph” prints, so this requires output from Go 1.21 or later.
 //
 // See http://www.graphviz.org/doc/info/lang.html for details of the DOT language
 // and http://www.graphviz.org/about/ for Graphviz itself.
@@ -30,22 +48,35 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"go/version"
 	"io"
 	"log"
 	"os"
 	"sort"
 	"strings"
 
+	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/semver"
 )
 
+var (
+	highlight = flag.String("highlight", "", "color the node `path@version`")
+	collapse  = flag.Bool("collapse", false, "merge all versions of a module into one node")
+	gomod     = flag.String("gomod", "", "dim edges pruned from the graph of the main module with go.mod `file`")
+)
+
 func usage() {
-	fmt.Fprintf(os.Stderr, `Usage: go mod graph | modgraphviz | dot -Tpng -o graph.png
+	fmt.Fprintf(os.Stderr, `Usage: go mod graph | modgraphviz [-collapse] [-gomod file] [-highlight path@version] | dot -Tpng -o graph.png
 
 For each module, the node representing the greatest version (i.e., the
 version chosen by Go's minimal version selection algorithm) is colored green.
 Other nodes, which aren't in the final build list, are colored grey.
+The main module is colored light blue and the -highlight node yellow.
+With -collapse, there is one node per module path.
+With -gomod, edges removed by module graph pruning are grey and dashed.
+
 `)
+	flag.PrintDefaults()
 	os.Exit(2)
 }
 
@@ -69,26 +100,69 @@ func modgraphviz(in io.Reader, out io.Writer) error {
 	if err != nil {
 		return err
 	}
+	if *gomod != "" {
+		data, err := os.ReadFile(*gomod)
+		if err != nil {
+			return err
+		}
+		f, err := modfile.ParseLax(*gomod, data, nil)
+		if err != nil {
+			return err
+		}
+		if f.Go != nil {
+			graph.markPruned(f.Go.Version)
+		}
+	}
+	hl := *highlight
+	if *collapse {
+		graph = graph.collapse()
+		hl = collapsedNode(hl)
+	}
+	if hl != "" && !graph.hasNode(hl) {
+		return fmt.Errorf("-highlight: %s is not in the graph", *highlight)
+	}
 
 	fmt.Fprintf(out, "digraph gomodgraph {\n")
 	fmt.Fprintf(out, "\tnode [ shape=rectangle fontsize=12 ]\n")
 	out.Write(graph.edgesAsDOT())
 	for _, n := range graph.mvsPicked {
-		fmt.Fprintf(out, "\t%q [style = filled, fillcolor = green]\n", n)
+		if n != graph.root && n != hl {
+			fmt.Fprintf(out, "\t%q [style = filled, fillcolor = green]\n", n)
+		}
 	}
 	for _, n := range graph.mvsUnpicked {
-		fmt.Fprintf(out, "\t%q [style = filled, fillcolor = gray]\n", n)
+		if n != graph.root && n != hl {
+			fmt.Fprintf(out, "\t%q [style = filled, fillcolor = gray]\n", n)
+		}
+	}
+	for _, n := range graph.multiVersion {
+		fmt.Fprintf(out, "\t%q [label = \"%s\\n%d versions\"]\n", n, n, graph.versions[n])
+	}
+	if graph.root != "" && graph.root != hl {
+		fmt.Fprintf(out, "\t%q [style = filled, fillcolor = lightblue]\n", graph.root)
+	}
+	if hl != "" {
+		fmt.Fprintf(out, "\t%q [style = filled, fillcolor = yellow]\n", hl)
 	}
 	fmt.Fprintf(out, "}\n")
 
 	return nil
 }
 
-type edge struct{ from, to string }
+type edge struct {
+	from, to string
+	pruned   bool // the requirements of from are pruned out of the graph
+}
 type graph struct {
+	root        string // the main module
 	edges       []edge
 	mvsPicked   []string
 	mvsUnpicked []string
+
+	// For collapsed graphs, the number of versions merged into each node,
+	// and the sorted nodes that have more than one.
+	versions     map[string]int
+	multiVersion []string
 }
 
 // convert reads “go mod graph” output from r and returns a graph, recording
@@ -110,6 +184,9 @@ func convert(r io.Reader) (*graph, error) {
 		}
 		from := parts[0]
 		to := parts[1]
+		if g.edges == nil {
+			g.root = from
+		}
 		g.edges = append(g.edges, edge{from: from, to: to})
 
 		for _, node := range []string{from, to} {
@@ -156,11 +233,129 @@ func convert(r io.Reader) (*graph, error) {
 	return &g, nil
 }
 
+// collapse returns a graph with each module node replaced by its module path.
+// Duplicate edges, and edges from a module to itself, are dropped.
+func (g *graph) collapse() *graph {
+	c := &graph{
+		root:     collapsedNode(g.root),
+		versions: map[string]int{},
+	}
+	seenEdge := map[edge]int{} // unpruned edge -> index in c.edges
+	seenNode := map[string]bool{}
+	for _, e := range g.edges {
+		ce := edge{from: collapsedNode(e.from), to: collapsedNode(e.to)}
+		for _, n := range []string{e.from, e.to} {
+			if !seenNode[n] {
+				seenNode[n] = true
+				c.versions[collapsedNode(n)]++
+			}
+		}
+		if ce.from == ce.to {
+			continue
+		}
+		// A collapsed edge is pruned only if all the edges it merges are.
+		if i, ok := seenEdge[ce]; ok {
+			c.edges[i].pruned = c.edges[i].pruned && e.pruned
+			continue
+		}
+		seenEdge[ce] = len(c.edges)
+		ce.pruned = e.pruned
+		c.edges = append(c.edges, ce)
+	}
+	for n, v := range c.versions {
+		if v > 1 {
+			c.multiVersion = append(c.multiVersion, n)
+		}
+	}
+	sort.Strings(c.multiVersion)
+	return c
+}
+
+// markPruned marks the edges that are not part of the module graph when
+// graph pruning is in effect for a main module at version mainGo of the Go
+// language.
+//
+// The requirements of the main module, and of the modules it reaches through
+// modules at go versions before 1.17, are part of the graph. Modules at go
+// 1.17 or later contribute their own requirements, but not those of the
+// modules they require.
+func (g *graph) markPruned(mainGo string) {
+	if !prunes(mainGo) {
+		return
+	}
+	goVersion := map[string]string{} // node -> its go version
+	out := map[string][]string{}     // node -> the nodes it requires
+	for _, e := range g.edges {
+		if v, ok := strings.CutPrefix(e.to, "go@"); ok {
+			goVersion[e.from] = v
+		}
+		out[e.from] = append(out[e.from], e.to)
+	}
+
+	loaded := map[string]bool{g.root: true}
+	queue := []string{g.root}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		if n != g.root && prunes(goVersion[n]) {
+			continue
+		}
+		for _, to := range out[n] {
+			if !loaded[to] {
+				loaded[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	for i, e := range g.edges {
+		g.edges[i].pruned = !loaded[e.from]
+	}
+}
+
+// prunes reports whether a go.mod file at version v of the Go language
+// supports module graph pruning.
+func prunes(v string) bool {
+	return v != "" && version.Compare("go"+v, "go1.17") >= 0
+}
+
+// modulePath returns node without its @version suffix, if any.
+func modulePath(node string) string {
+	if i := strings.IndexByte(node, '@'); i >= 0 {
+		return node[:i]
+	}
+	return node
+}
+
+// collapsedNode returns the node that node is merged into by collapse: its
+// module path. The go@version and toolchain@version nodes, which stand for
+// the go and toolchain lines of a go.mod file rather than for modules, are
+// kept as they are.
+func collapsedNode(node string) string {
+	if strings.HasPrefix(node, "go@") || strings.HasPrefix(node, "toolchain@") {
+		return node
+	}
+	return modulePath(node)
+}
+
+// hasNode reports whether node is the source or target of an edge in g.
+func (g *graph) hasNode(node string) bool {
+	for _, e := range g.edges {
+		if e.from == node || e.to == node {
+			return true
+		}
+	}
+	return false
+}
+
 // edgesAsDOT returns the edges in DOT notation.
 func (g *graph) edgesAsDOT() []byte {
 	var buf bytes.Buffer
 	for _, e := range g.edges {
-		fmt.Fprintf(&buf, "\t%q -> %q\n", e.from, e.to)
+		if e.pruned {
+			fmt.Fprintf(&buf, "\t%q -> %q [color = gray, style = dashed]\n", e.from, e.to)
+		} else {
+			fmt.Fprintf(&buf, "\t%q -> %q\n", e.from, e.to)
+		}
 	}
 	return buf.Bytes()
 }
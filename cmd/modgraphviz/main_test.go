@@ -7,7 +7,11 @@ package main
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -26,10 +30,10 @@ test.com/B@v1.0.0 test.com/C@v4.5.6
 	node [ shape=rectangle fontsize=12 ]
 	"test.com/A@v1.0.0" -> "test.com/B@v1.2.3"
 	"test.com/B@v1.0.0" -> "test.com/C@v4.5.6"
-	"test.com/A@v1.0.0" [style = filled, fillcolor = green]
 	"test.com/B@v1.2.3" [style = filled, fillcolor = green]
 	"test.com/C@v4.5.6" [style = filled, fillcolor = green]
 	"test.com/B@v1.0.0" [style = filled, fillcolor = gray]
+	"test.com/A@v1.0.0" [style = filled, fillcolor = lightblue]
 }
 `
 	if gotGraph != wantGraph {
@@ -37,6 +41,175 @@ test.com/B@v1.0.0 test.com/C@v4.5.6
 	}
 }
 
+func TestHighlight(t *testing.T) {
+	defer func(h string) { *highlight = h }(*highlight)
+	*highlight = "test.com/B@v1.0.0"
+
+	out := &bytes.Buffer{}
+	in := bytes.NewBuffer([]byte(`
+example.com/main test.com/B@v1.2.3
+example.com/main test.com/C@v1.0.0
+test.com/C@v1.0.0 test.com/B@v1.0.0
+`))
+	if err := modgraphviz(in, out); err != nil {
+		t.Fatal(err)
+	}
+
+	gotGraph := string(out.Bytes())
+	wantGraph := `digraph gomodgraph {
+	node [ shape=rectangle fontsize=12 ]
+	"example.com/main" -> "test.com/B@v1.2.3"
+	"example.com/main" -> "test.com/C@v1.0.0"
+	"test.com/C@v1.0.0" -> "test.com/B@v1.0.0"
+	"test.com/B@v1.2.3" [style = filled, fillcolor = green]
+	"test.com/C@v1.0.0" [style = filled, fillcolor = green]
+	"example.com/main" [style = filled, fillcolor = lightblue]
+	"test.com/B@v1.0.0" [style = filled, fillcolor = yellow]
+}
+`
+	if gotGraph != wantGraph {
+		t.Fatalf("\ngot: %s\nwant: %s", gotGraph, wantGraph)
+	}
+
+	*highlight = "test.com/D@v1.0.0"
+	in = bytes.NewBuffer([]byte("example.com/main test.com/B@v1.2.3\n"))
+	if err := modgraphviz(in, io.Discard); err == nil {
+		t.Errorf("got no error for a -highlight node that is not in the graph")
+	}
+}
+
+func TestCollapse(t *testing.T) {
+	defer func(c bool) { *collapse = c }(*collapse)
+	*collapse = true
+
+	out := &bytes.Buffer{}
+	in := bytes.NewBuffer([]byte(`
+example.com/main test.com/A@v1.0.0
+example.com/main test.com/B@v1.2.3
+test.com/A@v1.0.0 test.com/B@v1.0.0
+test.com/A@v1.0.0 test.com/C@v1.0.0
+test.com/B@v1.0.0 test.com/C@v1.0.0
+test.com/B@v1.2.3 test.com/C@v1.1.0
+test.com/B@v1.2.3 test.com/B@v1.0.0
+`))
+	if err := modgraphviz(in, out); err != nil {
+		t.Fatal(err)
+	}
+
+	gotGraph := string(out.Bytes())
+	wantGraph := `digraph gomodgraph {
+	node [ shape=rectangle fontsize=12 ]
+	"example.com/main" -> "test.com/A"
+	"example.com/main" -> "test.com/B"
+	"test.com/A" -> "test.com/B"
+	"test.com/A" -> "test.com/C"
+	"test.com/B" -> "test.com/C"
+	"test.com/B" [label = "test.com/B\n2 versions"]
+	"test.com/C" [label = "test.com/C\n2 versions"]
+	"example.com/main" [style = filled, fillcolor = lightblue]
+}
+`
+	if gotGraph != wantGraph {
+		t.Fatalf("\ngot: %s\nwant: %s", gotGraph, wantGraph)
+	}
+}
+
+func TestCollapseToolchain(t *testing.T) {
+	defer func(c bool, g string) { *collapse, *gomod = c, g }(*collapse, *gomod)
+	*collapse = true
+	*gomod = filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(*gomod, []byte("module example.com/main\n\ngo 1.21\n\ntoolchain go1.21.3\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	// The go and toolchain nodes are not modules, so their versions are not
+	// merged.
+	out := &bytes.Buffer{}
+	in := strings.NewReader(`
+example.com/main go@1.21
+example.com/main toolchain@go1.21.3
+example.com/main test.com/A@v1.0.0
+test.com/A@v1.0.0 go@1.20
+test.com/A@v1.0.0 toolchain@go1.21.0
+`)
+	if err := modgraphviz(in, out); err != nil {
+		t.Fatal(err)
+	}
+
+	gotGraph := out.String()
+	wantGraph := `digraph gomodgraph {
+	node [ shape=rectangle fontsize=12 ]
+	"example.com/main" -> "go@1.21"
+	"example.com/main" -> "toolchain@go1.21.3"
+	"example.com/main" -> "test.com/A"
+	"test.com/A" -> "go@1.20"
+	"test.com/A" -> "toolchain@go1.21.0"
+	"example.com/main" [style = filled, fillcolor = lightblue]
+}
+`
+	if gotGraph != wantGraph {
+		t.Fatalf("\ngot: %s\nwant: %s", gotGraph, wantGraph)
+	}
+}
+
+func TestPruned(t *testing.T) {
+	defer func(g string) { *gomod = g }(*gomod)
+	*gomod = filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(*gomod, []byte("module example.com/main\n\ngo 1.21\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	// A is pruned, so the requirements of B, which A requires, are not part
+	// of the graph. D is not pruned, so the requirements of E are.
+	graph := `
+example.com/main go@1.21
+example.com/main test.com/A@v1.0.0
+example.com/main test.com/D@v1.0.0
+test.com/A@v1.0.0 go@1.17
+test.com/A@v1.0.0 test.com/B@v1.0.0
+test.com/B@v1.0.0 test.com/C@v1.0.0
+test.com/D@v1.0.0 go@1.16
+test.com/D@v1.0.0 test.com/E@v1.0.0
+test.com/E@v1.0.0 test.com/F@v1.0.0
+`
+	out := &bytes.Buffer{}
+	if err := modgraphviz(strings.NewReader(graph), out); err != nil {
+		t.Fatal(err)
+	}
+
+	gotGraph := string(out.Bytes())
+	wantGraph := `digraph gomodgraph {
+	node [ shape=rectangle fontsize=12 ]
+	"example.com/main" -> "go@1.21"
+	"example.com/main" -> "test.com/A@v1.0.0"
+	"example.com/main" -> "test.com/D@v1.0.0"
+	"test.com/A@v1.0.0" -> "go@1.17"
+	"test.com/A@v1.0.0" -> "test.com/B@v1.0.0"
+	"test.com/B@v1.0.0" -> "test.com/C@v1.0.0" [color = gray, style = dashed]
+	"test.com/D@v1.0.0" -> "go@1.16"
+	"test.com/D@v1.0.0" -> "test.com/E@v1.0.0"
+	"test.com/E@v1.0.0" -> "test.com/F@v1.0.0"
+`
+	if !strings.HasPrefix(gotGraph, wantGraph) {
+		t.Fatalf("\ngot: %s\nwant prefix: %s", gotGraph, wantGraph)
+	}
+
+	// Before go 1.17, nothing is pruned.
+	g, err := convert(strings.NewReader(graph))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.edges) == 0 {
+		t.Fatal("no edges")
+	}
+	g.markPruned("1.16")
+	for _, e := range g.edges {
+		if e.pruned {
+			t.Errorf("edge %s -> %s is pruned at go 1.16", e.from, e.to)
+		}
+	}
+}
+
 func TestMVSPicking(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
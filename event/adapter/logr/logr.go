@@ -64,7 +64,7 @@ func (l *logSink) Error(err error, msg string, keysAndValues ...interface{}) {
 		return
 	}
 	ev := l.ev.Clone()
-	ev.Labels = append(ev.Labels, event.Value("error", err))
+	ev.Labels = append(ev.Labels, event.Value(event.ErrorKey, err))
 	l.log(ev, msg, keysAndValues)
 }
 
@@ -0,0 +1,110 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+// Package slog provides an event handler that forwards log events to a
+// slog.Handler.
+// To send the events from a context to a slog handler:
+//
+//	h := slog.NewHandler(slog.NewTextHandler(os.Stderr, nil))
+//	ctx = event.WithExporter(ctx, event.NewExporter(h, nil))
+package slog
+
+import (
+	"context"
+
+	"golang.org/x/exp/event"
+	"golang.org/x/exp/event/severity"
+	"golang.org/x/exp/slog"
+)
+
+// errKey is the conventional slog attribute key for an error.
+const errKey = "err"
+
+// Handler is an event.Handler that delivers log events to a slog.Handler.
+// Events of any other kind are ignored.
+type Handler struct {
+	h slog.Handler
+}
+
+// NewHandler returns a handler that forwards log events to h.
+func NewHandler(h slog.Handler) *Handler {
+	return &Handler{h: h}
+}
+
+// Event implements event.Handler.
+//
+// The severity label of the event, if any, sets the level of the record.
+// The severity levels are mapped so that severity.Debug, severity.Info,
+// severity.Warning and severity.Error become the slog levels of the same
+// name. Events without a severity are logged at slog.LevelError if they
+// have an event.ErrorKey label, as those from event.Error do, and at
+// slog.LevelInfo otherwise.
+// The "msg" label becomes the message of the record and all other labels
+// are added as attributes. The event.ErrorKey label is added with the key
+// "err", following the slog convention for errors.
+func (h *Handler) Event(ctx context.Context, ev *event.Event) context.Context {
+	if ev.Kind != event.LogKind {
+		return ctx
+	}
+	level := slog.LevelInfo
+	if l := ev.Find(severity.Key); l.HasValue() {
+		if s, ok := l.Interface().(severity.Level); ok {
+			level = Level(s)
+		}
+	} else if ev.Find(event.ErrorKey).HasValue() {
+		level = slog.LevelError
+	}
+	if !h.h.Enabled(ctx, level) {
+		return ctx
+	}
+	var msg string
+	if l := ev.Find("msg"); l.HasValue() {
+		msg = l.String()
+	}
+	r := slog.NewRecord(ev.At, level, msg, 0)
+	for _, l := range ev.Labels {
+		switch l.Name {
+		case "", "msg", severity.Key:
+			continue
+		}
+		a := Attr(l)
+		if l.Name == event.ErrorKey {
+			a.Key = errKey
+		}
+		r.AddAttrs(a)
+	}
+	h.h.Handle(ctx, r)
+	return ctx
+}
+
+// Level converts a severity level to the corresponding slog level.
+func Level(l severity.Level) slog.Level {
+	return slog.Level(int(l) - int(severity.Info))
+}
+
+// Attr converts a label to a slog attribute.
+func Attr(l event.Label) slog.Attr {
+	switch {
+	case !l.HasValue():
+		return slog.Any(l.Name, nil)
+	case l.IsString():
+		return slog.String(l.Name, l.String())
+	case l.IsBytes():
+		return slog.String(l.Name, string(l.Bytes()))
+	case l.IsInt64():
+		return slog.Int64(l.Name, l.Int64())
+	case l.IsUint64():
+		return slog.Uint64(l.Name, l.Uint64())
+	case l.IsFloat64():
+		return slog.Float64(l.Name, l.Float64())
+	case l.IsBool():
+		return slog.Bool(l.Name, l.Bool())
+	case l.IsDuration():
+		return slog.Duration(l.Name, l.Duration())
+	default:
+		return slog.Any(l.Name, l.Interface())
+	}
+}
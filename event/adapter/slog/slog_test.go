@@ -0,0 +1,175 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package slog_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/event"
+	eslog "golang.org/x/exp/event/adapter/slog"
+	"golang.org/x/exp/event/severity"
+	"golang.org/x/exp/slog"
+)
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	th := slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+	ctx := event.WithExporter(context.Background(), event.NewExporter(eslog.NewHandler(th), nil))
+
+	for _, test := range []struct {
+		name string
+		log  func(context.Context)
+		want string
+	}{
+		{
+			name: "simple",
+			log:  func(ctx context.Context) { event.Log(ctx, "a message") },
+			want: `level=INFO msg="a message"`,
+		},
+		{
+			name: "labels",
+			log: func(ctx context.Context) {
+				event.Log(ctx, "m", event.Int64("n", 3), event.String("s", "x"), event.Bool("b", true))
+			},
+			want: `level=INFO msg=m n=3 s=x b=true`,
+		},
+		{
+			name: "severity",
+			log: func(ctx context.Context) {
+				severity.Warning.Log(ctx, "careful", event.Float64("f", 1.5))
+			},
+			want: `level=WARN msg=careful f=1.5`,
+		},
+		{
+			name: "debug",
+			log:  func(ctx context.Context) { severity.Debug.Log(ctx, "d") },
+			want: `level=DEBUG msg=d`,
+		},
+		{
+			name: "trace disabled",
+			log:  func(ctx context.Context) { severity.Trace.Log(ctx, "t") },
+			want: ``,
+		},
+		{
+			name: "error",
+			log: func(ctx context.Context) {
+				event.Error(ctx, "failed", errors.New("boom"), event.Int64("n", 1))
+			},
+			want: `level=ERROR msg=failed n=1 err=boom`,
+		},
+		{
+			name: "error with severity",
+			log: func(ctx context.Context) {
+				event.Error(ctx, "retrying", errors.New("boom"), severity.Warning.Label())
+			},
+			want: `level=WARN msg=retrying err=boom`,
+		},
+		{
+			name: "not a log",
+			log:  func(ctx context.Context) { event.Annotate(ctx, event.String("a", "b")) },
+			want: ``,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf.Reset()
+			test.log(ctx)
+			got := buf.String()
+			if test.want != "" {
+				test.want += "\n"
+			}
+			if got != test.want {
+				t.Errorf("\ngot  %q\nwant %q", got, test.want)
+			}
+		})
+	}
+}
+
+// recordHandler is a slog.Handler that records the Records it handles.
+type recordHandler struct {
+	records []slog.Record
+}
+
+func (h *recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { panic("unimplemented") }
+func (h *recordHandler) WithGroup(string) slog.Handler      { panic("unimplemented") }
+
+func TestErrorStack(t *testing.T) {
+	for _, enable := range []bool{false, true} {
+		rec := &recordHandler{}
+		ctx := event.WithExporter(context.Background(),
+			event.NewExporter(eslog.NewHandler(rec), &event.ExporterOptions{EnableErrorStacks: enable}))
+		err := errors.New("boom")
+		event.Error(ctx, "failed", err)
+
+		if len(rec.records) != 1 {
+			t.Fatalf("EnableErrorStacks=%t: got %d records, want 1", enable, len(rec.records))
+		}
+		r := rec.records[0]
+		if r.Level != slog.LevelError || r.Message != "failed" {
+			t.Errorf("EnableErrorStacks=%t: got level %v, message %q, want ERROR, \"failed\"", enable, r.Level, r.Message)
+		}
+		var gotErr any
+		var stack string
+		r.Attrs(func(a slog.Attr) bool {
+			switch a.Key {
+			case "err":
+				gotErr = a.Value.Any()
+			case event.StackKey:
+				stack = a.Value.String()
+			}
+			return true
+		})
+		if gotErr != err {
+			t.Errorf("EnableErrorStacks=%t: got err attribute %v, want %v", enable, gotErr, err)
+		}
+		if !enable {
+			if stack != "" {
+				t.Errorf("EnableErrorStacks=false: got stack %q", stack)
+			}
+			continue
+		}
+		// The innermost frame is the caller of event.Error.
+		if first, _, _ := strings.Cut(stack, "\n"); !strings.Contains(first, "TestErrorStack") {
+			t.Errorf("EnableErrorStacks=true: got stack starting with %q, want TestErrorStack", first)
+		}
+	}
+}
+
+func TestLevel(t *testing.T) {
+	for _, test := range []struct {
+		in   severity.Level
+		want slog.Level
+	}{
+		{severity.Debug, slog.LevelDebug},
+		{severity.Info, slog.LevelInfo},
+		{severity.Warning, slog.LevelWarn},
+		{severity.Error, slog.LevelError},
+		{severity.Error + 1, slog.LevelError + 1},
+	} {
+		if got := eslog.Level(test.in); got != test.want {
+			t.Errorf("Level(%v) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
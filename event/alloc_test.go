@@ -33,3 +33,31 @@ func TestAllocs(t *testing.T) {
 func TestBenchAllocs(t *testing.T) {
 	eventtest.TestAllocs(t, eventPrint, eventLog, 0)
 }
+
+func TestUnsampledTraceAllocs(t *testing.T) {
+	e := event.NewExporter(logfmt.NewHandler(io.Discard), nil)
+	ctx := event.WithExporter(context.Background(), e)
+	ctx = event.WithSampler(ctx, event.NewRatioSampler(0))
+	ctx = event.WithFilter(ctx, func(*event.Event) bool { return true })
+	allocs := int(testing.AllocsPerRun(5, func() {
+		sctx := event.Start(ctx, "outer")
+		event.End(event.Start(sctx, "inner"))
+		event.End(sctx)
+	}))
+	if allocs != 0 {
+		t.Errorf("Got %d allocs, expect 0", allocs)
+	}
+}
+
+func TestSpanNoExporterAllocs(t *testing.T) {
+	ctx := event.WithExporter(context.Background(), nil)
+	allocs := int(testing.AllocsPerRun(5, func() {
+		sctx, outer := event.StartSpan(ctx, "outer", event.Int64("int", 4))
+		_, inner := event.StartSpan(sctx, "inner")
+		inner.End()
+		outer.End()
+	}))
+	if allocs != 0 {
+		t.Errorf("Got %d allocs, expect 0", allocs)
+	}
+}
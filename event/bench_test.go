@@ -93,6 +93,10 @@ func eventPrintSource(w io.Writer) context.Context {
 	return event.WithExporter(context.Background(), event.NewExporter(logfmt.NewHandler(w), opts))
 }
 
+func eventUnsampled() context.Context {
+	return event.WithSampler(eventNoop(), event.NewRatioSampler(0))
+}
+
 type nopHandler struct{}
 
 func (nopHandler) Event(ctx context.Context, _ *event.Event) context.Context { return ctx }
@@ -125,6 +129,10 @@ func BenchmarkEventTraceDiscard(b *testing.B) {
 	eventtest.RunBenchmark(b, eventPrint(io.Discard), eventTrace)
 }
 
+func BenchmarkEventTraceUnsampled(b *testing.B) {
+	eventtest.RunBenchmark(b, eventUnsampled(), eventTrace)
+}
+
 func BenchmarkEventMetricNoop(b *testing.B) {
 	eventtest.RunBenchmark(b, eventNoop(), eventMetric)
 }
@@ -132,3 +140,14 @@ func BenchmarkEventMetricNoop(b *testing.B) {
 func BenchmarkEventMetricDiscard(b *testing.B) {
 	eventtest.RunBenchmark(b, eventPrint(io.Discard), eventMetric)
 }
+
+func BenchmarkSpanNoExporter(b *testing.B) {
+	ctx := eventNoExporter()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		actx, a := event.StartSpan(ctx, eventtest.A.Msg, event.Int64(eventtest.A.Name, int64(i)))
+		_, s := event.StartSpan(actx, eventtest.B.Msg, event.String(eventtest.B.Name, "b"))
+		s.End()
+		a.End()
+	}
+}
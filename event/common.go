@@ -10,6 +10,14 @@ import (
 	"sync"
 )
 
+const (
+	// ErrorKey is the name of the label in which Error records the error.
+	ErrorKey = "error"
+	// StackKey is the name of the label in which Error records a Stack, if
+	// the exporter has error stacks enabled.
+	StackKey = "stack"
+)
+
 const (
 	MetricKey      = interfaceKey("metric")
 	MetricVal      = "metricValue"
@@ -94,11 +102,18 @@ func Logf(ctx context.Context, msg string, args ...interface{}) {
 	}
 }
 
+// Error delivers a log event for err, with the given message and labels.
+// The error is recorded in a label named ErrorKey. If the exporter has
+// error stacks enabled, the call stack of the caller of Error is recorded
+// in a label named StackKey.
 func Error(ctx context.Context, msg string, err error, labels ...Label) {
 	ev := New(ctx, LogKind)
 	if ev != nil {
 		ev.Labels = append(ev.Labels, labels...)
-		ev.Labels = append(ev.Labels, String("msg", msg), Value("error", err))
+		ev.Labels = append(ev.Labels, String("msg", msg), Value(ErrorKey, err))
+		if ev.target.exporter.opts.EnableErrorStacks {
+			ev.Labels = append(ev.Labels, Value(StackKey, captureStack(0)))
+		}
 		ev.Deliver()
 	}
 }
@@ -114,25 +129,55 @@ func Annotate(ctx context.Context, labels ...Label) {
 func Start(ctx context.Context, name string, labels ...Label) context.Context {
 	ev := New(ctx, StartKind)
 	if ev != nil {
+		if sctx, ok := ev.target.sample(ctx, name); !ok {
+			eventPool.Put(ev)
+			return sctx
+		}
 		ev.Labels = append(ev.Labels, String("name", name))
 		ev.Labels = append(ev.Labels, labels...)
+		// Filter before tracing, so that a rejected span is not given an ID
+		// and its context does not become the parent of later events.
+		if !ev.accept() {
+			ctx = ev.target.filterSpan(ctx)
+			eventPool.Put(ev)
+			return ctx
+		}
 		ev.Trace()
-		ctx = ev.Deliver()
+		ctx = ev.send()
 	}
 	return ctx
 }
 
 func End(ctx context.Context, labels ...Label) {
+	end(ctx, labels, false)
+}
+
+// end delivers an end event. If withDuration is set, the time since the
+// span started is added in a label named DurationKey.
+func end(ctx context.Context, labels []Label, withDuration bool) {
 	ev := New(ctx, EndKind)
 	if ev != nil {
+		if ev.target.dropEnd {
+			eventPool.Put(ev)
+			return
+		}
 		ev.Labels = append(ev.Labels, labels...)
+		// The end of a delivered span is always delivered, so the filter is
+		// only consulted outside of spans.
+		if ev.target.parent == 0 && !ev.accept() {
+			eventPool.Put(ev)
+			return
+		}
 		ev.prepare()
+		if withDuration {
+			ev.Labels = append(ev.Labels, Duration(DurationKey, ev.At.Sub(ev.target.startTime)))
+		}
 		// this was an end event, do we need to send a duration?
 		if v, ok := DurationMetric.Find(ev); ok {
 			//TODO: do we want the rest of the values from the end event?
 			v.(*DurationDistribution).Record(ctx, ev.At.Sub(ev.target.startTime))
 		}
-		ev.Deliver()
+		ev.send()
 	}
 }
 
@@ -8,6 +8,7 @@ package event
 
 import (
 	"context"
+	"runtime"
 	"time"
 )
 
@@ -38,3 +39,18 @@ func newContext(ctx context.Context, exporter *Exporter, parent uint64) context.
 func FromContext(ctx context.Context) *Target { return nil }
 
 func setDefaultExporter(e *Exporter) {}
+
+const DurationKey = "duration"
+
+type Span struct{}
+
+func StartSpan(ctx context.Context, name string, labels ...Label) (context.Context, Span) {
+	return ctx, Span{}
+}
+func (s Span) End(labels ...Label) {}
+
+type Stack []uintptr
+
+func captureStack(skip int) Stack       { return nil }
+func (s Stack) Frames() *runtime.Frames { return runtime.CallersFrames(s) }
+func (s Stack) String() string          { return "" }
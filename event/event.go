@@ -44,7 +44,7 @@ var eventPool = sync.Pool{New: func() interface{} { return &Event{} }}
 // The exporter is called synchronously from the event call site, so it should
 // return quickly so as not to hold up user code.
 func WithExporter(ctx context.Context, e *Exporter) context.Context {
-	return newContext(ctx, e, 0, time.Time{})
+	return newContext(ctx, e, 0, time.Time{}, nil)
 }
 
 // SetDefaultExporter sets an exporter that is used if no exporter can be
@@ -60,12 +60,7 @@ func SetDefaultExporter(e *Exporter) {
 // Deliver will exhaust the pool and cause allocations.
 // It returns nil if there is no active exporter for this kind of event.
 func New(ctx context.Context, kind Kind) *Event {
-	var t *target
-	if v, ok := ctx.Value(contextKey).(*target); ok {
-		t = v
-	} else {
-		t = getDefaultTarget()
-	}
+	t := lookupTarget(ctx)
 	if t == nil {
 		return nil
 	}
@@ -80,7 +75,7 @@ func New(ctx context.Context, kind Kind) *Event {
 			return nil
 		}
 	case StartKind, EndKind:
-		if !t.exporter.tracingEnabled() {
+		if !t.exporter.tracingEnabled() || t.dropSpans {
 			return nil
 		}
 	}
@@ -106,13 +101,24 @@ func (ev *Event) Clone() *Event {
 
 func (ev *Event) Trace() {
 	ev.prepare()
-	ev.ctx = newContext(ev.ctx, ev.target.exporter, ev.ID, ev.At)
+	ev.ctx = newContext(ev.ctx, ev.target.exporter, ev.ID, ev.At, ev.target.filter)
 }
 
 // Deliver the event to the exporter that was found in New.
 // This also returns the event to the pool, it is an error to do anything
 // with the event after it is delivered.
 func (ev *Event) Deliver() context.Context {
+	if !ev.accept() {
+		ctx := ev.ctx
+		eventPool.Put(ev)
+		return ctx
+	}
+	return ev.send()
+}
+
+// send delivers the event without consulting the filter, and returns it to
+// the pool.
+func (ev *Event) send() context.Context {
 	// get the event ready to send
 	ev.prepare()
 	ctx := ev.deliver()
@@ -120,6 +126,13 @@ func (ev *Event) Deliver() context.Context {
 	return ctx
 }
 
+// accept reports whether the filter of the event's target, if any, accepts
+// the event.
+func (ev *Event) accept() bool {
+	f := ev.target.filter
+	return f == nil || f(ev)
+}
+
 func (ev *Event) deliver() context.Context {
 	// hold the lock while we deliver the event
 	e := ev.target.exporter
@@ -30,6 +30,19 @@ type target struct {
 	exporter  *Exporter
 	parent    uint64
 	startTime time.Time // for trace latency
+
+	sampler   Sampler // if non-nil, decides whether root spans are delivered
+	unsampled *target // bound to contexts within spans rejected by sampler
+	dropSpans bool    // set within an unsampled span
+
+	// home is the context t was installed in by WithSampler or WithFilter,
+	// and unsampledHome is home within a span rejected by sampler. They let
+	// rejecting a span started from home reuse a context.
+	home          context.Context
+	unsampledHome context.Context
+
+	filter  func(*Event) bool // if non-nil, decides whether events are delivered
+	dropEnd bool              // set within a span rejected by filter
 }
 
 type ExporterOptions struct {
@@ -45,6 +58,10 @@ type ExporterOptions struct {
 	// Enable automatically setting the event Namespace to the calling package's
 	// import path.
 	EnableNamespaces bool
+
+	// Enable recording the call stack in events delivered by Error. This is
+	// off by default because capturing the stack is expensive.
+	EnableErrorStacks bool
 }
 
 // contextKeyType is used as the key for storing a contextValue on the context.
@@ -83,10 +100,19 @@ func getDefaultTarget() *target {
 	return (*target)(atomic.LoadPointer(&defaultTarget))
 }
 
-func newContext(ctx context.Context, exporter *Exporter, parent uint64, start time.Time) context.Context {
+// lookupTarget returns the target bound to ctx, or the default target if
+// there is none.
+func lookupTarget(ctx context.Context) *target {
+	if v, ok := ctx.Value(contextKey).(*target); ok {
+		return v
+	}
+	return getDefaultTarget()
+}
+
+func newContext(ctx context.Context, exporter *Exporter, parent uint64, start time.Time, filter func(*Event) bool) context.Context {
 	var t *target
 	if exporter != nil {
-		t = &target{exporter: exporter, parent: parent, startTime: start}
+		t = &target{exporter: exporter, parent: parent, startTime: start, filter: filter}
 	}
 	return context.WithValue(ctx, contextKey, t)
 }
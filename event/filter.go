@@ -0,0 +1,54 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event
+
+import "context"
+
+// WithFilter returns a context in which only the events for which f returns
+// true are delivered to the exporter. Unlike sampling, filtering is decided by
+// the content of each event, usually its labels.
+//
+// The filter applies to the exporter that is in effect for ctx, so it must be
+// installed after WithExporter. If there is no exporter, ctx is returned
+// unchanged. If ctx already has a filter, events must pass both filters.
+//
+// f is called synchronously for every event, before the event is given an ID
+// or a timestamp, so it should return quickly and must not retain ev.
+//
+// f decides whether a span is delivered from its start event. If it rejects
+// the start event, the span is not given an ID and its end event is dropped.
+// Events and spans within a rejected span belong to the enclosing span, and
+// are delivered if f accepts them. If f accepts the start event, the end
+// event is always delivered, whatever its labels.
+func WithFilter(ctx context.Context, f func(ev *Event) bool) context.Context {
+	t := lookupTarget(ctx)
+	if t == nil {
+		return ctx
+	}
+	if prev := t.filter; prev != nil {
+		next := f
+		f = func(ev *Event) bool { return prev(ev) && next(ev) }
+	}
+	t2 := *t
+	t2.filter = f
+	if t.unsampled != nil {
+		u := *t.unsampled
+		u.filter = f
+		t2.unsampled = &u
+	}
+	return t2.install(ctx)
+}
+
+// filterSpan returns a context for work within a span whose start event was
+// rejected by the filter of t. Events in that context belong to the
+// enclosing span, and End drops the rejected span's end event.
+func (t *target) filterSpan(ctx context.Context) context.Context {
+	t2 := *t
+	t2.dropEnd = true
+	t2.home, t2.unsampledHome = nil, nil
+	return context.WithValue(ctx, contextKey, &t2)
+}
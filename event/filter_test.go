@@ -0,0 +1,128 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event_test
+
+import (
+	"testing"
+
+	"golang.org/x/exp/event"
+	"golang.org/x/exp/event/eventtest"
+)
+
+func TestFilter(t *testing.T) {
+	ctx, h := eventtest.NewCapture()
+	ctx = event.WithFilter(ctx, func(ev *event.Event) bool {
+		return ev.Find("component").String() == "db"
+	})
+	db, http := event.String("component", "db"), event.String("component", "http")
+
+	event.Log(ctx, "query", db)
+	event.Log(ctx, "request", http)
+	event.Log(ctx, "unlabeled")
+	// The filter applies within spans too.
+	sctx := event.Start(ctx, "transaction", db)
+	event.Log(sctx, "commit", db)
+	event.Log(sctx, "response", http)
+	event.End(sctx, db)
+
+	var got []string
+	for _, ev := range h.Got {
+		s := ev.Kind.String()
+		for _, name := range []string{"msg", "name"} {
+			if l := ev.Find(name); l.Name != "" {
+				s += " " + l.String()
+			}
+		}
+		got = append(got, s)
+	}
+	want := []string{"log query", "start transaction", "log commit", "end"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+	// Filtered events are dropped before they are given an ID.
+	for i, ev := range h.Got {
+		if ev.ID != uint64(i+1) {
+			t.Errorf("event %d: got ID %d, want %d", i, ev.ID, i+1)
+		}
+	}
+}
+
+func TestFilterComposes(t *testing.T) {
+	ctx, h := eventtest.NewCapture()
+	ctx = event.WithFilter(ctx, func(ev *event.Event) bool {
+		return ev.Find("component").String() == "db"
+	})
+	ctx = event.WithFilter(ctx, func(ev *event.Event) bool {
+		return ev.Find("msg").String() != "noisy"
+	})
+	event.Log(ctx, "query", event.String("component", "db"))
+	event.Log(ctx, "noisy", event.String("component", "db"))
+	event.Log(ctx, "request", event.String("component", "http"))
+	if len(h.Got) != 1 || h.Got[0].Find("msg").String() != "query" {
+		t.Errorf("got %v, want only the query event", h.Got)
+	}
+}
+
+func TestFilterSpans(t *testing.T) {
+	ctx, h := eventtest.NewCapture()
+	// Reject events with a "drop" label.
+	ctx = event.WithFilter(ctx, func(ev *event.Event) bool {
+		return ev.Find("drop").Name == ""
+	})
+	drop := event.Bool("drop", true)
+
+	outer := event.Start(ctx, "outer")
+	event.Log(outer, "a")
+	rejected := event.Start(outer, "rejected", drop)
+	event.Log(rejected, "b")
+	inner := event.Start(rejected, "inner")
+	event.Log(inner, "c")
+	// The end of an accepted span is delivered despite its labels.
+	event.End(inner, drop)
+	// The end of a rejected span is dropped.
+	event.End(rejected)
+	event.End(outer)
+	// Outside of spans, end events are filtered like any other.
+	event.End(ctx, drop)
+
+	type delivered struct {
+		s          string
+		id, parent uint64
+	}
+	want := []delivered{
+		{"start outer", 1, 0},
+		{"log a", 2, 1},
+		{"log b", 3, 1},
+		{"start inner", 4, 1},
+		{"log c", 5, 4},
+		{"end", 6, 4},
+		{"end", 7, 1},
+	}
+	var got []delivered
+	for _, ev := range h.Got {
+		s := ev.Kind.String()
+		for _, name := range []string{"msg", "name"} {
+			if l := ev.Find(name); l.Name != "" {
+				s += " " + l.String()
+			}
+		}
+		got = append(got, delivered{s, ev.ID, ev.Parent})
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("event %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Sampler decides which traces are delivered to the exporter.
+//
+// Sampling is head based: the sampler is consulted only when a span is
+// started with no enclosing span, and its decision applies to every span
+// started within that one. Events that are not part of a trace, such as logs
+// and metrics, are never sampled.
+type Sampler interface {
+	// Sample reports whether the span called name, and all spans within it,
+	// should be delivered.
+	// It is called synchronously from Start, so it should return quickly.
+	Sample(ctx context.Context, name string) bool
+}
+
+// WithSampler returns a context in which root spans are sampled by s.
+// The sampler applies to the exporter that is in effect for ctx, so it must
+// be installed after WithExporter. If there is no exporter, ctx is returned
+// unchanged.
+//
+// Rejecting a root span started from the returned context does not
+// allocate. Rejecting one started from a context derived from it allocates
+// the context for work within the span.
+func WithSampler(ctx context.Context, s Sampler) context.Context {
+	t := lookupTarget(ctx)
+	if t == nil {
+		return ctx
+	}
+	t2 := *t
+	t2.sampler = s
+	// Precompute the target for contexts within unsampled spans, so that
+	// rejecting a span does not have to allocate one.
+	t2.unsampled = t2.unsampledTarget()
+	return t2.install(ctx)
+}
+
+// install returns a context derived from ctx that is bound to t. If t has a
+// sampler, it also precomputes the context within a rejected span started
+// from the returned one.
+func (t *target) install(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, contextKey, t)
+	if t.unsampled != nil {
+		t.home = ctx
+		t.unsampledHome = context.WithValue(ctx, contextKey, t.unsampled)
+	}
+	return ctx
+}
+
+// unsampledTarget returns a target for contexts within unsampled spans. It is
+// a copy of t that drops spans, so other events keep the parent and filter of
+// t.
+func (t *target) unsampledTarget() *target {
+	u := *t
+	u.sampler = nil
+	u.unsampled = nil
+	u.home, u.unsampledHome = nil, nil
+	u.dropSpans = true
+	return &u
+}
+
+// sample consults the sampler of the target, if any, about a span that is
+// about to start. If the span should not be delivered it returns a context in
+// which no spans are delivered.
+func (t *target) sample(ctx context.Context, name string) (context.Context, bool) {
+	if t.sampler == nil || t.parent != 0 || t.sampler.Sample(ctx, name) {
+		return ctx, true
+	}
+	if ctx == t.home {
+		return t.unsampledHome, false
+	}
+	return context.WithValue(ctx, contextKey, t.unsampled), false
+}
+
+// NewRatioSampler returns a sampler that accepts the given fraction of the
+// traces it is asked about.
+// The decision is made by counting rather than at random, so exactly one
+// trace in every 1/ratio is accepted.
+// A ratio of 0 or less rejects all traces, and 1 or more accepts them all.
+func NewRatioSampler(ratio float64) Sampler {
+	return &ratioSampler{ratio: ratio}
+}
+
+type ratioSampler struct {
+	ratio float64
+	count uint64 // accessed using atomic
+}
+
+func (s *ratioSampler) Sample(ctx context.Context, name string) bool {
+	switch {
+	case s.ratio <= 0:
+		return false
+	case s.ratio >= 1:
+		return true
+	}
+	n := atomic.AddUint64(&s.count, 1)
+	// Accept the n'th trace whenever n*ratio crosses an integer.
+	return uint64(float64(n)*s.ratio) != uint64(float64(n-1)*s.ratio)
+}
+
+// NewRateSampler returns a sampler that accepts at most perSecond traces
+// in each second, rejecting the rest.
+// If now is nil, time.Now is used.
+func NewRateSampler(perSecond int, now func() time.Time) Sampler {
+	if now == nil {
+		now = time.Now
+	}
+	return &rateSampler{limit: perSecond, now: now}
+}
+
+type rateSampler struct {
+	limit int
+	now   func() time.Time
+
+	mu     sync.Mutex
+	second int64 // the second the count applies to, in Unix time
+	count  int
+}
+
+func (s *rateSampler) Sample(ctx context.Context, name string) bool {
+	sec := s.now().Unix()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if sec != s.second {
+		s.second = sec
+		s.count = 0
+	}
+	if s.count >= s.limit {
+		return false
+	}
+	s.count++
+	return true
+}
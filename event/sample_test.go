@@ -0,0 +1,114 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/event"
+)
+
+type countHandler struct {
+	starts, ends, logs int
+}
+
+func (h *countHandler) Event(ctx context.Context, ev *event.Event) context.Context {
+	switch ev.Kind {
+	case event.StartKind:
+		h.starts++
+	case event.EndKind:
+		h.ends++
+	case event.LogKind:
+		h.logs++
+	}
+	return ctx
+}
+
+func TestSampler(t *testing.T) {
+	h := &countHandler{}
+	ctx := event.WithExporter(context.Background(), event.NewExporter(h, nil))
+	ctx = event.WithSampler(ctx, event.NewRatioSampler(0.25))
+	for i := 0; i < 8; i++ {
+		sctx := event.Start(ctx, "root")
+		cctx := event.Start(sctx, "child")
+		event.Log(cctx, "message")
+		event.End(cctx)
+		event.End(sctx)
+	}
+	// Two of the eight root spans are sampled, and their children with them.
+	// Logs are delivered whether or not the span they are in was sampled.
+	if h.starts != 4 || h.ends != 4 || h.logs != 8 {
+		t.Errorf("got %d starts, %d ends, %d logs; want 4, 4, 8", h.starts, h.ends, h.logs)
+	}
+}
+
+func TestRatioSampler(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		ratio float64
+		want  int
+	}{
+		{0, 0},
+		{-1, 0},
+		{0.1, 10},
+		{0.5, 50},
+		{1, 100},
+		{2, 100},
+	} {
+		s := event.NewRatioSampler(test.ratio)
+		got := 0
+		for i := 0; i < 100; i++ {
+			if s.Sample(ctx, "span") {
+				got++
+			}
+		}
+		if got != test.want {
+			t.Errorf("ratio %v: sampled %d of 100, want %d", test.ratio, got, test.want)
+		}
+	}
+}
+
+func TestRateSampler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	s := event.NewRateSampler(3, func() time.Time { return now })
+	check := func(want ...bool) {
+		t.Helper()
+		for i, w := range want {
+			if got := s.Sample(ctx, "span"); got != w {
+				t.Errorf("at %v, sample %d: got %v, want %v", now, i, got, w)
+			}
+		}
+	}
+	check(true, true, true, false, false)
+	now = now.Add(500 * time.Millisecond)
+	check(false)
+	now = now.Add(500 * time.Millisecond)
+	check(true, true, true, false)
+}
+
+func TestUnsampledContext(t *testing.T) {
+	type key struct{}
+	h := &countHandler{}
+	ctx := event.WithExporter(context.Background(), event.NewExporter(h, nil))
+	ctx = event.WithSampler(ctx, event.NewRatioSampler(0))
+	// Contexts within unsampled spans keep the values of the context they
+	// were started from.
+	for _, v := range []string{"a", "b", "a"} {
+		sctx := event.Start(context.WithValue(ctx, key{}, v), "root")
+		if got := sctx.Value(key{}); got != v {
+			t.Errorf("got value %v, want %v", got, v)
+		}
+		event.End(event.Start(sctx, "child"))
+		event.End(sctx)
+	}
+	if h.starts != 0 || h.ends != 0 {
+		t.Errorf("got %d starts and %d ends, want none", h.starts, h.ends)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event
+
+import "context"
+
+// DurationKey is the name of the label in which Span.End records the time
+// since the span started.
+const DurationKey = "duration"
+
+// Span is a span of work started by StartSpan.
+// The zero Span is valid, and ending it does nothing.
+type Span struct {
+	ctx context.Context
+}
+
+// StartSpan starts a span called name, as Start does, and returns the
+// context for work within the span together with a Span to end it.
+// Spans started from the returned context are children of this span.
+//
+// If there is no exporter, or tracing is disabled, ctx is returned unchanged
+// and nothing is allocated.
+func StartSpan(ctx context.Context, name string, labels ...Label) (context.Context, Span) {
+	ctx = Start(ctx, name, labels...)
+	return ctx, Span{ctx: ctx}
+}
+
+// End delivers the end event of the span, with the given labels.
+// Unlike End, it also records the time since the span started in a label
+// named DurationKey.
+func (s Span) End(labels ...Label) {
+	if s.ctx == nil {
+		return
+	}
+	end(s.ctx, labels, true)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event_test
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/exp/event"
+	"golang.org/x/exp/event/eventtest"
+)
+
+func TestSpan(t *testing.T) {
+	ctx, h := eventtest.NewCapture()
+	octx, outer := event.StartSpan(ctx, "outer", l1)
+	_, inner := event.StartSpan(octx, "inner")
+	inner.End(l2)
+	outer.End()
+	// A zero Span can be ended safely.
+	event.Span{}.End()
+
+	if len(h.Got) != 4 {
+		t.Fatalf("got %d events, want 4", len(h.Got))
+	}
+	os, is, ie, oe := h.Got[0], h.Got[1], h.Got[2], h.Got[3]
+	for _, k := range []struct {
+		ev   event.Event
+		want event.Kind
+	}{{os, event.StartKind}, {is, event.StartKind}, {ie, event.EndKind}, {oe, event.EndKind}} {
+		if k.ev.Kind != k.want {
+			t.Errorf("event %d: got kind %v, want %v", k.ev.ID, k.ev.Kind, k.want)
+		}
+	}
+	if os.Parent != 0 {
+		t.Errorf("outer span has parent %d, want 0", os.Parent)
+	}
+	if is.Parent != os.ID || oe.Parent != os.ID {
+		t.Errorf("inner start and outer end have parents %d, %d; want %d", is.Parent, oe.Parent, os.ID)
+	}
+	if ie.Parent != is.ID {
+		t.Errorf("inner end has parent %d, want %d", ie.Parent, is.ID)
+	}
+	if got := ie.Find("l2"); !got.HasValue() || got.Int64() != 2 {
+		t.Errorf("inner end: got l2 = %v, want 2", got)
+	}
+
+	// The capturing exporter's clock advances a second for each event.
+	for _, test := range []struct {
+		name string
+		ev   event.Event
+		want time.Duration
+	}{
+		{"inner", ie, 1 * time.Second},
+		{"outer", oe, 3 * time.Second},
+	} {
+		d := test.ev.Find(event.DurationKey)
+		if !d.HasValue() {
+			t.Errorf("%s: no %s label", test.name, event.DurationKey)
+			continue
+		}
+		if got := d.Duration(); got != test.want {
+			t.Errorf("%s: got duration %v, want %v", test.name, got, test.want)
+		}
+	}
+}
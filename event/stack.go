@@ -0,0 +1,55 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !disable_events
+
+package event
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// maxStackDepth is the maximum number of frames that a Stack records.
+const maxStackDepth = 32
+
+// Stack is a call stack, as a list of program counters, innermost first.
+// Error records one in a StackKey label if the exporter has error stacks
+// enabled.
+type Stack []uintptr
+
+// captureStack returns the stack of the caller's caller, skipping skip more
+// frames.
+func captureStack(skip int) Stack {
+	var pcs [maxStackDepth]uintptr
+	// Skip runtime.Callers, captureStack and its caller.
+	n := runtime.Callers(skip+3, pcs[:])
+	return append(Stack(nil), pcs[:n]...)
+}
+
+// Frames returns the frames of the stack.
+func (s Stack) Frames() *runtime.Frames {
+	return runtime.CallersFrames(s)
+}
+
+// String formats the stack with one "function file:line" entry per line,
+// innermost first.
+func (s Stack) String() string {
+	var b strings.Builder
+	frames := s.Frames()
+	for {
+		f, more := frames.Next()
+		if f.Function != "" || f.File != "" {
+			if b.Len() > 0 {
+				b.WriteByte('\n')
+			}
+			fmt.Fprintf(&b, "%s %s:%d", f.Function, f.File, f.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,182 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Iconvg2png rasterizes an IconVG graphic and writes it as a PNG image.
+//
+// Usage:
+//
+//	iconvg2png [-size n] [-bg color] [-palette file] in.ivg out.png
+//
+// Flags may also follow the file names.
+//
+// The -size flag sets the length, in pixels, of the longer side of the
+// output image. The shorter side is scaled to keep the graphic's aspect
+// ratio. The default is 256.
+//
+// The -bg flag sets the color, in "#rrggbb" or "#rrggbbaa" form, that the
+// graphic is drawn over. The default is a transparent background.
+//
+// The -palette flag names a JSON file that holds an array of up to 64 colors,
+// in the same form as -bg, that replace the start of the graphic's suggested
+// palette. For example:
+//
+//	["#ff0000", "#00ff0080"]
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/shiny/iconvg"
+)
+
+var (
+	size    = flag.Int("size", 256, "length in pixels of the longer side of the image")
+	bg      = flag.String("bg", "", "background `color`, as #rrggbb or #rrggbbaa")
+	palette = flag.String("palette", "", "read the custom palette from JSON `file`")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: iconvg2png [-size n] [-bg color] [-palette file] in.ivg out.png\n\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("iconvg2png: ")
+	flag.Usage = usage
+
+	// Allow flags both before and after the file names.
+	var files []string
+	args := os.Args[1:]
+	for {
+		flag.CommandLine.Parse(args)
+		args = flag.Args()
+		if len(args) == 0 {
+			break
+		}
+		files = append(files, args[0])
+		args = args[1:]
+	}
+	if len(files) != 2 {
+		usage()
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts := options{size: *size}
+	if *bg != "" {
+		c, err := parseColor(*bg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.bg = c
+	}
+	if *palette != "" {
+		data, err := os.ReadFile(*palette)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if opts.palette, err = parsePalette(data); err != nil {
+			log.Fatalf("%s: %v", *palette, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := convert(&buf, src, opts); err != nil {
+		log.Fatalf("%s: %v", files[0], err)
+	}
+	if err := os.WriteFile(files[1], buf.Bytes(), 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type options struct {
+	size    int
+	bg      color.Color // nil means transparent
+	palette []color.RGBA
+}
+
+// convert rasterizes the IconVG graphic src and writes it to w as a PNG.
+func convert(w io.Writer, src []byte, opts options) error {
+	if opts.size <= 0 {
+		return fmt.Errorf("invalid size %d", opts.size)
+	}
+	md, err := iconvg.DecodeMetadata(src)
+	if err != nil {
+		return err
+	}
+	width, height := opts.size, opts.size
+	if dx, dy := md.ViewBox.AspectRatio(); dx < dy {
+		width = int(float32(opts.size) * dx / dy)
+	} else {
+		height = int(float32(opts.size) * dy / dx)
+	}
+
+	pal := md.Palette
+	copy(pal[:], opts.palette)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if opts.bg != nil {
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(opts.bg), image.Point{}, draw.Src)
+	}
+	var z iconvg.Rasterizer
+	z.SetDstImage(dst, dst.Bounds(), draw.Over)
+	if err := iconvg.Decode(&z, src, &iconvg.DecodeOptions{Palette: &pal}); err != nil {
+		return err
+	}
+	return png.Encode(w, dst)
+}
+
+// parsePalette parses a JSON array of at most 64 colors.
+func parsePalette(data []byte) ([]color.RGBA, error) {
+	var strs []string
+	if err := json.Unmarshal(data, &strs); err != nil {
+		return nil, err
+	}
+	if len(strs) > len(iconvg.Palette{}) {
+		return nil, fmt.Errorf("%d colors in palette, want at most %d", len(strs), len(iconvg.Palette{}))
+	}
+	pal := make([]color.RGBA, len(strs))
+	for i, s := range strs {
+		c, err := parseColor(s)
+		if err != nil {
+			return nil, err
+		}
+		pal[i] = c
+	}
+	return pal, nil
+}
+
+// parseColor parses a color of the form #rrggbb or #rrggbbaa, where the
+// red, green and blue components are not premultiplied by alpha.
+func parseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if hex == s || (len(hex) != 6 && len(hex) != 8) {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb or #rrggbbaa", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	x, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb or #rrggbbaa", s)
+	}
+	c := color.NRGBA{R: uint8(x >> 24), G: uint8(x >> 16), B: uint8(x >> 8), A: uint8(x)}
+	return color.RGBAModel.Convert(c).(color.RGBA), nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	src, err := os.ReadFile(filepath.FromSlash("../../iconvg/testdata/favicon.ivg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	opts := options{
+		size:    128,
+		bg:      color.White,
+		palette: []color.RGBA{{0xfe, 0x76, 0xea, 0xff}},
+	}
+	if err := convert(&buf, src, opts); err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("convert wrote no data")
+	}
+	m, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode: %v", err)
+	}
+	if got := m.Bounds().Size(); got.X != 128 || got.Y != 128 {
+		t.Errorf("image size = %v, want (128,128)", got)
+	}
+	// The top left corner of the favicon is outside the gopher.
+	if r, g, b, a := m.At(0, 0).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff || a != 0xffff {
+		t.Errorf("corner pixel = %v, want the white background", m.At(0, 0))
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want color.RGBA
+		ok   bool
+	}{
+		{"#000000", color.RGBA{0x00, 0x00, 0x00, 0xff}, true},
+		{"#76e1fe", color.RGBA{0x76, 0xe1, 0xfe, 0xff}, true},
+		{"#ff000080", color.RGBA{0x80, 0x00, 0x00, 0x80}, true},
+		{"#ffffff00", color.RGBA{}, true},
+		{"ffffff", color.RGBA{}, false},
+		{"#fff", color.RGBA{}, false},
+		{"#gggggg", color.RGBA{}, false},
+	} {
+		got, err := parseColor(test.in)
+		if (err == nil) != test.ok || got != test.want {
+			t.Errorf("parseColor(%q) = %v, %v; want %v, ok=%v", test.in, got, err, test.want, test.ok)
+		}
+	}
+}
+
+func TestParsePalette(t *testing.T) {
+	got, err := parsePalette([]byte(`["#ff0000", "#00ff0080"]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []color.RGBA{{0xff, 0x00, 0x00, 0xff}, {0x00, 0x80, 0x00, 0x80}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("parsePalette = %v, want %v", got, want)
+	}
+	if _, err := parsePalette([]byte(`{}`)); err == nil {
+		t.Error("parsePalette of a JSON object succeeded")
+	}
+}
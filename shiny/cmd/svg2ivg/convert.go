@@ -0,0 +1,593 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/shiny/iconvg"
+	"golang.org/x/image/math/f32"
+)
+
+// node is an element of an SVG document.
+type node struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []node     `xml:",any"`
+}
+
+// attr returns the value of the named attribute, ignoring its namespace.
+func (n *node) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// props returns the presentation attributes of n, overridden by the
+// declarations in its style attribute, if any.
+func (n *node) props() map[string]string {
+	m := make(map[string]string)
+	for _, a := range n.Attrs {
+		if a.Name.Space == "" {
+			m[a.Name.Local] = strings.TrimSpace(a.Value)
+		}
+	}
+	for _, decl := range strings.Split(m["style"], ";") {
+		if k, v, ok := strings.Cut(decl, ":"); ok {
+			m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return m
+}
+
+// The CREG and NREG slots where gradient stops are stored.
+const gradientBase = 10
+
+// minStopGap is the smallest difference between consecutive gradient stop
+// offsets. IconVG requires strictly increasing offsets, so stops at the same
+// SVG offset are moved apart by this much. It is a multiple of the zero-to-one
+// number encoding's resolution, and much larger than the rounding error of
+// the real number encoding.
+const minStopGap = 1.0 / 15120
+
+type converter struct {
+	e         iconvg.Encoder
+	ox, oy    float64 // the center of the SVG viewBox
+	vw, vh    float64 // the size of the SVG viewBox
+	gradients map[string]*node
+
+	// fill is the color in CREG[CSEL], if fillOK.
+	fill   color.RGBA
+	fillOK bool
+}
+
+// style is the inherited state that affects how shapes are filled.
+type style struct {
+	fill        string
+	fillOpacity float64
+}
+
+// convert converts the SVG document src to IconVG.
+func convert(src []byte, hires bool) ([]byte, error) {
+	var root node
+	if err := xml.Unmarshal(src, &root); err != nil {
+		return nil, err
+	}
+	if root.XMLName.Local != "svg" {
+		return nil, fmt.Errorf("root element is <%s>, want <svg>", root.XMLName.Local)
+	}
+
+	var minX, minY, w, h float64
+	if vb := root.attr("viewBox"); vb != "" {
+		nums, err := parseNumbers(vb)
+		if err != nil || len(nums) != 4 {
+			return nil, fmt.Errorf("invalid viewBox %q", vb)
+		}
+		minX, minY, w, h = nums[0], nums[1], nums[2], nums[3]
+	} else {
+		var err error
+		if w, err = parseLength(root.attr("width")); err != nil {
+			return nil, fmt.Errorf("<svg> width: %v", err)
+		}
+		if h, err = parseLength(root.attr("height")); err != nil {
+			return nil, fmt.Errorf("<svg> height: %v", err)
+		}
+	}
+	if w <= 0 || h <= 0 {
+		return nil, fmt.Errorf("empty viewBox")
+	}
+
+	// IconVG coordinates are most compactly encoded close to the origin, so
+	// center the viewBox on it.
+	c := &converter{
+		ox:        minX + w/2,
+		oy:        minY + h/2,
+		vw:        w,
+		vh:        h,
+		gradients: make(map[string]*node),
+		fill:      iconvg.DefaultPalette[0],
+		fillOK:    true,
+	}
+	c.collectGradients(&root)
+	c.e.Reset(iconvg.Metadata{
+		ViewBox: iconvg.Rectangle{
+			Min: f32.Vec2{float32(-w / 2), float32(-h / 2)},
+			Max: f32.Vec2{float32(+w / 2), float32(+h / 2)},
+		},
+		Palette: iconvg.DefaultPalette,
+	})
+	c.e.HighResolutionCoordinates = hires
+
+	st := style{fill: "black", fillOpacity: 1}
+	if err := c.container(&root, st); err != nil {
+		return nil, err
+	}
+	return c.e.Bytes()
+}
+
+func (c *converter) collectGradients(n *node) {
+	switch n.XMLName.Local {
+	case "linearGradient", "radialGradient":
+		if id := n.attr("id"); id != "" {
+			c.gradients[id] = n
+		}
+	}
+	for i := range n.Children {
+		c.collectGradients(&n.Children[i])
+	}
+}
+
+// checkProps returns an error if props use a feature that cannot be
+// converted.
+func checkProps(n *node, props map[string]string) error {
+	for _, name := range []string{"transform", "filter", "mask", "clip-path"} {
+		if v, ok := props[name]; ok && v != "none" {
+			return fmt.Errorf("<%s>: %s is not supported", n.XMLName.Local, name)
+		}
+	}
+	if v, ok := props["stroke"]; ok && v != "none" {
+		return fmt.Errorf("<%s>: strokes are not supported", n.XMLName.Local)
+	}
+	if props["fill-rule"] == "evenodd" {
+		return fmt.Errorf("<%s>: fill-rule evenodd is not supported", n.XMLName.Local)
+	}
+	return nil
+}
+
+// inherit returns st updated by the fill properties of props.
+func inherit(st style, props map[string]string) (style, error) {
+	if v, ok := props["fill"]; ok {
+		st.fill = v
+	}
+	if v, ok := props["fill-opacity"]; ok {
+		op, err := parseOpacity(v)
+		if err != nil {
+			return st, err
+		}
+		st.fillOpacity = op
+	}
+	return st, nil
+}
+
+// container converts the children of the svg or g element n.
+func (c *converter) container(n *node, st style) error {
+	props := n.props()
+	if err := checkProps(n, props); err != nil {
+		return err
+	}
+	if v, ok := props["opacity"]; ok {
+		if op, err := parseOpacity(v); err != nil || op != 1 {
+			return fmt.Errorf("<%s>: group opacity is not supported", n.XMLName.Local)
+		}
+	}
+	st, err := inherit(st, props)
+	if err != nil {
+		return fmt.Errorf("<%s>: %v", n.XMLName.Local, err)
+	}
+	for i := range n.Children {
+		if err := c.element(&n.Children[i], st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *converter) element(n *node, st style) error {
+	switch n.XMLName.Local {
+	case "g":
+		return c.container(n, st)
+	case "defs", "linearGradient", "radialGradient", "title", "desc", "metadata":
+		// Gradients are found by collectGradients; the rest is not drawn.
+		return nil
+	case "path", "rect", "circle", "ellipse", "polygon", "polyline":
+		// Handled below.
+	default:
+		return fmt.Errorf("<%s> elements are not supported", n.XMLName.Local)
+	}
+
+	props := n.props()
+	if err := checkProps(n, props); err != nil {
+		return err
+	}
+	st, err := inherit(st, props)
+	if err != nil {
+		return fmt.Errorf("<%s>: %v", n.XMLName.Local, err)
+	}
+	if v, ok := props["opacity"]; ok {
+		// For a single filled shape, opacity is the same as fill-opacity.
+		op, err := parseOpacity(v)
+		if err != nil {
+			return fmt.Errorf("<%s>: %v", n.XMLName.Local, err)
+		}
+		st.fillOpacity *= op
+	}
+	if ok, err := c.setFill(st); err != nil {
+		return fmt.Errorf("<%s>: %v", n.XMLName.Local, err)
+	} else if !ok {
+		return nil
+	}
+	if err := c.shape(n); err != nil {
+		return fmt.Errorf("<%s>: %v", n.XMLName.Local, err)
+	}
+	return nil
+}
+
+// setFill sets CREG[CSEL] to the fill of st. It reports false if the
+// shape is not filled.
+func (c *converter) setFill(st style) (bool, error) {
+	if st.fill == "none" {
+		return false, nil
+	}
+	if strings.HasPrefix(st.fill, "url(") {
+		id := strings.TrimSuffix(strings.TrimPrefix(st.fill, "url("), ")")
+		g := c.gradients[strings.TrimPrefix(strings.TrimSpace(id), "#")]
+		if g == nil {
+			return false, fmt.Errorf("unknown gradient %s", st.fill)
+		}
+		c.fillOK = false
+		return true, c.setGradient(g, st.fillOpacity)
+	}
+	rgba, err := parseColor(st.fill, st.fillOpacity)
+	if err != nil {
+		return false, err
+	}
+	if !c.fillOK || rgba != c.fill {
+		c.e.SetCReg(0, false, iconvg.RGBAColor(rgba))
+		c.fill, c.fillOK = rgba, true
+	}
+	return true, nil
+}
+
+func (c *converter) setGradient(g *node, opacity float64) error {
+	props := g.props()
+	name := g.XMLName.Local
+	if props["gradientUnits"] != "userSpaceOnUse" {
+		return fmt.Errorf("%s: only userSpaceOnUse gradientUnits are supported", name)
+	}
+	if _, ok := props["gradientTransform"]; ok {
+		return fmt.Errorf("%s: gradientTransform is not supported", name)
+	}
+	if g.attr("href") != "" {
+		return fmt.Errorf("%s: href is not supported", name)
+	}
+
+	var spread iconvg.GradientSpread
+	switch props["spreadMethod"] {
+	case "", "pad":
+		spread = iconvg.GradientSpreadPad
+	case "reflect":
+		spread = iconvg.GradientSpreadReflect
+	case "repeat":
+		spread = iconvg.GradientSpreadRepeat
+	default:
+		return fmt.Errorf("%s: invalid spreadMethod %q", name, props["spreadMethod"])
+	}
+
+	var stops []iconvg.GradientStop
+	for i := range g.Children {
+		s := &g.Children[i]
+		if s.XMLName.Local != "stop" {
+			continue
+		}
+		sp := s.props()
+		offset, err := parseOpacity(sp["offset"])
+		if err != nil {
+			return fmt.Errorf("%s stop offset: %v", name, err)
+		}
+		// SVG offsets never decrease, but IconVG offsets must increase.
+		if n := len(stops); n > 0 && offset < float64(stops[n-1].Offset)+minStopGap {
+			offset = float64(stops[n-1].Offset) + minStopGap
+			if offset > 1 {
+				return fmt.Errorf("%s: too many stops at offset 1", name)
+			}
+		}
+		op := 1.0
+		if v, ok := sp["stop-opacity"]; ok {
+			if op, err = parseOpacity(v); err != nil {
+				return fmt.Errorf("%s stop-opacity: %v", name, err)
+			}
+		}
+		col := sp["stop-color"]
+		if col == "" {
+			col = "black"
+		}
+		rgba, err := parseColor(col, op*opacity)
+		if err != nil {
+			return fmt.Errorf("%s stop-color: %v", name, err)
+		}
+		stops = append(stops, iconvg.GradientStop{Offset: float32(offset), Color: rgba})
+	}
+	if len(stops) == 0 {
+		return fmt.Errorf("%s has no stops", name)
+	}
+
+	// Percentages are relative to the viewBox width, height or normalized
+	// diagonal, for x, y and other lengths.
+	coord := func(attr, def string) (float64, error) {
+		v, ok := props[attr]
+		if !ok {
+			v = def
+		}
+		size := math.Sqrt((c.vw*c.vw + c.vh*c.vh) / 2)
+		switch {
+		case strings.Contains(attr, "x"):
+			size = c.vw
+		case strings.Contains(attr, "y"):
+			size = c.vh
+		}
+		f, err := parseLengthOrPercentage(v, size)
+		if err != nil {
+			return 0, fmt.Errorf("%s %s: %v", name, attr, err)
+		}
+		return f, nil
+	}
+	if name == "linearGradient" {
+		var p [4]float64
+		for i, a := range []struct{ attr, def string }{
+			{"x1", "0"}, {"y1", "0"}, {"x2", "100%"}, {"y2", "0"},
+		} {
+			var err error
+			if p[i], err = coord(a.attr, a.def); err != nil {
+				return err
+			}
+		}
+		c.e.SetLinearGradient(gradientBase, gradientBase,
+			float32(p[0]-c.ox), float32(p[1]-c.oy), float32(p[2]-c.ox), float32(p[3]-c.oy),
+			spread, stops)
+		return nil
+	}
+
+	var p [3]float64
+	for i, a := range []struct{ attr, def string }{
+		{"cx", "50%"}, {"cy", "50%"}, {"r", "50%"},
+	} {
+		var err error
+		if p[i], err = coord(a.attr, a.def); err != nil {
+			return err
+		}
+	}
+	for i, focal := range []string{"fx", "fy"} {
+		if _, ok := props[focal]; ok {
+			if f, err := coord(focal, ""); err != nil || f != p[i] {
+				return fmt.Errorf("%s: focal points are not supported", name)
+			}
+		}
+	}
+	c.e.SetCircularGradient(gradientBase, gradientBase,
+		float32(p[0]-c.ox), float32(p[1]-c.oy), float32(p[2]), 0,
+		spread, stops)
+	return nil
+}
+
+// shape encodes the geometry of n, filled with CREG[CSEL].
+func (c *converter) shape(n *node) error {
+	lengths := func(attrs ...string) ([]float64, error) {
+		vals := make([]float64, len(attrs))
+		for i, a := range attrs {
+			v := n.attr(a)
+			if v == "" {
+				continue
+			}
+			f, err := parseLength(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", a, err)
+			}
+			vals[i] = f
+		}
+		return vals, nil
+	}
+	x := func(v float64) float32 { return float32(v - c.ox) }
+	y := func(v float64) float32 { return float32(v - c.oy) }
+
+	switch n.XMLName.Local {
+	case "path":
+		return encodePath(&c.e, n.attr("d"), c.ox, c.oy)
+
+	case "rect":
+		v, err := lengths("x", "y", "width", "height", "rx", "ry")
+		if err != nil {
+			return err
+		}
+		x0, y0, w, h, rx, ry := v[0], v[1], v[2], v[3], v[4], v[5]
+		if w <= 0 || h <= 0 {
+			return nil
+		}
+		if n.attr("rx") == "" {
+			rx = ry
+		} else if n.attr("ry") == "" {
+			ry = rx
+		}
+		if rx > w/2 {
+			rx = w / 2
+		}
+		if ry > h/2 {
+			ry = h / 2
+		}
+		if rx <= 0 || ry <= 0 {
+			c.e.AppendRect(0, x(x0), y(y0), x(x0+w), y(y0+h))
+			return nil
+		}
+		c.e.StartPath(0, x(x0+rx), y(y0))
+		c.e.AbsHLineTo(x(x0 + w - rx))
+		c.e.AbsArcTo(float32(rx), float32(ry), 0, false, true, x(x0+w), y(y0+ry))
+		c.e.AbsVLineTo(y(y0 + h - ry))
+		c.e.AbsArcTo(float32(rx), float32(ry), 0, false, true, x(x0+w-rx), y(y0+h))
+		c.e.AbsHLineTo(x(x0 + rx))
+		c.e.AbsArcTo(float32(rx), float32(ry), 0, false, true, x(x0), y(y0+h-ry))
+		c.e.AbsVLineTo(y(y0 + ry))
+		c.e.AbsArcTo(float32(rx), float32(ry), 0, false, true, x(x0+rx), y(y0))
+		c.e.ClosePathEndPath()
+
+	case "circle":
+		v, err := lengths("cx", "cy", "r")
+		if err != nil {
+			return err
+		}
+		if v[2] > 0 {
+			c.e.AppendCircle(0, x(v[0]), y(v[1]), float32(v[2]))
+		}
+
+	case "ellipse":
+		v, err := lengths("cx", "cy", "rx", "ry")
+		if err != nil {
+			return err
+		}
+		cx, cy, rx, ry := v[0], v[1], v[2], v[3]
+		if rx <= 0 || ry <= 0 {
+			return nil
+		}
+		c.e.StartPath(0, x(cx+rx), y(cy))
+		c.e.AbsArcTo(float32(rx), float32(ry), 0, false, true, x(cx-rx), y(cy))
+		c.e.AbsArcTo(float32(rx), float32(ry), 0, false, true, x(cx+rx), y(cy))
+		c.e.ClosePathEndPath()
+
+	case "polygon", "polyline":
+		// IconVG paths are always closed, which is how SVG fills polylines.
+		pts, err := parseNumbers(n.attr("points"))
+		if err != nil {
+			return fmt.Errorf("points: %v", err)
+		}
+		if len(pts)%2 != 0 {
+			return fmt.Errorf("odd number of coordinates in points")
+		}
+		if len(pts) < 4 {
+			return nil
+		}
+		c.e.StartPath(0, x(pts[0]), y(pts[1]))
+		for i := 2; i < len(pts); i += 2 {
+			c.e.AbsLineTo(x(pts[i]), y(pts[i+1]))
+		}
+		c.e.ClosePathEndPath()
+	}
+	return nil
+}
+
+// parseNumbers parses a list of numbers separated by white space or commas.
+func parseNumbers(s string) ([]float64, error) {
+	var nums []float64
+	sc := scanner{s: s}
+	for !sc.done() {
+		f, err := sc.number()
+		if err != nil {
+			return nil, err
+		}
+		nums = append(nums, f)
+	}
+	return nums, nil
+}
+
+// parseLength parses a length in user units, with an optional px suffix.
+func parseLength(s string) (float64, error) {
+	s = strings.TrimSuffix(strings.TrimSpace(s), "px")
+	switch {
+	case s == "":
+		return 0, fmt.Errorf("missing length")
+	case strings.HasSuffix(s, "%"):
+		return 0, fmt.Errorf("percentage lengths are not supported")
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// parseLengthOrPercentage is like parseLength but also accepts a percentage,
+// which is a fraction of size.
+func parseLengthOrPercentage(s string, size float64) (float64, error) {
+	if t := strings.TrimSpace(s); strings.HasSuffix(t, "%") {
+		f, err := strconv.ParseFloat(strings.TrimSuffix(t, "%"), 64)
+		if err != nil {
+			return 0, err
+		}
+		return f / 100 * size, nil
+	}
+	return parseLength(s)
+}
+
+// parseOpacity parses a number or percentage, clamped to the range [0, 1].
+func parseOpacity(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	scale := 1.0
+	if strings.HasSuffix(s, "%") {
+		s, scale = strings.TrimSuffix(s, "%"), 100
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	switch f /= scale; {
+	case f < 0:
+		return 0, nil
+	case f > 1:
+		return 1, nil
+	}
+	return f, nil
+}
+
+var namedColors = map[string]color.RGBA{
+	"black":   {0x00, 0x00, 0x00, 0xff},
+	"silver":  {0xc0, 0xc0, 0xc0, 0xff},
+	"gray":    {0x80, 0x80, 0x80, 0xff},
+	"white":   {0xff, 0xff, 0xff, 0xff},
+	"maroon":  {0x80, 0x00, 0x00, 0xff},
+	"red":     {0xff, 0x00, 0x00, 0xff},
+	"purple":  {0x80, 0x00, 0x80, 0xff},
+	"fuchsia": {0xff, 0x00, 0xff, 0xff},
+	"green":   {0x00, 0x80, 0x00, 0xff},
+	"lime":    {0x00, 0xff, 0x00, 0xff},
+	"olive":   {0x80, 0x80, 0x00, 0xff},
+	"yellow":  {0xff, 0xff, 0x00, 0xff},
+	"navy":    {0x00, 0x00, 0x80, 0xff},
+	"blue":    {0x00, 0x00, 0xff, 0xff},
+	"teal":    {0x00, 0x80, 0x80, 0xff},
+	"aqua":    {0x00, 0xff, 0xff, 0xff},
+}
+
+// parseColor parses a color as #rgb, #rrggbb or one of the basic named
+// colors, and applies the given opacity.
+func parseColor(s string, opacity float64) (color.RGBA, error) {
+	c, ok := namedColors[strings.ToLower(s)]
+	if !ok {
+		hex := strings.TrimPrefix(s, "#")
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		x, err := strconv.ParseUint(hex, 16, 32)
+		if hex == s || len(hex) != 6 || err != nil {
+			return color.RGBA{}, fmt.Errorf("unsupported color %q", s)
+		}
+		c = color.RGBA{uint8(x >> 16), uint8(x >> 8), uint8(x), 0xff}
+	}
+	if opacity < 1 {
+		nc := color.NRGBA{c.R, c.G, c.B, uint8(opacity*0xff + 0.5)}
+		c = color.RGBAModel.Convert(nc).(color.RGBA)
+	}
+	return c, nil
+}
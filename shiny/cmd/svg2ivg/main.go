@@ -0,0 +1,74 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Svg2ivg converts an SVG file to the IconVG format.
+//
+// Usage:
+//
+//	svg2ivg [-hires] in.svg out.ivg
+//
+// Flags may also follow the file names.
+//
+// Only the subset of SVG that maps directly to IconVG is supported: the
+// path, rect, circle, ellipse, polygon and polyline elements, grouped by g
+// elements, filled with solid colors or with linear and radial gradients
+// whose gradientUnits are userSpaceOnUse. The IconVG graphic's viewBox has
+// the same size as the SVG's, but is centered on the origin.
+//
+// Anything else that affects the rendering, such as text, strokes,
+// transforms, filters and masks, is reported as an error rather than
+// dropped.
+//
+// The -hires flag sets iconvg.Encoder.HighResolutionCoordinates, so that
+// coordinates are not quantized to 1/64th of a unit.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+var hires = flag.Bool("hires", false, "encode coordinates at high resolution")
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: svg2ivg [-hires] in.svg out.ivg\n\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("svg2ivg: ")
+	flag.Usage = usage
+
+	// Allow flags both before and after the file names.
+	var files []string
+	args := os.Args[1:]
+	for {
+		flag.CommandLine.Parse(args)
+		args = flag.Args()
+		if len(args) == 0 {
+			break
+		}
+		files = append(files, args[0])
+		args = args[1:]
+	}
+	if len(files) != 2 {
+		usage()
+	}
+
+	src, err := os.ReadFile(files[0])
+	if err != nil {
+		log.Fatal(err)
+	}
+	ivg, err := convert(src, *hires)
+	if err != nil {
+		log.Fatalf("%s: %v", files[0], err)
+	}
+	if err := os.WriteFile(files[1], ivg, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/iconvg"
+)
+
+func TestConvertActionInfo(t *testing.T) {
+	svg, err := os.ReadFile(filepath.FromSlash("../../iconvg/testdata/action-info.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, res := range []string{"lores", "hires"} {
+		got, err := convert(svg, res == "hires")
+		if err != nil {
+			t.Fatalf("%s: convert: %v", res, err)
+		}
+		want, err := os.ReadFile(filepath.FromSlash("../../iconvg/testdata/action-info." + res + ".ivg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s:\ngot  %d bytes: % x\nwant %d bytes: % x", res, len(got), got, len(want), want)
+		}
+	}
+}
+
+func TestConvertShapes(t *testing.T) {
+	const svg = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64">
+	<title>Shapes</title>
+	<defs>
+		<linearGradient id="g" gradientUnits="userSpaceOnUse" x1="20" y1="0" x2="44" y2="0">
+			<stop offset="0" stop-color="#f00"/>
+			<stop offset="100%" style="stop-color: #0000ff"/>
+		</linearGradient>
+	</defs>
+	<rect x="8" y="8" width="48" height="16" fill="navy"/>
+	<g style="fill: yellow">
+		<circle cx="32" cy="40" r="10.5"/>
+		<polygon points="0,64 8,56 16,64"/>
+	</g>
+	<rect x="8" y="48" width="48" height="8" fill="url(#g)"/>
+	<ellipse cx="32" cy="32" rx="4" ry="2" fill="none"/>
+</svg>`
+	got, err := convert([]byte(svg), false)
+	if err != nil {
+		t.Fatalf("convert: %v", err)
+	}
+
+	var e iconvg.Encoder
+	e.SetCReg(0, false, iconvg.RGBAColor(color.RGBA{0x00, 0x00, 0x80, 0xff}))
+	e.AppendRect(0, -24, -24, +24, -8)
+	e.SetCReg(0, false, iconvg.RGBAColor(color.RGBA{0xff, 0xff, 0x00, 0xff}))
+	e.AppendCircle(0, 0, 8, 10.5)
+	e.StartPath(0, -32, +32)
+	e.AbsLineTo(-24, +24)
+	e.AbsLineTo(-16, +32)
+	e.ClosePathEndPath()
+	e.SetLinearGradient(gradientBase, gradientBase, -12, -32, +12, -32, iconvg.GradientSpreadPad, []iconvg.GradientStop{
+		{Offset: 0, Color: color.RGBA{0xff, 0x00, 0x00, 0xff}},
+		{Offset: 1, Color: color.RGBA{0x00, 0x00, 0xff, 0xff}},
+	})
+	e.AppendRect(0, -24, +16, +24, +24)
+	want, err := e.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("\ngot  % x\nwant % x", got, want)
+	}
+}
+
+func TestConvertGradientStops(t *testing.T) {
+	for _, body := range []string{
+		// A hard stop, with two stops at the same offset.
+		`<linearGradient id="g" gradientUnits="userSpaceOnUse" x2="64">
+			<stop offset="0" stop-color="red"/>
+			<stop offset="50%" stop-color="red"/>
+			<stop offset="50%" stop-color="blue"/>
+			<stop offset="40%" stop-color="blue"/>
+		</linearGradient>`,
+		// Stops at 0 and the default, percentage, coordinates.
+		`<radialGradient id="g" gradientUnits="userSpaceOnUse" fx="50%">
+			<stop offset="0" stop-color="red"/>
+			<stop offset="0" stop-color="blue"/>
+		</radialGradient>`,
+	} {
+		svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64">` + body +
+			`<rect width="64" height="64" fill="url(#g)"/></svg>`
+		got, err := convert([]byte(svg), false)
+		if err != nil {
+			t.Errorf("convert(%s): %v", body, err)
+			continue
+		}
+		if err := iconvg.Validate(got); err != nil {
+			t.Errorf("convert(%s): Validate: %v", body, err)
+		}
+	}
+}
+
+func TestConvertPath(t *testing.T) {
+	for _, test := range []struct {
+		d    string
+		want func(e *iconvg.Encoder)
+	}{{
+		d: "M0,0 10,0 1e1-10 0-10z",
+		want: func(e *iconvg.Encoder) {
+			e.StartPath(0, 0, -10)
+			e.AbsLineTo(10, -10)
+			e.AbsLineTo(10, -20)
+			e.AbsLineTo(0, -20)
+			e.ClosePathEndPath()
+		},
+	}, {
+		// A relative moveto after a closepath is relative to the start of
+		// the closed subpath, otherwise to the current point.
+		d: "m1 2h3zm1 1h1m1 1v1",
+		want: func(e *iconvg.Encoder) {
+			e.StartPath(0, 1, -8)
+			e.RelHLineTo(3)
+			e.ClosePathRelMoveTo(1, 1)
+			e.RelHLineTo(1)
+			e.ClosePathAbsMoveTo(4, -6)
+			e.RelVLineTo(1)
+			e.ClosePathEndPath()
+		},
+	}, {
+		// Arc flags need not be separated from what follows.
+		d: "M0 0a5 5 90 1110 0A5 5 0 0 0 0 0",
+		want: func(e *iconvg.Encoder) {
+			e.StartPath(0, 0, -10)
+			e.RelArcTo(5, 5, 0.25, true, true, 10, 0)
+			e.AbsArcTo(5, 5, 0, false, false, 0, -10)
+			e.ClosePathEndPath()
+		},
+	}} {
+		var got, want iconvg.Encoder
+		if err := encodePath(&got, test.d, 0, 10); err != nil {
+			t.Errorf("encodePath(%q): %v", test.d, err)
+			continue
+		}
+		test.want(&want)
+		gotBytes, err1 := got.Bytes()
+		wantBytes, err2 := want.Bytes()
+		if err1 != nil || err2 != nil {
+			t.Fatalf("Bytes: %v, %v", err1, err2)
+		}
+		if !bytes.Equal(gotBytes, wantBytes) {
+			t.Errorf("encodePath(%q):\ngot  % x\nwant % x", test.d, gotBytes, wantBytes)
+		}
+	}
+
+	for _, d := range []string{"L0 0", "M0", "M0 0 X1", "M0 0z1", "M0 0a5 5 0 2 0 1 1"} {
+		var e iconvg.Encoder
+		if err := encodePath(&e, d, 0, 0); err == nil {
+			t.Errorf("encodePath(%q) succeeded", d)
+		}
+	}
+}
+
+func TestConvertUnsupported(t *testing.T) {
+	for _, test := range []struct {
+		body, want string
+	}{
+		{`<text x="0" y="0">hi</text>`, "<text> elements are not supported"},
+		{`<filter id="f"/>`, "<filter> elements are not supported"},
+		{`<path d="M0 0h1v1z" stroke="red"/>`, "strokes are not supported"},
+		{`<path d="M0 0h1v1z" style="stroke:red"/>`, "strokes are not supported"},
+		{`<g transform="scale(2)"><path d="M0 0h1v1z"/></g>`, "transform is not supported"},
+		{`<g opacity="0.5"><path d="M0 0h1v1z"/></g>`, "group opacity is not supported"},
+		{`<path d="M0 0h1v1z" fill="url(#nope)"/>`, "unknown gradient"},
+		{`<path d="M0 0h1v1z" fill="rgb(1,2,3)"/>`, "unsupported color"},
+		{`<radialGradient id="r"><stop offset="0"/></radialGradient><path d="M0 0h1v1z" fill="url(#r)"/>`,
+			"only userSpaceOnUse gradientUnits are supported"},
+		{`<linearGradient id="g" gradientUnits="userSpaceOnUse"><stop offset="1"/><stop offset="1"/></linearGradient>` +
+			`<path d="M0 0h1v1z" fill="url(#g)"/>`, "too many stops at offset 1"},
+	} {
+		svg := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 64 64">` + test.body + `</svg>`
+		_, err := convert([]byte(svg), false)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("convert(%s): got error %v, want %q", test.body, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"golang.org/x/exp/shiny/iconvg"
+)
+
+// nArgs is the number of arguments taken by each SVG path command.
+var nArgs = [256]int{
+	'M': 2, 'm': 2,
+	'Z': 0, 'z': 0,
+	'L': 2, 'l': 2,
+	'H': 1, 'h': 1,
+	'V': 1, 'v': 1,
+	'C': 6, 'c': 6,
+	'S': 4, 's': 4,
+	'Q': 4, 'q': 4,
+	'T': 2, 't': 2,
+	'A': 7, 'a': 7,
+}
+
+// encodePath encodes the SVG path data d as an IconVG path filled with
+// CREG[CSEL]. Absolute coordinates are translated by (-ox, -oy).
+func encodePath(e *iconvg.Encoder, d string, ox, oy float64) error {
+	sc := scanner{s: d}
+	var (
+		verb            byte
+		args            [7]float64
+		curX, curY      float64
+		startX, startY  float64
+		started, closed bool
+	)
+	for !sc.done() {
+		if c := sc.s[sc.i]; ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') {
+			verb = c
+			sc.i++
+			if nArgs[verb] == 0 && verb != 'Z' && verb != 'z' {
+				return fmt.Errorf("path data: unsupported command %q", verb)
+			}
+		} else if verb == 0 || verb == 'Z' || verb == 'z' {
+			return fmt.Errorf("path data: expected a command at offset %d", sc.i)
+		}
+
+		n := nArgs[verb]
+		for i := 0; i < n; i++ {
+			var err error
+			if (verb == 'A' || verb == 'a') && (i == 3 || i == 4) {
+				args[i], err = sc.flag()
+			} else {
+				args[i], err = sc.number()
+			}
+			if err != nil {
+				return fmt.Errorf("path data: %v", err)
+			}
+		}
+		rel := 'a' <= verb
+
+		switch verb {
+		case 'Z', 'z':
+			if !started {
+				return fmt.Errorf("path data: closepath before moveto")
+			}
+			closed = true
+			curX, curY = startX, startY
+			continue
+
+		case 'M', 'm':
+			x, y := args[0], args[1]
+			if rel {
+				x, y = x+curX, y+curY
+			}
+			switch {
+			case !started:
+				e.StartPath(0, float32(x-ox), float32(y-oy))
+				started = true
+			case closed && rel:
+				e.ClosePathRelMoveTo(float32(args[0]), float32(args[1]))
+			default:
+				e.ClosePathAbsMoveTo(float32(x-ox), float32(y-oy))
+			}
+			closed = false
+			curX, curY = x, y
+			startX, startY = x, y
+			// Further coordinate pairs are implicit lineto commands.
+			if rel {
+				verb = 'l'
+			} else {
+				verb = 'L'
+			}
+			continue
+		}
+
+		if !started {
+			return fmt.Errorf("path data: %q before moveto", verb)
+		}
+		if closed {
+			// Drawing after a closepath starts a new subpath at the same point.
+			e.ClosePathRelMoveTo(0, 0)
+			closed = false
+		}
+
+		// Update the current point before translating the arguments.
+		switch verb {
+		case 'H':
+			curX = args[0]
+		case 'h':
+			curX += args[0]
+		case 'V':
+			curY = args[0]
+		case 'v':
+			curY += args[0]
+		default:
+			if rel {
+				curX, curY = curX+args[n-2], curY+args[n-1]
+			} else {
+				curX, curY = args[n-2], args[n-1]
+			}
+		}
+		if !rel {
+			switch verb {
+			case 'H':
+				args[0] -= ox
+			case 'V':
+				args[0] -= oy
+			case 'A':
+				args[5] -= ox
+				args[6] -= oy
+			default:
+				for i := 0; i < n; i += 2 {
+					args[i] -= ox
+					args[i+1] -= oy
+				}
+			}
+		}
+
+		var a [7]float32
+		for i := range a {
+			a[i] = float32(args[i])
+		}
+		switch verb {
+		case 'H':
+			e.AbsHLineTo(a[0])
+		case 'h':
+			e.RelHLineTo(a[0])
+		case 'V':
+			e.AbsVLineTo(a[0])
+		case 'v':
+			e.RelVLineTo(a[0])
+		case 'L':
+			e.AbsLineTo(a[0], a[1])
+		case 'l':
+			e.RelLineTo(a[0], a[1])
+		case 'T':
+			e.AbsSmoothQuadTo(a[0], a[1])
+		case 't':
+			e.RelSmoothQuadTo(a[0], a[1])
+		case 'Q':
+			e.AbsQuadTo(a[0], a[1], a[2], a[3])
+		case 'q':
+			e.RelQuadTo(a[0], a[1], a[2], a[3])
+		case 'S':
+			e.AbsSmoothCubeTo(a[0], a[1], a[2], a[3])
+		case 's':
+			e.RelSmoothCubeTo(a[0], a[1], a[2], a[3])
+		case 'C':
+			e.AbsCubeTo(a[0], a[1], a[2], a[3], a[4], a[5])
+		case 'c':
+			e.RelCubeTo(a[0], a[1], a[2], a[3], a[4], a[5])
+		case 'A':
+			// IconVG measures the x axis rotation in units of 360 degrees.
+			e.AbsArcTo(a[0], a[1], a[2]/360, a[3] != 0, a[4] != 0, a[5], a[6])
+		case 'a':
+			e.RelArcTo(a[0], a[1], a[2]/360, a[3] != 0, a[4] != 0, a[5], a[6])
+		}
+	}
+	if started {
+		e.ClosePathEndPath()
+	}
+	return nil
+}
+
+// scanner reads the numbers in path data and similar attributes, which may
+// be separated by white space, commas or nothing at all, as in "1-2.5.5".
+type scanner struct {
+	s string
+	i int
+}
+
+// done skips any separators and reports whether the input is exhausted.
+func (sc *scanner) done() bool {
+	for ; sc.i < len(sc.s); sc.i++ {
+		switch sc.s[sc.i] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			continue
+		}
+		break
+	}
+	return sc.i >= len(sc.s)
+}
+
+func (sc *scanner) number() (float64, error) {
+	if sc.done() {
+		return 0, fmt.Errorf("missing number at end of %q", sc.s)
+	}
+	start, i := sc.i, sc.i
+	if c := sc.s[i]; c == '+' || c == '-' {
+		i++
+	}
+	digits := 0
+	for ; i < len(sc.s) && '0' <= sc.s[i] && sc.s[i] <= '9'; i++ {
+		digits++
+	}
+	if i < len(sc.s) && sc.s[i] == '.' {
+		for i++; i < len(sc.s) && '0' <= sc.s[i] && sc.s[i] <= '9'; i++ {
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, fmt.Errorf("expected a number at offset %d of %q", start, sc.s)
+	}
+	if i < len(sc.s) && (sc.s[i] == 'e' || sc.s[i] == 'E') {
+		j := i + 1
+		if j < len(sc.s) && (sc.s[j] == '+' || sc.s[j] == '-') {
+			j++
+		}
+		if j < len(sc.s) && '0' <= sc.s[j] && sc.s[j] <= '9' {
+			for i = j; i < len(sc.s) && '0' <= sc.s[i] && sc.s[i] <= '9'; i++ {
+			}
+		}
+	}
+	sc.i = i
+	return strconv.ParseFloat(sc.s[start:i], 64)
+}
+
+// flag reads an arc flag, which is a single 0 or 1 that need not be
+// separated from what follows.
+func (sc *scanner) flag() (float64, error) {
+	if sc.done() {
+		return 0, fmt.Errorf("missing flag at end of %q", sc.s)
+	}
+	switch sc.s[sc.i] {
+	case '0':
+		sc.i++
+		return 0, nil
+	case '1':
+		sc.i++
+		return 1, nil
+	}
+	return 0, fmt.Errorf("expected a flag at offset %d of %q", sc.i, sc.s)
+}
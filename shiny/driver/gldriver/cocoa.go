@@ -23,6 +23,7 @@ void flushContext(uintptr_t ctx);
 uintptr_t doNewWindow(int width, int height, char* title);
 void doShowWindow(uintptr_t id);
 void doCloseWindow(uintptr_t id);
+void doSetCursor(uintptr_t id, int c);
 uint64_t threadID();
 */
 import "C"
@@ -32,6 +33,7 @@ import (
 	"fmt"
 	"log"
 	"runtime"
+	"time"
 	"unsafe"
 
 	"golang.org/x/exp/shiny/driver/internal/lifecycler"
@@ -94,6 +96,10 @@ func closeWindow(id uintptr) {
 	C.doCloseWindow(C.uintptr_t(id))
 }
 
+func setCursor(id uintptr, c screen.Cursor) {
+	C.doSetCursor(C.uintptr_t(id), C.int(c))
+}
+
 var mainCallback func(screen.Screen)
 
 func main(f func(screen.Screen)) error {
@@ -171,7 +177,11 @@ func drawLoop(w *windowImpl, vba uintptr) {
 				}
 			}
 			C.flushContext(C.uintptr_t(w.ctx.(uintptr)))
-			w.publishDone <- screen.PublishResult{}
+			w.publishDone <- screen.PublishResult{
+				// The context's NSOpenGLCPSwapInterval is 1, so the
+				// flush is synchronized with the vertical retrace.
+				PresentTime: time.Now(),
+			}
 		}
 	}
 }
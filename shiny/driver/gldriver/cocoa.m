@@ -50,6 +50,7 @@ uint64 threadID() {
 
 @interface ScreenGLView : NSOpenGLView<NSWindowDelegate>
 {
+	NSCursor* cursor; // nil means the default cursor
 }
 @end
 
@@ -117,6 +118,20 @@ uint64 threadID() {
 	drawgl((GoUintptr)self);
 }
 
+- (void)setShinyCursor:(NSCursor*)c {
+	[c retain];
+	[cursor release];
+	cursor = c;
+	[self.window invalidateCursorRectsForView:self];
+}
+
+- (void)resetCursorRects {
+	[super resetCursorRects];
+	if (cursor != nil) {
+		[self addCursorRect:[self bounds] cursor:cursor];
+	}
+}
+
 - (void)mouseEventNS:(NSEvent *)theEvent {
 	NSPoint p = [theEvent locationInWindow];
 	double h = self.frame.size.height;
@@ -316,6 +331,35 @@ void doCloseWindow(uintptr_t viewID) {
 	});
 }
 
+// doSetCursor sets the cursor of the view to c, a screen.Cursor value.
+void doSetCursor(uintptr_t viewID, int c) {
+	ScreenGLView* view = (ScreenGLView*)viewID;
+	dispatch_async(dispatch_get_main_queue(), ^{
+		static NSCursor* blank = nil;
+		NSCursor* cursor = nil;
+		switch (c) {
+		case 1: // screen.CursorPointer
+			cursor = [NSCursor pointingHandCursor];
+			break;
+		case 2: // screen.CursorText
+			cursor = [NSCursor IBeamCursor];
+			break;
+		case 3: // screen.CursorCrosshair
+			cursor = [NSCursor crosshairCursor];
+			break;
+		case 4: // screen.CursorNone
+			if (blank == nil) {
+				NSImage* image = [[NSImage alloc] initWithSize:NSMakeSize(1, 1)];
+				blank = [[NSCursor alloc] initWithImage:image hotSpot:NSZeroPoint];
+				[image release];
+			}
+			cursor = blank;
+			break;
+		}
+		[view setShinyCursor:cursor];
+	});
+}
+
 void startDriver() {
 	[NSAutoreleasePool new];
 	[NSApplication sharedApplication];
@@ -25,5 +25,7 @@ func showWindow(id *windowImpl) {}
 func closeWindow(id uintptr)    {}
 func drawLoop(w *windowImpl)    {}
 
+func setCursor(id uintptr, c screen.Cursor) {}
+
 func surfaceCreate() error             { return errUnsupported }
 func main(f func(screen.Screen)) error { return errUnsupported }
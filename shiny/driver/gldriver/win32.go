@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"runtime"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"golang.org/x/exp/shiny/driver/internal/win32"
@@ -88,6 +89,10 @@ func showWindow(w *windowImpl) {
 
 func closeWindow(id uintptr) {} // TODO
 
+func setCursor(id uintptr, c screen.Cursor) {
+	win32.SetCursor(syscall.Handle(id), c)
+}
+
 func drawLoop(w *windowImpl) {
 	runtime.LockOSThread()
 
@@ -118,7 +123,11 @@ func drawLoop(w *windowImpl) {
 			if ret, _, _ := eglSwapBuffers.Call(display, surface); ret == 0 {
 				panic(fmt.Sprintf("eglSwapBuffers failed: %v", eglErr()))
 			}
-			w.publishDone <- screen.PublishResult{}
+			w.publishDone <- screen.PublishResult{
+				// The swap interval is 1, so eglSwapBuffers returns
+				// after the vertical sync that shows the frame.
+				PresentTime: time.Now(),
+			}
 		}
 	}
 }
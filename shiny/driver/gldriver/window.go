@@ -5,6 +5,7 @@
 package gldriver
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
@@ -324,6 +325,17 @@ func (w *windowImpl) Scale(dr image.Rectangle, src screen.Texture, sr image.Rect
 	drawer.Scale(w, dr, src, sr, op, opts)
 }
 
+func (w *windowImpl) Screenshot() (*image.RGBA, error) {
+	// TODO: read back the window's Framebuffer. After Publish swaps the
+	// buffers, the back buffer's contents are undefined, so the pixels would
+	// have to be read during Publish, before the swap.
+	return nil, fmt.Errorf("gldriver: screenshot: %w", screen.ErrUnsupported)
+}
+
+func (w *windowImpl) SetCursor(c screen.Cursor) {
+	setCursor(w.id, c)
+}
+
 func (w *windowImpl) mvp(tlx, tly, trx, try, blx, bly float64) f64.Aff3 {
 	w.szMu.Lock()
 	sz := w.sz
@@ -8,6 +8,7 @@
 #include <EGL/egl.h>
 #include <X11/Xlib.h>   // for Atom, Colormap, Display, Window
 #include <X11/Xutil.h>  // for XVisualInfo
+#include <X11/cursorfont.h>
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
@@ -233,6 +234,44 @@ doCloseWindow(uintptr_t id) {
 	XDestroyWindow(x_dpy, win);
 }
 
+// x_cursors holds the cursors created by doSetCursor, indexed by the
+// screen.Cursor value.
+Cursor x_cursors[5];
+
+// doSetCursor sets the cursor of the window to c, a screen.Cursor value.
+void
+doSetCursor(uintptr_t id, int c) {
+	Window win = (Window)(id);
+	if (c <= 0 || c >= 5) {
+		// screen.CursorDefault, or unknown. Use the parent window's cursor.
+		XUndefineCursor(x_dpy, win);
+		return;
+	}
+	if (!x_cursors[c]) {
+		switch (c) {
+		case 1: // screen.CursorPointer
+			x_cursors[c] = XCreateFontCursor(x_dpy, XC_hand2);
+			break;
+		case 2: // screen.CursorText
+			x_cursors[c] = XCreateFontCursor(x_dpy, XC_xterm);
+			break;
+		case 3: // screen.CursorCrosshair
+			x_cursors[c] = XCreateFontCursor(x_dpy, XC_crosshair);
+			break;
+		case 4: { // screen.CursorNone
+			// A cursor whose mask is all zeroes is invisible.
+			char data = 0;
+			XColor black = {0};
+			Pixmap p = XCreateBitmapFromData(x_dpy, win, &data, 1, 1);
+			x_cursors[c] = XCreatePixmapCursor(x_dpy, p, p, &black, &black, 0, 0);
+			XFreePixmap(x_dpy, p);
+			break;
+		}
+		}
+	}
+	XDefineCursor(x_dpy, win, x_cursors[c]);
+}
+
 uintptr_t
 doNewWindow(int width, int height, char* title, int title_len) {
 	XSetWindowAttributes attr;
@@ -22,6 +22,7 @@ void processEvents();
 void makeCurrent(uintptr_t ctx);
 void swapBuffers(uintptr_t ctx);
 void doCloseWindow(uintptr_t id);
+void doSetCursor(uintptr_t id, int c);
 uintptr_t doNewWindow(int width, int height, char* title, int title_len);
 uintptr_t doShowWindow(uintptr_t id);
 uintptr_t surfaceCreate();
@@ -97,6 +98,15 @@ func closeWindow(id uintptr) {
 	}
 }
 
+func setCursor(id uintptr, c screen.Cursor) {
+	uic <- uiClosure{
+		f: func() uintptr {
+			C.doSetCursor(C.uintptr_t(id), C.int(c))
+			return 0
+		},
+	}
+}
+
 func drawLoop(w *windowImpl) {
 	glcontextc <- w.ctx.(uintptr)
 	go func() {
@@ -165,7 +175,12 @@ func main(f func(screen.Screen)) error {
 			C.makeCurrent(C.uintptr_t(ctx))
 		case w := <-publishc:
 			C.swapBuffers(C.uintptr_t(w.ctx.(uintptr)))
-			w.publishDone <- screen.PublishResult{}
+			w.publishDone <- screen.PublishResult{
+				// EGL's default swap interval is 1, so the swap
+				// waits for the vertical sync and the frame is shown
+				// about now.
+				PresentTime: time.Now(),
+			}
 		case req := <-uic:
 			ret := req.f()
 			if req.retc != nil {
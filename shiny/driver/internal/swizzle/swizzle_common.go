@@ -29,3 +29,15 @@ func BGRA(p []byte) {
 		p[i+0], p[i+2] = p[i+2], p[i+0]
 	}
 }
+
+// BGRX converts a pixel buffer from other systems' BGRX byte order, whose
+// fourth byte is unused, to Go's RGBA byte order, with every pixel fully
+// opaque.
+//
+// It panics if the input slice length is not a multiple of 4.
+func BGRX(p []byte) {
+	BGRA(p)
+	for i := 3; i < len(p); i += 4 {
+		p[i] = 0xff
+	}
+}
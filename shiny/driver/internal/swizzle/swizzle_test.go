@@ -64,6 +64,30 @@ func TestBGRARandomInput(t *testing.T) {
 	}
 }
 
+func TestBGRX(t *testing.T) {
+	p := []byte{
+		0x10, 0x20, 0x30, 0x00,
+		0x40, 0x50, 0x60, 0x7f,
+		0x70, 0x80, 0x90, 0xff,
+	}
+	want := []byte{
+		0x30, 0x20, 0x10, 0xff,
+		0x60, 0x50, 0x40, 0xff,
+		0x90, 0x80, 0x70, 0xff,
+	}
+	BGRX(p)
+	if !bytes.Equal(p, want) {
+		t.Errorf("got % x, want % x", p, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("BGRX with a partial pixel: did not panic")
+		}
+	}()
+	BGRX(make([]byte, 6))
+}
+
 func pureGoBGRA(p []byte) {
 	if len(p)%4 != 0 {
 		return
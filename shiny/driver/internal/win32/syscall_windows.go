@@ -63,6 +63,7 @@ const (
 	_WM_KILLFOCUS        = 8
 	_WM_PAINT            = 15
 	_WM_CLOSE            = 16
+	_WM_SETCURSOR        = 32
 	_WM_WINDOWPOSCHANGED = 71
 	_WM_KEYDOWN          = 256
 	_WM_KEYUP            = 257
@@ -110,6 +111,13 @@ const (
 const (
 	_IDI_APPLICATION = 32512
 	_IDC_ARROW       = 32512
+	_IDC_IBEAM       = 32513
+	_IDC_CROSS       = 32515
+	_IDC_HAND        = 32649
+)
+
+const (
+	_HTCLIENT = 1
 )
 
 const (
@@ -179,6 +187,7 @@ func _HIWORD(l uintptr) uint16 {
 //sys	_PostMessage(hwnd syscall.Handle, uMsg uint32, wParam uintptr, lParam uintptr) (lResult bool) = user32.PostMessageW
 //sys   _PostQuitMessage(exitCode int32) = user32.PostQuitMessage
 //sys	_RegisterClass(wc *_WNDCLASS) (atom uint16, err error) = user32.RegisterClassW
+//sys	_SetCursor(cursor syscall.Handle) (prev syscall.Handle) = user32.SetCursor
 //sys	_ShowWindow(hwnd syscall.Handle, cmdshow int32) (wasvisible bool) = user32.ShowWindow
 //sys	_ScreenToClient(hwnd syscall.Handle, lpPoint *_POINT) (ok bool) = user32.ScreenToClient
 //sys   _ToUnicodeEx(wVirtKey uint32, wScanCode uint32, lpKeyState *byte, pwszBuff *uint16, cchBuff int32, wFlags uint32, dwhkl syscall.Handle) (ret int32) = user32.ToUnicodeEx
@@ -36,6 +36,7 @@ const (
 	msgCreateWindow = _WM_USER + iota
 	msgMainCallback
 	msgShow
+	msgSetCursor
 	msgQuit
 	msgLast
 )
@@ -119,6 +120,54 @@ func Release(hwnd syscall.Handle) {
 	SendMessage(hwnd, _WM_CLOSE, 0, 0)
 }
 
+// SetCursor sets the cursor shown when the pointer is over the client area
+// of the window. It takes effect when the pointer next moves.
+func SetCursor(hwnd syscall.Handle, c screen.Cursor) {
+	SendMessage(hwnd, msgSetCursor, uintptr(c), 0)
+}
+
+// cursors holds the cursors set by SetCursor, for windows whose cursor is
+// not the default. It is only accessed on the main thread.
+var cursors = map[syscall.Handle]screen.Cursor{}
+
+func storeCursor(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResult uintptr) {
+	if c := screen.Cursor(wParam); c != screen.CursorDefault {
+		cursors[hwnd] = c
+	} else {
+		delete(cursors, hwnd)
+	}
+	return 0
+}
+
+func sendSetCursor(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResult uintptr) {
+	c, ok := cursors[hwnd]
+	if !ok || lParam&0xffff != _HTCLIENT {
+		return _DefWindowProc(hwnd, uMsg, wParam, lParam)
+	}
+	name := uintptr(0)
+	switch c {
+	case screen.CursorPointer:
+		name = _IDC_HAND
+	case screen.CursorText:
+		name = _IDC_IBEAM
+	case screen.CursorCrosshair:
+		name = _IDC_CROSS
+	case screen.CursorNone:
+		// A zero cursor hides the cursor.
+	default:
+		return _DefWindowProc(hwnd, uMsg, wParam, lParam)
+	}
+	h := syscall.Handle(0)
+	if name != 0 {
+		var err error
+		if h, err = _LoadCursor(0, name); err != nil {
+			return _DefWindowProc(hwnd, uMsg, wParam, lParam)
+		}
+	}
+	_SetCursor(h)
+	return 1
+}
+
 func sendFocus(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResult uintptr) {
 	switch uMsg {
 	case _WM_SETFOCUS:
@@ -171,6 +220,7 @@ func sendClose(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) (lResul
 	// To intercept destruction of the window, return 0 and call
 	// DestroyWindow when appropriate.
 	LifecycleEvent(hwnd, lifecycle.StageDead)
+	delete(cursors, hwnd)
 	return _DefWindowProc(hwnd, uMsg, wParam, lParam)
 }
 
@@ -319,6 +369,8 @@ var windowMsgs = map[uint32]func(hwnd syscall.Handle, uMsg uint32, wParam, lPara
 	_WM_KILLFOCUS:        sendFocus,
 	_WM_PAINT:            sendPaint,
 	msgShow:              sendShow,
+	msgSetCursor:         storeCursor,
+	_WM_SETCURSOR:        sendSetCursor,
 	_WM_WINDOWPOSCHANGED: sendSizeEvent,
 	_WM_CLOSE:            sendClose,
 
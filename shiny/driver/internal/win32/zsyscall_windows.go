@@ -58,6 +58,7 @@ var (
 	procPostMessageW      = moduser32.NewProc("PostMessageW")
 	procPostQuitMessage   = moduser32.NewProc("PostQuitMessage")
 	procRegisterClassW    = moduser32.NewProc("RegisterClassW")
+	procSetCursor         = moduser32.NewProc("SetCursor")
 	procShowWindow        = moduser32.NewProc("ShowWindow")
 	procScreenToClient    = moduser32.NewProc("ScreenToClient")
 	procToUnicodeEx       = moduser32.NewProc("ToUnicodeEx")
@@ -262,6 +263,12 @@ func _RegisterClass(wc *_WNDCLASS) (atom uint16, err error) {
 	return
 }
 
+func _SetCursor(cursor syscall.Handle) (prev syscall.Handle) {
+	r0, _, _ := syscall.Syscall(procSetCursor.Addr(), 1, uintptr(cursor), 0, 0)
+	prev = syscall.Handle(r0)
+	return
+}
+
 func _ShowWindow(hwnd syscall.Handle, cmdshow int32) (wasvisible bool) {
 	r0, _, _ := syscall.Syscall(procShowWindow.Addr(), 2, uintptr(hwnd), uintptr(cmdshow), 0)
 	wasvisible = r0 != 0
@@ -65,6 +65,8 @@ func main(f func(screen.Screen)) error {
 		done            = make(chan struct{})
 		newWindowCh     = make(chan newWindowReq, 1)
 		releaseWindowCh = make(chan releaseWindowReq, 1)
+		setCursorCh     = make(chan setCursorReq, 1)
+		cursors         = map[screen.Cursor]*glfw.Cursor{}
 	)
 	go func() {
 		f(&screenImpl{
@@ -78,11 +80,14 @@ func main(f func(screen.Screen)) error {
 		case <-done:
 			return nil
 		case req := <-newWindowCh:
-			w, err := newWindow(device, releaseWindowCh, req.opts)
+			w, err := newWindow(device, releaseWindowCh, setCursorCh, req.opts)
 			req.respCh <- newWindowResp{w, err}
 		case req := <-releaseWindowCh:
 			req.window.Destroy()
 			req.respCh <- struct{}{}
+		case req := <-setCursorCh:
+			setCursor(req.window, req.cursor, cursors)
+			req.respCh <- struct{}{}
 		default:
 			glfw.WaitEvents()
 		}
@@ -104,9 +109,44 @@ type releaseWindowReq struct {
 	respCh chan struct{}
 }
 
+type setCursorReq struct {
+	window *glfw.Window
+	cursor screen.Cursor
+	respCh chan struct{}
+}
+
+// setCursor sets the cursor of a GLFW window, creating the GLFW cursor if it
+// is not in cursors.
+// It must be called on the main thread.
+func setCursor(window *glfw.Window, c screen.Cursor, cursors map[screen.Cursor]*glfw.Cursor) {
+	if c == screen.CursorNone {
+		window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+		return
+	}
+	window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	var shape glfw.StandardCursor
+	switch c {
+	case screen.CursorPointer:
+		shape = glfw.HandCursor
+	case screen.CursorText:
+		shape = glfw.IBeamCursor
+	case screen.CursorCrosshair:
+		shape = glfw.CrosshairCursor
+	default:
+		window.SetCursor(nil)
+		return
+	}
+	gc, ok := cursors[c]
+	if !ok {
+		gc = glfw.CreateStandardCursor(shape)
+		cursors[c] = gc
+	}
+	window.SetCursor(gc)
+}
+
 // newWindow creates a new GLFW window.
 // It must be called on the main thread.
-func newWindow(device mtl.Device, releaseWindowCh chan releaseWindowReq, opts *screen.NewWindowOptions) (screen.Window, error) {
+func newWindow(device mtl.Device, releaseWindowCh chan releaseWindowReq, setCursorCh chan setCursorReq, opts *screen.NewWindowOptions) (screen.Window, error) {
 	width, height := optsSize(opts)
 	window, err := glfw.CreateWindow(width, height, opts.GetTitle(), nil, nil)
 	if err != nil {
@@ -126,6 +166,7 @@ func newWindow(device mtl.Device, releaseWindowCh chan releaseWindowReq, opts *s
 		device:          device,
 		window:          window,
 		releaseWindowCh: releaseWindowCh,
+		setCursorCh:     setCursorCh,
 		ml:              ml,
 		cq:              device.MakeCommandQueue(),
 	}
@@ -28,6 +28,7 @@ type windowImpl struct {
 	device          mtl.Device
 	window          *glfw.Window
 	releaseWindowCh chan releaseWindowReq
+	setCursorCh     chan setCursorReq
 	ml              coreanim.MetalLayer
 	cq              mtl.CommandQueue
 
@@ -48,6 +49,17 @@ func (w *windowImpl) Release() {
 	<-respCh
 }
 
+func (w *windowImpl) SetCursor(c screen.Cursor) {
+	respCh := make(chan struct{})
+	w.setCursorCh <- setCursorReq{
+		window: w.window,
+		cursor: c,
+		respCh: respCh,
+	}
+	glfw.PostEmptyEvent() // Break main loop out of glfw.WaitEvents so it can receive on setCursorCh.
+	<-respCh
+}
+
 func (w *windowImpl) NextEvent() interface{} {
 	e := w.Deque.NextEvent()
 	if sz, ok := e.(size.Event); ok {
@@ -94,6 +106,14 @@ func (w *windowImpl) Publish() screen.PublishResult {
 	return screen.PublishResult{}
 }
 
+func (w *windowImpl) Screenshot() (*image.RGBA, error) {
+	// The window's contents are drawn to w.rgba before being copied to a
+	// texture in Publish, so there is no need to read back the texture.
+	m := image.NewRGBA(w.rgba.Rect)
+	copy(m.Pix, w.rgba.Pix)
+	return m, nil
+}
+
 func (w *windowImpl) Upload(dp image.Point, src screen.Buffer, sr image.Rectangle) {
 	draw.Draw(w.rgba, sr.Sub(sr.Min).Add(dp), src.RGBA(), sr.Min, draw.Src)
 }
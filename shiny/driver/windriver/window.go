@@ -170,6 +170,19 @@ func (w *windowImpl) Publish() screen.PublishResult {
 	return screen.PublishResult{}
 }
 
+func (w *windowImpl) Screenshot() (*image.RGBA, error) {
+	c := &cmd{id: cmdScreenshot}
+	win32.SendMessage(w.hwnd, msgCmd, 0, uintptr(unsafe.Pointer(c)))
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.rgba, nil
+}
+
+func (w *windowImpl) SetCursor(c screen.Cursor) {
+	win32.SetCursor(w.hwnd, c)
+}
+
 func init() {
 	send := func(hwnd syscall.Handle, e interface{}) {
 		theScreen.mu.Lock()
@@ -227,6 +240,7 @@ type cmd struct {
 	op      draw.Op
 	texture syscall.Handle
 	buffer  *bufferImpl
+	rgba    *image.RGBA
 }
 
 const (
@@ -234,6 +248,7 @@ const (
 	cmdFill
 	cmdUpload
 	cmdDrawUniform
+	cmdScreenshot
 )
 
 var msgCmd = win32.AddWindowMsg(handleCmd)
@@ -266,6 +281,16 @@ func handleCmd(hwnd syscall.Handle, uMsg uint32, wParam, lParam uintptr) {
 		// TODO: adjust if dp is outside dst bounds, or sr is outside buffer bounds.
 		dr := c.sr.Add(c.dp.Sub(c.sr.Min))
 		c.err = copyBitmapToDC(dc, dr, c.buffer.hbitmap, c.sr, draw.Src)
+	case cmdScreenshot:
+		theScreen.mu.Lock()
+		w, ok := theScreen.windows[hwnd]
+		theScreen.mu.Unlock()
+		if !ok {
+			// The window was closed after the screenshot was requested.
+			c.err = fmt.Errorf("windriver: screenshot of a released window")
+			break
+		}
+		c.rgba, c.err = readDC(dc, image.Point{w.sz.WidthPx, w.sz.HeightPx})
 	default:
 		c.err = fmt.Errorf("unknown command id=%d", c.id)
 	}
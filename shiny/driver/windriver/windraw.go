@@ -80,6 +80,47 @@ func copyBitmapToDC(dc syscall.Handle, dr image.Rectangle, src syscall.Handle, s
 	}
 }
 
+// readDC returns a copy of the top-left part of dc with the given size.
+func readDC(dc syscall.Handle, size image.Point) (ret *image.RGBA, retErr error) {
+	if size.X <= 0 || size.Y <= 0 {
+		return image.NewRGBA(image.Rectangle{}), nil
+	}
+	bitmap, bitvalues, err := mkbitmap(size)
+	if err != nil {
+		return nil, err
+	}
+	defer _DeleteObject(bitmap)
+
+	memdc, err := _CreateCompatibleDC(dc)
+	if err != nil {
+		return nil, err
+	}
+	defer _DeleteDC(memdc)
+
+	prev, err := _SelectObject(memdc, bitmap)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, err2 := _SelectObject(memdc, prev)
+		if retErr == nil {
+			retErr = err2
+		}
+	}()
+
+	if err := _BitBlt(memdc, 0, 0, int32(size.X), int32(size.Y), dc, 0, 0, _SRCCOPY); err != nil {
+		return nil, err
+	}
+
+	m := image.NewRGBA(image.Rectangle{Max: size})
+	copy(m.Pix, unsafe.Slice(bitvalues, len(m.Pix)))
+	// The bitmap is in BGRX order, and the window has no alpha channel.
+	for i := 0; i < len(m.Pix); i += 4 {
+		m.Pix[i+0], m.Pix[i+2], m.Pix[i+3] = m.Pix[i+2], m.Pix[i+0], 0xff
+	}
+	return m, nil
+}
+
 func fill(dc syscall.Handle, dr image.Rectangle, c color.Color, op draw.Op) error {
 	r, g, b, a := c.RGBA()
 	r >>= 8
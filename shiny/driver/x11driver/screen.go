@@ -57,6 +57,12 @@ type screenImpl struct {
 	uniformC  render.Color
 	uniformP  render.Picture
 
+	// cursors holds the X11 cursors for the screen.Cursor values, created
+	// on first use. The zero xproto.Cursor, None, means the parent window's
+	// cursor, which is the default cursor.
+	cursorMu sync.Mutex
+	cursors  map[screen.Cursor]xproto.Cursor
+
 	mu              sync.Mutex
 	buffers         map[shm.Seg]*bufferImpl
 	uploads         map[uint16]chan struct{}
@@ -488,6 +494,74 @@ func (s *screenImpl) initAtoms() (err error) {
 	return nil
 }
 
+// Glyphs in the standard X11 cursor font, from <X11/cursorfont.h>.
+const (
+	xcCrosshair = 34
+	xcHand2     = 60
+	xcXterm     = 152
+)
+
+// cursor returns the X11 cursor for c, creating it if necessary.
+func (s *screenImpl) cursor(c screen.Cursor) (xproto.Cursor, error) {
+	glyph := uint16(0)
+	switch c {
+	case screen.CursorPointer:
+		glyph = xcHand2
+	case screen.CursorText:
+		glyph = xcXterm
+	case screen.CursorCrosshair:
+		glyph = xcCrosshair
+	case screen.CursorNone:
+		// No glyph. The cursor is made from a blank pixmap.
+	default:
+		return 0, nil
+	}
+
+	s.cursorMu.Lock()
+	defer s.cursorMu.Unlock()
+	if xc, ok := s.cursors[c]; ok {
+		return xc, nil
+	}
+	xc, err := xproto.NewCursorId(s.xc)
+	if err != nil {
+		return 0, fmt.Errorf("x11driver: xproto.NewCursorId failed: %v", err)
+	}
+	if glyph != 0 {
+		const name = "cursor"
+		xf, err := xproto.NewFontId(s.xc)
+		if err != nil {
+			return 0, fmt.Errorf("x11driver: xproto.NewFontId failed: %v", err)
+		}
+		xproto.OpenFont(s.xc, xf, uint16(len(name)), name)
+		// The mask glyph follows the source glyph. The cursor is black with
+		// a white outline.
+		xproto.CreateGlyphCursor(s.xc, xc, xf, xf, glyph, glyph+1, 0, 0, 0, 0xffff, 0xffff, 0xffff)
+		xproto.CloseFont(s.xc, xf)
+	} else {
+		xm, err := xproto.NewPixmapId(s.xc)
+		if err != nil {
+			return 0, fmt.Errorf("x11driver: xproto.NewPixmapId failed: %v", err)
+		}
+		xg, err := xproto.NewGcontextId(s.xc)
+		if err != nil {
+			return 0, fmt.Errorf("x11driver: xproto.NewGcontextId failed: %v", err)
+		}
+		// The X11 server doesn't zero-initialize the pixmap, and a zero mask
+		// makes every pixel of the cursor transparent.
+		xproto.CreatePixmap(s.xc, 1, xm, xproto.Drawable(s.xsi.Root), 1, 1)
+		xproto.CreateGC(s.xc, xg, xproto.Drawable(xm), xproto.GcForeground, []uint32{0})
+		xproto.PolyFillRectangle(s.xc, xproto.Drawable(xm), xg, []xproto.Rectangle{{Width: 1, Height: 1}})
+		xproto.CreateCursor(s.xc, xc, xm, xm, 0, 0, 0, 0, 0, 0, 0, 0)
+		xproto.FreeGC(s.xc, xg)
+		xproto.FreePixmap(s.xc, xm)
+	}
+	if s.cursors == nil {
+		s.cursors = map[screen.Cursor]xproto.Cursor{}
+	}
+	s.cursors[c] = xc
+	return xc, nil
+}
+
 func (s *screenImpl) internAtom(name string) (xproto.Atom, error) {
 	r, err := xproto.InternAtom(s.xc, false, uint16(len(name)), name).Reply()
 	if err != nil {
@@ -7,6 +7,7 @@ package x11driver
 // TODO: implement a back buffer.
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"image/draw"
@@ -19,6 +20,7 @@ import (
 	"golang.org/x/exp/shiny/driver/internal/drawer"
 	"golang.org/x/exp/shiny/driver/internal/event"
 	"golang.org/x/exp/shiny/driver/internal/lifecycler"
+	"golang.org/x/exp/shiny/driver/internal/swizzle"
 	"golang.org/x/exp/shiny/driver/internal/x11key"
 	"golang.org/x/exp/shiny/screen"
 	"golang.org/x/image/math/f64"
@@ -106,6 +108,43 @@ func (w *windowImpl) Publish() screen.PublishResult {
 	return screen.PublishResult{}
 }
 
+func (w *windowImpl) Screenshot() (*image.RGBA, error) {
+	xd := xproto.Drawable(w.xw)
+	g, err := xproto.GetGeometry(w.s.xc, xd).Reply()
+	if err != nil {
+		return nil, err
+	}
+	m := image.NewRGBA(image.Rect(0, 0, int(g.Width), int(g.Height)))
+	if m.Rect.Empty() {
+		return m, nil
+	}
+	if g.Depth != 24 && g.Depth != 32 {
+		return nil, fmt.Errorf("x11driver: screenshot of a window with depth %d: %w", g.Depth, screen.ErrUnsupported)
+	}
+	// There is no back buffer, so the window's contents are read directly.
+	r, err := xproto.GetImage(w.s.xc, xproto.ImageFormatZPixmap, xd,
+		0, 0, g.Width, g.Height, 0xffffffff).Reply()
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Data) < len(m.Pix) {
+		return nil, fmt.Errorf("x11driver: screenshot has %d bytes, want %d", len(r.Data), len(m.Pix))
+	}
+	// The image is in BGRX order, like the upload buffers.
+	copy(m.Pix, r.Data)
+	swizzle.BGRX(m.Pix)
+	return m, nil
+}
+
+func (w *windowImpl) SetCursor(c screen.Cursor) {
+	xc, err := w.s.cursor(c)
+	if err != nil {
+		// Like the X11 requests below, a failure leaves the cursor unchanged.
+		return
+	}
+	xproto.ChangeWindowAttributes(w.s.xc, w.xw, xproto.CwCursor, []uint32{uint32(xc)})
+}
+
 func (w *windowImpl) handleConfigureNotify(ev xproto.ConfigureNotifyEvent) {
 	// TODO: does the order of these lifecycle and size events matter? Should
 	// they really be a single, atomic event?
@@ -48,6 +48,8 @@ func main() {
 			log.Fatal(err)
 		}
 		defer w.Release()
+		// Stones are placed at the intersection nearest the pointer.
+		w.SetCursor(screen.CursorCrosshair)
 
 		var b screen.Buffer
 		defer func() {
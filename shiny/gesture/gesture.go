@@ -103,6 +103,38 @@ func (t Type) String() string {
 	}
 }
 
+// DragPhase is the phase of a drag gesture.
+type DragPhase uint8
+
+const (
+	// DragNone means that the event is not part of a drag.
+	DragNone DragPhase = 0
+	// DragStart is when the gesture is first recognized as a drag, once it
+	// moves further than a threshold distance from its initial position. A
+	// gesture that ends before then is a tap, not a drag.
+	DragStart DragPhase = 1
+	// DragMove is each subsequent movement of the drag.
+	DragMove DragPhase = 2
+	// DragEnd is when the button or touch that started the drag is
+	// released.
+	DragEnd DragPhase = 3
+)
+
+func (p DragPhase) String() string {
+	switch p {
+	case DragNone:
+		return "None"
+	case DragStart:
+		return "Start"
+	case DragMove:
+		return "Move"
+	case DragEnd:
+		return "End"
+	default:
+		return fmt.Sprintf("gesture.DragPhase(%d)", p)
+	}
+}
+
 // Point is a mouse or touch location, in pixels.
 type Point struct {
 	X, Y float32
@@ -141,6 +173,35 @@ type Event struct {
 	// TODO: include the mouse Button and key Modifiers?
 }
 
+// DragPhase returns the drag phase of the event. A drag's events are, in
+// order, a TypeIsDrag event (DragStart), zero or more TypeDrag events
+// (DragMove) and a TypeEnd event (DragEnd), all with the Drag field set.
+// Other events are DragNone.
+func (e Event) DragPhase() DragPhase {
+	if !e.Drag {
+		return DragNone
+	}
+	switch e.Type {
+	case TypeIsDrag:
+		return DragStart
+	case TypeDrag:
+		return DragMove
+	case TypeEnd:
+		return DragEnd
+	}
+	return DragNone
+}
+
+// Delta returns the accumulated movement since the gesture started, which is
+// CurrentPos minus InitialPos. For a drag, this includes the movement before
+// the drag was recognized.
+func (e Event) Delta() Point {
+	return Point{
+		X: e.CurrentPos.X - e.InitialPos.X,
+		Y: e.CurrentPos.Y - e.InitialPos.Y,
+	}
+}
+
 type internalEvent struct {
 	eventFilter *EventFilter
 
@@ -0,0 +1,100 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gesture
+
+import (
+	"testing"
+
+	"golang.org/x/mobile/event/mouse"
+)
+
+// testDeque is a screen.EventDeque that records the gesture events sent to
+// it. Internal events, which schedule timeouts, are discarded.
+type testDeque struct {
+	// pending holds the events sent during one call to EventFilter.Filter,
+	// in the order that NextEvent would return them.
+	pending []Event
+	events  []Event
+}
+
+func (q *testDeque) Send(e interface{}) {
+	if e, ok := e.(Event); ok {
+		q.pending = append(q.pending, e)
+	}
+}
+
+func (q *testDeque) SendFirst(e interface{}) {
+	if e, ok := e.(Event); ok {
+		q.pending = append([]Event{e}, q.pending...)
+	}
+}
+
+func (q *testDeque) NextEvent() interface{} { panic("unimplemented") }
+
+func (q *testDeque) flush() {
+	q.events = append(q.events, q.pending...)
+	q.pending = nil
+}
+
+func TestDrag(t *testing.T) {
+	q := &testDeque{}
+	f := &EventFilter{EventDeque: q}
+	for _, e := range []mouse.Event{
+		{X: 100, Y: 100, Button: mouse.ButtonLeft, Direction: mouse.DirPress},
+		{X: 104, Y: 102, Direction: mouse.DirNone}, // Within the threshold.
+		{X: 120, Y: 95, Direction: mouse.DirNone},
+		{X: 130, Y: 80, Direction: mouse.DirNone},
+		{X: 131, Y: 81, Button: mouse.ButtonLeft, Direction: mouse.DirRelease},
+	} {
+		f.Filter(e)
+		q.flush()
+	}
+
+	want := []struct {
+		typ   Type
+		phase DragPhase
+		delta Point
+	}{
+		{TypeStart, DragNone, Point{0, 0}},
+		{TypeIsDrag, DragStart, Point{20, -5}},
+		{TypeDrag, DragMove, Point{20, -5}},
+		{TypeDrag, DragMove, Point{30, -20}},
+		{TypeEnd, DragEnd, Point{31, -19}},
+	}
+	if len(q.events) != len(want) {
+		t.Fatalf("got %d events, want %d: %v", len(q.events), len(want), q.events)
+	}
+	for i, e := range q.events {
+		w := want[i]
+		if e.Type != w.typ || e.DragPhase() != w.phase || e.Delta() != w.delta {
+			t.Errorf("event #%d: got %v, %v, %v, want %v, %v, %v",
+				i, e.Type, e.DragPhase(), e.Delta(), w.typ, w.phase, w.delta)
+		}
+	}
+}
+
+func TestTapIsNotDrag(t *testing.T) {
+	q := &testDeque{}
+	f := &EventFilter{EventDeque: q}
+	for _, e := range []mouse.Event{
+		{X: 100, Y: 100, Button: mouse.ButtonLeft, Direction: mouse.DirPress},
+		{X: 105, Y: 95, Direction: mouse.DirNone},
+		{X: 105, Y: 95, Button: mouse.ButtonLeft, Direction: mouse.DirRelease},
+	} {
+		f.Filter(e)
+		q.flush()
+	}
+
+	var types []Type
+	for _, e := range q.events {
+		if e.DragPhase() != DragNone {
+			t.Errorf("%v event: got drag phase %v, want None", e.Type, e.DragPhase())
+		}
+		types = append(types, e.Type)
+	}
+	if len(types) != 2 || types[0] != TypeStart || types[1] != TypeTap {
+		t.Errorf("got event types %v, want [Start Tap]", types)
+	}
+}
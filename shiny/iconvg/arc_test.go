@@ -0,0 +1,84 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestArcToBeziersQuarterCircle(t *testing.T) {
+	// A quarter circle of radius 10, centered on the origin, from (10, 0) to
+	// (0, 10), is approximated by one cubic Bézier curve whose control points
+	// are k*10 along the tangents at each end.
+	k := float32(4 * (math.Sqrt2 - 1) / 3)
+	got := ArcToBeziers(10, 0, 10, 10, 0, false, true, 0, 10)
+	want := [6]float32{10, 10 * k, 10 * k, 10, 0, 10}
+	if len(got) != 1 {
+		t.Fatalf("got %d curves, want 1", len(got))
+	}
+	for i := range want {
+		if d := got[0][i] - want[i]; d < -1e-4 || d > +1e-4 {
+			t.Fatalf("got %v, want %v", got[0], want)
+		}
+	}
+}
+
+func TestArcToBeziers(t *testing.T) {
+	testCases := []struct {
+		desc             string
+		x0, y0           float32
+		rx, ry, rotation float32
+		largeArc, sweep  bool
+		x, y             float32
+		wantN            int
+		cx, cy           float32 // the center of the circle, if rx == ry
+	}{
+		{"small sweep", 10, 0, 10, 10, 0, false, true, 0, 10, 1, 0, 0},
+		{"small counter-sweep", 10, 0, 10, 10, 0, false, false, 0, 10, 1, 10, 10},
+		{"large sweep", 10, 0, 10, 10, 0, true, true, 0, 10, 3, 10, 10},
+		{"large counter-sweep", 10, 0, 10, 10, 0, true, false, 0, 10, 3, 0, 0},
+		{"semicircle", -5, 3, 5, 5, 0, false, true, 5, 3, 2, 0, 3},
+		{"radii too small", -5, 3, 1, 1, 0, false, true, 5, 3, 2, 0, 3},
+		{"rotated ellipse", 0, 0, 8, 4, 0.125, true, true, 6, -2, 3, 0, 0},
+	}
+	for _, tc := range testCases {
+		got := ArcToBeziers(tc.x0, tc.y0, tc.rx, tc.ry, tc.rotation, tc.largeArc, tc.sweep, tc.x, tc.y)
+		if len(got) != tc.wantN {
+			t.Errorf("%s: got %d curves, want %d", tc.desc, len(got), tc.wantN)
+			continue
+		}
+		if c := got[len(got)-1]; !near(c[4], tc.x) || !near(c[5], tc.y) {
+			t.Errorf("%s: ends at (%v, %v), want (%v, %v)", tc.desc, c[4], c[5], tc.x, tc.y)
+		}
+		if tc.rx != tc.ry {
+			continue
+		}
+		// Each curve's midpoint is on the circle.
+		x0, y0 := tc.x0, tc.y0
+		for i, c := range got {
+			mx := (x0 + 3*c[0] + 3*c[2] + c[4]) / 8
+			my := (y0 + 3*c[1] + 3*c[3] + c[5]) / 8
+			r := float32(math.Hypot(float64(mx-tc.cx), float64(my-tc.cy)))
+			if want := float32(math.Hypot(float64(tc.x0-tc.cx), float64(tc.y0-tc.cy))); !near(r, want) {
+				t.Errorf("%s: curve #%d: midpoint (%v, %v) is %v from the center, want %v", tc.desc, i, mx, my, r, want)
+			}
+			x0, y0 = c[4], c[5]
+		}
+	}
+
+	// Degenerate arcs are lines.
+	if got := ArcToBeziers(0, 0, 0, 10, 0, false, true, 10, 10); got != nil {
+		t.Errorf("zero radius: got %v, want nil", got)
+	}
+	if got := ArcToBeziers(5, 5, 10, 10, 0, false, true, 5, 5); got != nil {
+		t.Errorf("same end points: got %v, want nil", got)
+	}
+}
+
+func near(x, y float32) bool {
+	d := x - y
+	return -1e-2 < d && d < +1e-2
+}
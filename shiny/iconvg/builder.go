@@ -0,0 +1,204 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"errors"
+)
+
+var (
+	errBuilderNoPath       = errors.New("iconvg: builder drawing op outside of a path")
+	errBuilderNoMoveTo     = errors.New("iconvg: builder path has no move-to")
+	errBuilderUnclosedPath = errors.New("iconvg: builder path is not closed")
+)
+
+type builderState uint8
+
+const (
+	builderStateIdle builderState = iota
+	builderStatePath
+	builderStateSubpath
+)
+
+// Builder is a higher level wrapper around an Encoder for constructing paths.
+// Each method returns the Builder itself, so that calls can be chained:
+//
+//	b.Path(0).MoveTo(-8, -8).HLineTo(8).VLineTo(8).HLineTo(-8).Close()
+//
+// The first error encountered is sticky: subsequent methods are no-ops and
+// the error is returned by Err and Bytes. Styling ops, such as setting color
+// registers, are made by calling the Encoder's methods directly, outside of a
+// path.
+type Builder struct {
+	e     *Encoder
+	err   error
+	adj   uint8
+	state builderState
+}
+
+// NewBuilder returns a Builder that appends to e.
+func NewBuilder(e *Encoder) *Builder {
+	return &Builder{e: e}
+}
+
+// Encoder returns the Encoder that b appends to.
+func (b *Builder) Encoder() *Encoder { return b.e }
+
+// Err returns the first error encountered, if any, including a path that was
+// started but not closed.
+func (b *Builder) Err() error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.state != builderStateIdle {
+		return errBuilderUnclosedPath
+	}
+	return nil
+}
+
+// Bytes returns the encoded form, as per the Encoder's Bytes method.
+func (b *Builder) Bytes() ([]byte, error) {
+	if err := b.Err(); err != nil {
+		return nil, err
+	}
+	return b.e.Bytes()
+}
+
+// Path starts a path filled with CREG[CSEL-adj]. It must be followed by
+// MoveTo or RelMoveTo, and ended by Close.
+func (b *Builder) Path(adj uint8) *Builder {
+	if b.err != nil {
+		return b
+	}
+	if b.state != builderStateIdle {
+		b.err = errBuilderUnclosedPath
+		return b
+	}
+	b.adj = adj
+	b.state = builderStatePath
+	return b
+}
+
+// MoveTo starts a new subpath at (x, y), closing any previous subpath of the
+// current path.
+func (b *Builder) MoveTo(x, y float32) *Builder {
+	return b.moveTo(false, x, y)
+}
+
+// RelMoveTo is like MoveTo but relative to the current point. For the first
+// subpath of a path, it is relative to the origin.
+func (b *Builder) RelMoveTo(x, y float32) *Builder {
+	return b.moveTo(true, x, y)
+}
+
+func (b *Builder) moveTo(rel bool, x, y float32) *Builder {
+	if b.err != nil {
+		return b
+	}
+	switch b.state {
+	case builderStateIdle:
+		b.err = errBuilderNoPath
+	case builderStatePath:
+		b.e.StartPath(b.adj, x, y)
+		b.state = builderStateSubpath
+	case builderStateSubpath:
+		if rel {
+			b.e.ClosePathRelMoveTo(x, y)
+		} else {
+			b.e.ClosePathAbsMoveTo(x, y)
+		}
+	}
+	return b
+}
+
+// Close closes the current subpath and ends the path started by Path.
+func (b *Builder) Close() *Builder {
+	if b.err != nil {
+		return b
+	}
+	switch b.state {
+	case builderStateIdle:
+		b.err = errBuilderNoPath
+		return b
+	case builderStatePath:
+		b.err = errBuilderNoMoveTo
+		return b
+	}
+	b.e.ClosePathEndPath()
+	b.state = builderStateIdle
+	return b
+}
+
+// Rect appends a closed path for the axis-aligned rectangle from (minX, minY)
+// to (maxX, maxY), filled with CREG[CSEL-adj], as per Encoder.AppendRect.
+func (b *Builder) Rect(adj uint8, minX, minY, maxX, maxY float32) *Builder {
+	return b.Path(adj).MoveTo(minX, minY).HLineTo(maxX).VLineTo(maxY).HLineTo(minX).Close()
+}
+
+// Circle appends a closed path for the circle with center (cx, cy) and radius
+// r, filled with CREG[CSEL-adj], as per Encoder.AppendCircle.
+func (b *Builder) Circle(adj uint8, cx, cy, r float32) *Builder {
+	return b.Path(adj).MoveTo(cx+r, cy).
+		ArcTo(r, r, 0, false, true, cx-r, cy).
+		ArcTo(r, r, 0, false, true, cx+r, cy).
+		Close()
+}
+
+func (b *Builder) HLineTo(x float32) *Builder    { return b.draw('H', x, 0, 0, 0, 0, 0) }
+func (b *Builder) RelHLineTo(x float32) *Builder { return b.draw('h', x, 0, 0, 0, 0, 0) }
+func (b *Builder) VLineTo(y float32) *Builder    { return b.draw('V', y, 0, 0, 0, 0, 0) }
+func (b *Builder) RelVLineTo(y float32) *Builder { return b.draw('v', y, 0, 0, 0, 0, 0) }
+func (b *Builder) LineTo(x, y float32) *Builder  { return b.draw('L', x, y, 0, 0, 0, 0) }
+func (b *Builder) RelLineTo(x, y float32) *Builder {
+	return b.draw('l', x, y, 0, 0, 0, 0)
+}
+func (b *Builder) SmoothQuadTo(x, y float32) *Builder {
+	return b.draw('T', x, y, 0, 0, 0, 0)
+}
+func (b *Builder) RelSmoothQuadTo(x, y float32) *Builder {
+	return b.draw('t', x, y, 0, 0, 0, 0)
+}
+func (b *Builder) QuadTo(x1, y1, x, y float32) *Builder {
+	return b.draw('Q', x1, y1, x, y, 0, 0)
+}
+func (b *Builder) RelQuadTo(x1, y1, x, y float32) *Builder {
+	return b.draw('q', x1, y1, x, y, 0, 0)
+}
+func (b *Builder) SmoothCubeTo(x2, y2, x, y float32) *Builder {
+	return b.draw('S', x2, y2, x, y, 0, 0)
+}
+func (b *Builder) RelSmoothCubeTo(x2, y2, x, y float32) *Builder {
+	return b.draw('s', x2, y2, x, y, 0, 0)
+}
+func (b *Builder) CubeTo(x1, y1, x2, y2, x, y float32) *Builder {
+	return b.draw('C', x1, y1, x2, y2, x, y)
+}
+func (b *Builder) RelCubeTo(x1, y1, x2, y2, x, y float32) *Builder {
+	return b.draw('c', x1, y1, x2, y2, x, y)
+}
+
+func (b *Builder) ArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) *Builder {
+	return b.draw('A', rx, ry, xAxisRotation, arcFlags(largeArc, sweep), x, y)
+}
+
+func (b *Builder) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) *Builder {
+	return b.draw('a', rx, ry, xAxisRotation, arcFlags(largeArc, sweep), x, y)
+}
+
+func (b *Builder) draw(drawOp byte, arg0, arg1, arg2, arg3, arg4, arg5 float32) *Builder {
+	if b.err != nil {
+		return b
+	}
+	switch b.state {
+	case builderStateIdle:
+		b.err = errBuilderNoPath
+		return b
+	case builderStatePath:
+		b.err = errBuilderNoMoveTo
+		return b
+	}
+	b.e.draw(drawOp, arg0, arg1, arg2, arg3, arg4, arg5)
+	return b
+}
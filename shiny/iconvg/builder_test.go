@@ -0,0 +1,129 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"bytes"
+	"testing"
+
+	"golang.org/x/image/math/f32"
+)
+
+func TestBuilderActionInfo(t *testing.T) {
+	for _, hires := range []bool{false, true} {
+		var e, f Encoder
+		for _, x := range []*Encoder{&e, &f} {
+			x.Reset(Metadata{
+				ViewBox: Rectangle{
+					Min: f32.Vec2{-24, -24},
+					Max: f32.Vec2{+24, +24},
+				},
+				Palette: DefaultPalette,
+			})
+			x.HighResolutionCoordinates = hires
+		}
+
+		e.StartPath(0, 0, -20)
+		e.AbsCubeTo(-11.05, -20, -20, -11.05, -20, 0)
+		e.RelSmoothCubeTo(8.95, 20, 20, 20)
+		e.RelSmoothCubeTo(20, -8.95, 20, -20)
+		e.AbsSmoothCubeTo(11.05, -20, 0, -20)
+		e.ClosePathRelMoveTo(2, 30)
+		e.RelHLineTo(-4)
+		e.AbsVLineTo(-2)
+		e.RelHLineTo(4)
+		e.RelVLineTo(12)
+		e.ClosePathRelMoveTo(0, -16)
+		e.RelHLineTo(-4)
+		e.RelVLineTo(-4)
+		e.RelHLineTo(4)
+		e.RelVLineTo(4)
+		e.ClosePathEndPath()
+		want, err := e.Bytes()
+		if err != nil {
+			t.Fatalf("hires=%t: Encoder.Bytes: %v", hires, err)
+		}
+
+		b := NewBuilder(&f)
+		b.Path(0).MoveTo(0, -20).
+			CubeTo(-11.05, -20, -20, -11.05, -20, 0).
+			RelSmoothCubeTo(8.95, 20, 20, 20).
+			RelSmoothCubeTo(20, -8.95, 20, -20).
+			SmoothCubeTo(11.05, -20, 0, -20).
+			RelMoveTo(2, 30).RelHLineTo(-4).VLineTo(-2).RelHLineTo(4).RelVLineTo(12).
+			RelMoveTo(0, -16).RelHLineTo(-4).RelVLineTo(-4).RelHLineTo(4).RelVLineTo(4).
+			Close()
+		got, err := b.Bytes()
+		if err != nil {
+			t.Fatalf("hires=%t: Builder.Bytes: %v", hires, err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("hires=%t:\ngot  % x\nwant % x", hires, got, want)
+		}
+	}
+}
+
+func TestBuilderShapes(t *testing.T) {
+	var e, f Encoder
+	e.AppendRect(0, -8, -8, 8, 8)
+	e.AppendCircle(1, 0, 0, 16)
+	want, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Encoder.Bytes: %v", err)
+	}
+	got, err := NewBuilder(&f).Rect(0, -8, -8, 8, 8).Circle(1, 0, 0, 16).Bytes()
+	if err != nil {
+		t.Fatalf("Builder.Bytes: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("\ngot  % x\nwant % x", got, want)
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	testCases := []struct {
+		desc  string
+		build func(b *Builder)
+		want  error
+	}{{
+		desc:  "line outside of a path",
+		build: func(b *Builder) { b.LineTo(1, 1) },
+		want:  errBuilderNoPath,
+	}, {
+		desc:  "line before move",
+		build: func(b *Builder) { b.Path(0).LineTo(1, 1) },
+		want:  errBuilderNoMoveTo,
+	}, {
+		desc:  "close without move",
+		build: func(b *Builder) { b.Path(0).Close() },
+		want:  errBuilderNoMoveTo,
+	}, {
+		desc:  "unclosed path",
+		build: func(b *Builder) { b.Path(0).MoveTo(0, 0).LineTo(1, 1) },
+		want:  errBuilderUnclosedPath,
+	}, {
+		desc:  "nested path",
+		build: func(b *Builder) { b.Path(0).MoveTo(0, 0).Path(1).MoveTo(1, 1).Close() },
+		want:  errBuilderUnclosedPath,
+	}, {
+		desc:  "error is sticky",
+		build: func(b *Builder) { b.Close().Rect(0, 0, 0, 1, 1) },
+		want:  errBuilderNoPath,
+	}, {
+		desc:  "encoder error",
+		build: func(b *Builder) { b.Path(7).MoveTo(0, 0).Close() },
+		want:  errInvalidSelectorAdjustment,
+	}}
+
+	for _, tc := range testCases {
+		var e Encoder
+		b := NewBuilder(&e)
+		tc.build(b)
+		if _, err := b.Bytes(); err != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.desc, err, tc.want)
+		}
+	}
+}
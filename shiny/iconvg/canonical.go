@@ -0,0 +1,131 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+// Canonicalize returns the canonical encoding of the IconVG graphic in src.
+// Graphics that decode to the same metadata and the same sequence of
+// Destination method calls have the same canonical encoding, regardless of
+// how they were encoded, so it can be used to compare or deduplicate
+// graphics by their bytes. The canonical encoding renders identically to
+// src.
+//
+// The canonical encoding is what an Encoder produces, with its
+// HighResolutionCoordinates field set, for those method calls:
+//   - the suggested palette and each color uses its shortest encoding,
+//   - each number uses the shortest encoding that represents it exactly,
+//   - consecutive drawing ops of the same kind share an opcode, up to that
+//     opcode's maximum repeat count, and
+//   - the version indicator is present only if the graphic needs version 1
+//     or later, for clip paths, a title or palette variants.
+//
+// Canonicalize does not otherwise optimize the graphic. For example, it
+// keeps redundant styling opcodes and does not convert between absolute and
+// relative coordinates. To compare graphics regardless of such differences,
+// use Equal.
+//
+// A glyph table is kept, with each glyph made of the re-encoded opcodes of
+// the corresponding glyph in src. Canonicalize returns an error if the glyph
+// table is not one that BeginGlyph and EndGlyph could have produced: if its
+// glyphs are out of order or overlap, or if a glyph starts or ends within a
+// path or within an opcode.
+func Canonicalize(src []byte) ([]byte, error) {
+	return reencode(src, func(e *canonicalEncoder) Destination { return e })
+}
+
+// reencode decodes src into a canonicalEncoder, wrapped by wrap, and returns
+// the encoded bytes. The version indicator is written only if required.
+func reencode(src []byte, wrap func(e *canonicalEncoder) Destination) ([]byte, error) {
+	m := Metadata{
+		ViewBox: DefaultViewBox,
+		Palette: DefaultPalette,
+	}
+	g := glyphSelection{index: -1}
+	if err := decode(nil, nil, &m, &g, true, src, nil); err != nil {
+		return nil, err
+	}
+	for i := 1; i < len(g.ranges); i++ {
+		if g.ranges[i].start < g.ranges[i-1].end {
+			return nil, errUncanonicalizableGlyphTable
+		}
+	}
+
+	for _, writeVersion := range [...]bool{false, true} {
+		e := &canonicalEncoder{srcGlyphs: g.ranges, base: g.base}
+		e.WriteVersion = writeVersion
+		if err := Decode(wrap(e), src, nil); err != nil {
+			return nil, err
+		}
+		e.glyphsAt(uint32(len(src) - g.base))
+		if e.glyphErr != nil {
+			return nil, e.glyphErr
+		}
+		if e.nBegun != len(g.ranges) || e.inGlyph {
+			return nil, errUncanonicalizableGlyphTable
+		}
+		b, err := e.Bytes()
+		if err == errVersionIndicatorRequired && !writeVersion {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	panic("unreachable")
+}
+
+// canonicalEncoder is an Encoder whose coordinates are not quantized. It
+// reproduces the glyph table of the graphic being decoded.
+type canonicalEncoder struct {
+	Encoder
+
+	// srcGlyphs is the glyph table of the graphic being decoded, and base is
+	// the byte offset of its first opcode. nBegun is the number of those
+	// glyphs that have begun, and glyphErr records a glyph boundary within
+	// a path.
+	srcGlyphs []glyphRange
+	base      int
+	nBegun    int
+	glyphErr  error
+}
+
+func (e *canonicalEncoder) Reset(m Metadata) {
+	e.Encoder.Reset(m)
+	e.HighResolutionCoordinates = true
+	e.nBegun = 0
+	e.glyphErr = nil
+}
+
+func (e *canonicalEncoder) startOpcode(offset int) { e.glyphsAt(uint32(offset - e.base)) }
+func (e *canonicalEncoder) endOpcode() error       { return e.glyphErr }
+
+// glyphsAt ends and begins the glyphs of the graphic being decoded whose
+// opcodes end or start at the given offset, relative to its first opcode.
+// Glyphs that start at an earlier offset are never begun.
+func (e *canonicalEncoder) glyphsAt(at uint32) {
+	for e.glyphErr == nil {
+		if e.inGlyph {
+			if e.srcGlyphs[e.nBegun-1].end != at {
+				return
+			}
+			if e.mode == modeDrawing {
+				e.glyphErr = errUncanonicalizableGlyphTable
+				return
+			}
+			e.EndGlyph()
+		}
+		if e.nBegun == len(e.srcGlyphs) || e.srcGlyphs[e.nBegun].start != at {
+			return
+		}
+		if e.mode == modeDrawing {
+			e.glyphErr = errUncanonicalizableGlyphTable
+			return
+		}
+		// The graphic's own opcodes are re-encoded, so there is no need to
+		// reset the selectors and LOD.
+		e.beginGlyph(false)
+		e.nBegun++
+	}
+}
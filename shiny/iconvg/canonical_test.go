@@ -0,0 +1,183 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	// nonCanonical uses longer encodings than necessary: a 4 byte color, a
+	// 2 byte coordinate and two LineTo opcodes that could be one.
+	nonCanonical := []byte{
+		// Magic identifier and zero metadata chunks.
+		0x89, 0x49, 0x56, 0x47, 0x00,
+		// Set CREG[CSEL-0] to opaque black, as a 4 byte color.
+		0x98, 0x00, 0x00, 0x00, 0xff,
+		// Start a path at (0, -8).
+		0xc0, 0x01, 0x80, 0x70,
+		// LineTo (8, -8), and again LineTo (8, 8).
+		0x00, 0x90, 0x70,
+		0x00, 0x90, 0x90,
+		// ClosePathEndPath.
+		0xe1,
+	}
+
+	var e Encoder
+	e.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x00, 0xff}))
+	e.StartPath(0, 0, -8)
+	e.AbsLineTo(8, -8)
+	e.AbsLineTo(8, 8)
+	e.ClosePathEndPath()
+	want, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	got, err := Canonicalize(nonCanonical)
+	if err != nil {
+		t.Fatalf("Canonicalize: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Canonicalize:\ngot  % x\nwant % x", got, want)
+	}
+
+	if _, err := Canonicalize(nonCanonical[:3]); err != errInvalidMagicIdentifier {
+		t.Errorf("Canonicalize(truncated): got %v, want %v", err, errInvalidMagicIdentifier)
+	}
+}
+
+func TestCanonicalizeTestdata(t *testing.T) {
+	const length = 64
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		c1, err := Canonicalize(ivgData)
+		if err != nil {
+			t.Errorf("%s: Canonicalize: %v", tc.filename, err)
+			continue
+		}
+		c2, err := Canonicalize(c1)
+		if err != nil {
+			t.Errorf("%s: Canonicalize(Canonicalize): %v", tc.filename, err)
+			continue
+		}
+		if !bytes.Equal(c1, c2) {
+			t.Errorf("%s: Canonicalize is not idempotent:\n% x\n% x", tc.filename, c1, c2)
+		}
+
+		// The canonical encoding renders identically.
+		var images [2]*image.RGBA
+		for i, src := range [2][]byte{ivgData, c1} {
+			images[i] = image.NewRGBA(image.Rect(0, 0, length, length))
+			var z Rasterizer
+			z.SetDstImage(images[i], images[i].Bounds(), draw.Src)
+			if err := Decode(&z, src, nil); err != nil {
+				t.Errorf("%s: Decode: %v", tc.filename, err)
+			}
+		}
+		if !bytes.Equal(images[0].Pix, images[1].Pix) {
+			t.Errorf("%s: original and canonical encodings render differently", tc.filename)
+		}
+	}
+}
+
+func TestCanonicalizeGlyphs(t *testing.T) {
+	e := Encoder{WriteVersion: true}
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+	// A 4 byte color, re-encoded as a 1 byte color, changes the length of
+	// the opcodes before the glyphs.
+	e.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x00, 0xff}))
+	for i := 0; i < 3; i++ {
+		e.BeginGlyph()
+		encodeGlyph(&e, i)
+		e.EndGlyph()
+	}
+	src := mustBytes(t, &e)
+
+	recolored, err := Recolor(src, color.RGBA{0x00, 0x80, 0x00, 0xff}, color.RGBA{0xff, 0x00, 0x00, 0xff})
+	if err != nil {
+		t.Fatalf("Recolor: %v", err)
+	}
+	c1, err := Canonicalize(src)
+	if err != nil {
+		t.Fatalf("Canonicalize: %v", err)
+	}
+	c2, err := Canonicalize(c1)
+	if err != nil {
+		t.Fatalf("Canonicalize(Canonicalize): %v", err)
+	}
+	if !bytes.Equal(c1, c2) {
+		t.Errorf("Canonicalize is not idempotent:\n% x\n% x", c1, c2)
+	}
+
+	for _, tc := range []struct {
+		desc string
+		b    []byte
+	}{
+		{"Canonicalize", c1},
+		{"Recolor", recolored},
+	} {
+		if n, err := NumGlyphs(tc.b); err != nil || n != 3 {
+			t.Errorf("%s: NumGlyphs: got %d, %v, want 3, nil", tc.desc, n, err)
+			continue
+		}
+		// Each glyph has the same opcodes as the canonical encoding of the
+		// original glyph.
+		for i := 0; i < 3; i++ {
+			want := &canonicalEncoder{}
+			want.WriteVersion = true
+			if err := DecodeGlyph(src, i, want); err != nil {
+				t.Fatalf("DecodeGlyph(src, %d): %v", i, err)
+			}
+			got := &canonicalEncoder{}
+			got.WriteVersion = true
+			if err := DecodeGlyph(tc.b, i, got); err != nil {
+				t.Fatalf("%s: DecodeGlyph(%d): %v", tc.desc, i, err)
+			}
+			g, w := mustBytes(t, &got.Encoder), mustBytes(t, &want.Encoder)
+			// Only the second glyph has the recolored fill.
+			if recolor := tc.desc == "Recolor" && i == 1; bytes.Equal(g, w) == recolor {
+				t.Errorf("%s: glyph %d:\ngot  % x\nwant % x (recolored: %t)", tc.desc, i, g, w, recolor)
+			}
+		}
+	}
+}
+
+func TestCanonicalizeGlyphErrors(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		adjust func(glyphs []glyphRange)
+	}{
+		{"overlapping", func(g []glyphRange) { g[1].start = g[0].end - 1 }},
+		{"within a path", func(g []glyphRange) { g[0].end -= 1 }},
+		{"within an opcode", func(g []glyphRange) { g[0].start += 1 }},
+	}
+	for _, tc := range testCases {
+		e := Encoder{WriteVersion: true}
+		for i := 0; i < 2; i++ {
+			e.BeginGlyph()
+			encodeGlyph(&e, i)
+			e.EndGlyph()
+		}
+		tc.adjust(e.glyphs)
+		src := mustBytes(t, &e)
+		if _, err := Canonicalize(src); err != errUncanonicalizableGlyphTable {
+			t.Errorf("%s: Canonicalize: got %v, want %v", tc.desc, err, errUncanonicalizableGlyphTable)
+		}
+		if _, err := Recolor(src, color.RGBA{}, color.RGBA{}); err != errUncanonicalizableGlyphTable {
+			t.Errorf("%s: Recolor: got %v, want %v", tc.desc, err, errUncanonicalizableGlyphTable)
+		}
+	}
+}
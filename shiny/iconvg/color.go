@@ -90,6 +90,19 @@ func CRegColor(i uint8) Color { return Color{ColorTypeCReg, color.RGBA{R: i & 0x
 // See the "Colors" section in the package documentation for details.
 func BlendColor(t, c0, c1 uint8) Color { return Color{ColorTypeBlend, color.RGBA{R: t, G: c0, B: c1}} }
 
+// Color1Byte returns the Color that the 1 byte color encoding encodes as v.
+//
+// See the "Colors" section in the package documentation for details.
+func Color1Byte(v uint8) Color { return decodeColor1(v) }
+
+// These are the direct Colors of the special values of the 1 byte color
+// encoding: 125, 126 and 127.
+var (
+	ColorGray75Premul = RGBAColor(color.RGBA{0xc0, 0xc0, 0xc0, 0xc0})
+	ColorGray50Premul = RGBAColor(color.RGBA{0x80, 0x80, 0x80, 0x80})
+	ColorTransparent  = RGBAColor(color.RGBA{0x00, 0x00, 0x00, 0x00})
+)
+
 func decodeColor1(x byte) Color {
 	if x >= 0x80 {
 		if x >= 0xc0 {
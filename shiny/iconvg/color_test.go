@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestColor1Byte(t *testing.T) {
+	pal := Palette{
+		3: color.RGBA{0x11, 0x22, 0x33, 0xff},
+	}
+	cReg := [64]color.RGBA{
+		5: color.RGBA{0x44, 0x55, 0x66, 0xff},
+	}
+	testCases := []struct {
+		desc string
+		c    Color
+		want color.RGBA
+	}{
+		{"ColorGray75Premul", ColorGray75Premul, color.RGBA{0xc0, 0xc0, 0xc0, 0xc0}},
+		{"ColorGray50Premul", ColorGray50Premul, color.RGBA{0x80, 0x80, 0x80, 0x80}},
+		{"ColorTransparent", ColorTransparent, color.RGBA{0x00, 0x00, 0x00, 0x00}},
+		{"Color1Byte(125)", Color1Byte(125), color.RGBA{0xc0, 0xc0, 0xc0, 0xc0}},
+		{"Color1Byte(126)", Color1Byte(126), color.RGBA{0x80, 0x80, 0x80, 0x80}},
+		{"Color1Byte(127)", Color1Byte(127), color.RGBA{0x00, 0x00, 0x00, 0x00}},
+		{"Color1Byte(0)", Color1Byte(0), color.RGBA{0x00, 0x00, 0x00, 0xff}},
+		{"Color1Byte(124)", Color1Byte(124), color.RGBA{0xff, 0xff, 0xff, 0xff}},
+		{"Color1Byte(0x0b)", Color1Byte(0x0b), color.RGBA{0x00, 0x80, 0x40, 0xff}},
+		{"Color1Byte(0x83)", Color1Byte(0x83), color.RGBA{0x11, 0x22, 0x33, 0xff}},
+		{"Color1Byte(0xc5)", Color1Byte(0xc5), color.RGBA{0x44, 0x55, 0x66, 0xff}},
+	}
+	for _, tc := range testCases {
+		if got := tc.c.Resolve(&pal, &cReg); got != tc.want {
+			t.Errorf("%s: got %x, want %x", tc.desc, got, tc.want)
+		}
+	}
+
+	// The special values round-trip through the 1 byte color encoding.
+	for i, c := range []Color{ColorGray75Premul, ColorGray50Premul, ColorTransparent} {
+		if x, ok := encodeColor1(c); !ok || x != uint8(125+i) {
+			t.Errorf("encodeColor1(%v): got %d, %t, want %d, true", c, x, ok, 125+i)
+		}
+	}
+}
@@ -7,28 +7,77 @@ package iconvg
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"image"
 	"image/color"
+	"unicode/utf8"
 )
 
 var (
 	errInconsistentMetadataChunkLength = errors.New("iconvg: inconsistent metadata chunk length")
+	errInvalidBackground               = errors.New("iconvg: invalid background")
 	errInvalidColor                    = errors.New("iconvg: invalid color")
+	errInvalidGlyphIndex               = errors.New("iconvg: invalid glyph index")
+	errInvalidGlyphTable               = errors.New("iconvg: invalid glyph table")
 	errInvalidMagicIdentifier          = errors.New("iconvg: invalid magic identifier")
 	errInvalidMetadataChunkLength      = errors.New("iconvg: invalid metadata chunk length")
 	errInvalidMetadataIdentifier       = errors.New("iconvg: invalid metadata identifier")
 	errInvalidNumber                   = errors.New("iconvg: invalid number")
 	errInvalidNumberOfMetadataChunks   = errors.New("iconvg: invalid number of metadata chunks")
+	errInvalidPalettes                 = errors.New("iconvg: invalid palette variants")
 	errInvalidSuggestedPalette         = errors.New("iconvg: invalid suggested palette")
+	errInvalidTitle                    = errors.New("iconvg: invalid title")
 	errInvalidViewBox                  = errors.New("iconvg: invalid view box")
+	errUncanonicalizableGlyphTable     = errors.New("iconvg: glyph table cannot be canonicalized")
 	errUnsupportedDrawingOpcode        = errors.New("iconvg: unsupported drawing opcode")
 	errUnsupportedMetadataIdentifier   = errors.New("iconvg: unsupported metadata identifier")
 	errUnsupportedStylingOpcode        = errors.New("iconvg: unsupported styling opcode")
 	errUnsupportedUpgrade              = errors.New("iconvg: unsupported upgrade")
+	errUnsupportedVersion              = errors.New("iconvg: unsupported version")
 )
 
+// reservedOpcodeError is returned when decoding meets an opcode that is
+// reserved in the current mode.
+type reservedOpcodeError struct {
+	opcode  byte
+	offset  int // from the start of the IconVG graphic
+	drawing bool
+}
+
+func (e *reservedOpcodeError) Error() string {
+	mode := "styling"
+	if e.drawing {
+		mode = "drawing"
+	}
+	return fmt.Sprintf("iconvg: reserved opcode %#02x at offset %d in %s mode", e.opcode, e.offset, mode)
+}
+
+func (e *reservedOpcodeError) Unwrap() error {
+	if e.drawing {
+		return errUnsupportedDrawingOpcode
+	}
+	return errUnsupportedStylingOpcode
+}
+
+// versionError is an error for a graphic whose version indicator gives a
+// version that this package does not implement.
+type versionError struct {
+	version byte
+}
+
+func (e *versionError) Error() string {
+	return fmt.Sprintf("iconvg: unsupported version %d (want at most %d)", e.version, version)
+}
+
+func (e *versionError) Unwrap() error { return errUnsupportedVersion }
+
 var midDescriptions = [...]string{
 	midViewBox:          "viewBox",
 	midSuggestedPalette: "suggested palette",
+	midTitle:            "title",
+	midGlyphs:           "glyph table",
+	midPalettes:         "palette variants",
+	midBackground:       "background",
 }
 
 // Destination handles the actions decoded from an IconVG graphic's opcodes.
@@ -36,6 +85,11 @@ var midDescriptions = [...]string{
 // When passed to Decode, the first method called (if any) will be Reset. No
 // methods will be called at all if an error is encountered in the encoded form
 // before the metadata is fully decoded.
+//
+// PushClip and PopClip are only called for graphics of version 1 or later.
+// After PushClip, the next path (from StartPath to ClosePathEndPath) is a clip
+// path, which is not filled but instead restricts where subsequent paths are
+// drawn, until the matching PopClip.
 type Destination interface {
 	Reset(m Metadata)
 
@@ -44,6 +98,8 @@ type Destination interface {
 	SetCReg(adj uint8, incr bool, c Color)
 	SetNReg(adj uint8, incr bool, f float32)
 	SetLOD(lod0, lod1 float32)
+	PushClip()
+	PopClip()
 
 	StartPath(adj uint8, x, y float32)
 	ClosePathEndPath()
@@ -75,13 +131,36 @@ type DecodeOptions struct {
 	// Palette is an optional 64 color palette. If one isn't provided, the
 	// IconVG graphic's suggested palette will be used.
 	Palette *Palette
+
+	// PaletteVariant selects, by name, which of the graphic's
+	// Metadata.Palettes to draw with when Palette is nil. If the graphic has
+	// no variant with that name, its first variant is used. If
+	// PaletteVariant is empty, the suggested palette is used.
+	PaletteVariant string
+
+	// DstRect and Fit only apply when decoding to a *Rasterizer. For that
+	// call to Decode, they override the destination rectangle passed to
+	// SetDstImage, and how the graphic's viewBox is fitted to it. DstRect is
+	// ignored if empty, in which case the Rasterizer's rectangle is used.
+	// The zero Fit value, FitStretch, matches the Rasterizer's default.
+	DstRect image.Rectangle
+	Fit     Fit
+
+	// MaxPaths and MaxPoints, if positive, limit the number of paths and the
+	// total number of points in those paths. Decoding stops with an error as
+	// soon as either limit is exceeded, which bounds the work done for
+	// untrusted graphics. A point is a coordinate pair given to a drawing
+	// op: a path or subpath's start, or an end or control point. An arc
+	// counts as one point. Zero means no limit.
+	MaxPaths  int
+	MaxPoints int
 }
 
 // DecodeMetadata decodes only the metadata in an IconVG graphic.
 func DecodeMetadata(src []byte) (m Metadata, err error) {
 	m.ViewBox = DefaultViewBox
 	m.Palette = DefaultPalette
-	if err = decode(nil, nil, &m, true, src, nil); err != nil {
+	if err = decode(nil, nil, &m, nil, true, src, nil); err != nil {
 		return Metadata{}, err
 	}
 	return m, nil
@@ -96,10 +175,35 @@ func Decode(dst Destination, src []byte, opts *DecodeOptions) error {
 	if opts != nil && opts.Palette != nil {
 		m.Palette = *opts.Palette
 	}
-	return decode(dst, nil, &m, false, src, opts)
+	if z, ok := dst.(*Rasterizer); ok && opts != nil {
+		r, fit := z.r, z.fit
+		if !opts.DstRect.Empty() {
+			z.r = opts.DstRect
+		}
+		z.fit = opts.Fit
+		defer func() {
+			z.r, z.fit = r, fit
+			z.recalcTransform()
+		}()
+	}
+	if t, ok := dst.(Tracer); ok {
+		dst = &tracer{Destination: dst, t: t}
+	}
+	if dst != nil && opts != nil && (opts.MaxPaths > 0 || opts.MaxPoints > 0) {
+		dst = &limiter{
+			Destination: dst,
+			maxPaths:    opts.MaxPaths,
+			maxPoints:   opts.MaxPoints,
+		}
+	}
+	return decode(dst, nil, &m, nil, false, src, opts)
 }
 
-func decode(dst Destination, p printer, m *Metadata, metadataOnly bool, src buffer, opts *DecodeOptions) (err error) {
+// decode decodes the graphic in src. If g is non-nil, it receives the
+// graphic's glyph table and, if g.index is non-negative, only that glyph's
+// opcodes are decoded.
+func decode(dst Destination, p printer, m *Metadata, g *glyphSelection, metadataOnly bool, src buffer, opts *DecodeOptions) (err error) {
+	lenAll := len(src)
 	if !bytes.HasPrefix(src, magicBytes) {
 		// TODO: detect FFV 1 (File Format Version 1), as opposed to the FFV 0
 		// that this package implements, and delegate to a FFV 1 decoder.
@@ -110,6 +214,17 @@ func decode(dst Destination, p printer, m *Metadata, metadataOnly bool, src buff
 	}
 	src = src[len(magic):]
 
+	ver := byte(0)
+	if len(src) > 0 && src[0]&versionIndicator != 0 {
+		if ver = src[0] &^ versionIndicator; ver > version {
+			return &versionError{ver}
+		}
+		if p != nil {
+			p(src[:1], "Version: %d\n", ver)
+		}
+		src = src[1:]
+	}
+
 	nMetadataChunks, n := src.decodeNatural()
 	if n == 0 {
 		return errInvalidNumberOfMetadataChunks
@@ -120,29 +235,104 @@ func decode(dst Destination, p printer, m *Metadata, metadataOnly bool, src buff
 	src = src[n:]
 
 	for ; nMetadataChunks > 0; nMetadataChunks-- {
-		src, err = decodeMetadataChunk(p, m, src, opts)
+		src, err = decodeMetadataChunk(p, m, g, ver, src, opts)
 		if err != nil {
 			return err
 		}
 	}
+	base := lenAll - len(src)
+	if g != nil {
+		g.base = base
+	}
 	if metadataOnly {
 		return nil
 	}
+
+	if g != nil && g.index >= 0 {
+		if g.index >= len(g.ranges) {
+			return errInvalidGlyphIndex
+		}
+		r := g.ranges[g.index]
+		if uint64(r.end) > uint64(len(src)) {
+			return errInvalidGlyphTable
+		}
+		src, base = src[r.start:r.end], base+int(r.start)
+	}
 	if dst != nil {
-		dst.Reset(*m)
+		dm := *m
+		if opts != nil && opts.Palette == nil && opts.PaletteVariant != "" {
+			dm.Palette = m.paletteVariant(opts.PaletteVariant)
+		}
+		dst.Reset(dm)
+	}
+	return decodeOpcodes(dst, p, ver, src, base)
+}
+
+// paletteVariant returns the palette of m's variant with the given name, or
+// of its first variant if none has that name. It returns the suggested
+// palette if m has no variants.
+func (m *Metadata) paletteVariant(name string) Palette {
+	if len(m.Palettes) == 0 {
+		return m.Palette
 	}
+	for _, np := range m.Palettes {
+		if np.Name == name {
+			return np.Palette
+		}
+	}
+	return m.Palettes[0].Palette
+}
 
+// opcodeObserver is an optional interface for this package's Destinations
+// that follow the decoding of each opcode, such as the validator and the
+// limiter.
+type opcodeObserver interface {
+	// startOpcode is called before each opcode is decoded, with the opcode's
+	// byte offset in the complete graphic.
+	startOpcode(offset int)
+	// endOpcode is called after each opcode is decoded. A non-nil error
+	// stops decoding, and is returned by Decode.
+	endOpcode() error
+}
+
+// decodeOpcodes decodes the styling and drawing opcodes in src, which starts
+// at the given byte offset in the complete graphic.
+func decodeOpcodes(dst Destination, p printer, ver byte, src buffer, base int) (err error) {
+	lenAll := base + len(src)
+	o, _ := dst.(opcodeObserver)
 	mf := modeFunc(decodeStyling)
 	for len(src) > 0 {
-		mf, src, err = mf(dst, p, src)
+		opcode, offset := src[0], lenAll-len(src)
+		if o != nil {
+			o.startOpcode(offset)
+		}
+		var next modeFunc
+		var rest buffer
+		next, rest, err = mf(dst, p, src)
+		if err == errUnsupportedStylingOpcode && ver >= clipVersion {
+			next, rest, err = decodeClip(dst, p, src)
+		}
+		mf, src = next, rest
+		if err == errUnsupportedStylingOpcode || err == errUnsupportedDrawingOpcode {
+			return &reservedOpcodeError{
+				opcode:  opcode,
+				offset:  offset,
+				drawing: err == errUnsupportedDrawingOpcode,
+			}
+		}
 		if err != nil {
 			return err
 		}
+		if o != nil {
+			if err := o.endOpcode(); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
 
-func decodeMetadataChunk(p printer, m *Metadata, src buffer, opts *DecodeOptions) (src1 buffer, err error) {
+func decodeMetadataChunk(p printer, m *Metadata, g *glyphSelection, ver byte, src buffer, opts *DecodeOptions) (src1 buffer, err error) {
 	length, n := src.decodeNatural()
 	if n == 0 {
 		return nil, errInvalidMetadataChunkLength
@@ -157,7 +347,9 @@ func decodeMetadataChunk(p printer, m *Metadata, src buffer, opts *DecodeOptions
 	if n == 0 {
 		return nil, errInvalidMetadataIdentifier
 	}
-	if mid >= uint32(len(midDescriptions)) {
+	if mid >= uint32(len(midDescriptions)) || (mid == midTitle && ver < titleVersion) ||
+		(mid == midGlyphs && ver < glyphsVersion) || (mid == midPalettes && ver < palettesVersion) ||
+		(mid == midBackground && ver < backgroundVersion) {
 		return nil, errUnsupportedMetadataIdentifier
 	}
 	if p != nil {
@@ -186,40 +378,55 @@ func decodeMetadataChunk(p printer, m *Metadata, src buffer, opts *DecodeOptions
 		}
 
 	case midSuggestedPalette:
-		if len(src) == 0 {
+		pal := DefaultPalette
+		if src, err = decodePalette(p, &pal, src); err != nil {
 			return nil, errInvalidSuggestedPalette
 		}
-		length, format := 1+int(src[0]&0x3f), src[0]>>6
-		decode := buffer.decodeColor4
-		switch format {
-		case 0:
-			decode = buffer.decodeColor1
-		case 1:
-			decode = buffer.decodeColor2
-		case 2:
-			decode = buffer.decodeColor3Direct
+		if opts == nil || opts.Palette == nil {
+			m.Palette = pal
+		}
+
+	case midTitle:
+		// The title is the rest of the chunk.
+		n := int64(len(src)) - lenSrcWant
+		if n < 0 || n > maxTitleLen || !utf8.Valid(src[:n]) {
+			return nil, errInvalidTitle
 		}
 		if p != nil {
-			p(src[:1], "    %d palette colors, %d bytes per color\n", length, 1+format)
+			for i := int64(0); i < n; i += 4 {
+				j := i + 4
+				if j > n {
+					j = n
+				}
+				p(src[i:j], "    %q\n", src[i:j])
+			}
 		}
-		src = src[1:]
+		m.Title = string(src[:n])
+		src = src[n:]
 
-		for i := 0; i < length; i++ {
-			c, n := decode(src)
-			if n == 0 {
-				return nil, errInvalidSuggestedPalette
-			}
-			rgba := c.rgba()
-			if c.typ != ColorTypeRGBA || !validAlphaPremulColor(rgba) {
-				rgba = color.RGBA{0x00, 0x00, 0x00, 0xff}
-			}
-			if p != nil {
-				p(src[:n], "    RGBA %02x%02x%02x%02x\n", rgba.R, rgba.G, rgba.B, rgba.A)
-			}
-			src = src[n:]
-			if opts == nil || opts.Palette == nil {
-				m.Palette[i] = rgba
-			}
+	case midPalettes:
+		if m.Palettes, src, err = decodePalettes(p, src); err != nil {
+			return nil, err
+		}
+
+	case midBackground:
+		c, n := src.decodeColor4()
+		if n == 0 || !validAlphaPremulColor(c.rgba()) {
+			return nil, errInvalidBackground
+		}
+		m.Background = c.rgba()
+		if p != nil {
+			p(src[:n], "    RGBA %02x%02x%02x%02x\n", m.Background.R, m.Background.G, m.Background.B, m.Background.A)
+		}
+		src = src[n:]
+
+	case midGlyphs:
+		var ranges []glyphRange
+		if ranges, src, err = decodeGlyphTable(p, src); err != nil {
+			return nil, err
+		}
+		if g != nil {
+			g.ranges = ranges
 		}
 
 	default:
@@ -232,6 +439,93 @@ func decodeMetadataChunk(p printer, m *Metadata, src buffer, opts *DecodeOptions
 	return src, nil
 }
 
+// decodePalette decodes a palette's colors into the first entries of pal.
+func decodePalette(p printer, pal *Palette, src buffer) (buffer, error) {
+	if len(src) == 0 {
+		return nil, errInvalidSuggestedPalette
+	}
+	length, format := 1+int(src[0]&0x3f), src[0]>>6
+	decode := buffer.decodeColor4
+	switch format {
+	case 0:
+		decode = buffer.decodeColor1
+	case 1:
+		decode = buffer.decodeColor2
+	case 2:
+		decode = buffer.decodeColor3Direct
+	}
+	if p != nil {
+		p(src[:1], "    %d palette colors, %d bytes per color\n", length, 1+format)
+	}
+	src = src[1:]
+
+	for i := 0; i < length; i++ {
+		c, n := decode(src)
+		if n == 0 {
+			return nil, errInvalidSuggestedPalette
+		}
+		rgba := c.rgba()
+		if c.typ != ColorTypeRGBA || !validAlphaPremulColor(rgba) {
+			rgba = color.RGBA{0x00, 0x00, 0x00, 0xff}
+		}
+		if p != nil {
+			p(src[:n], "    RGBA %02x%02x%02x%02x\n", rgba.R, rgba.G, rgba.B, rgba.A)
+		}
+		src = src[n:]
+		pal[i] = rgba
+	}
+	return src, nil
+}
+
+// decodePalettes decodes the palette variants metadata chunk: the number of
+// variants followed by, for each one, its name's length, its name and its
+// colors.
+func decodePalettes(p printer, src buffer) (nps []NamedPalette, src1 buffer, err error) {
+	nVariants, n := src.decodeNatural()
+	if n == 0 || nVariants == 0 || uint64(nVariants) > uint64(len(src)) {
+		return nil, nil, errInvalidPalettes
+	}
+	if p != nil {
+		p(src[:n], "    %d palette variants\n", nVariants)
+	}
+	src = src[n:]
+
+	nps = make([]NamedPalette, nVariants)
+	for i := range nps {
+		nameLen, n := src.decodeNatural()
+		if n == 0 || nameLen > maxPaletteNameLen || uint64(n)+uint64(nameLen) > uint64(len(src)) {
+			return nil, nil, errInvalidPalettes
+		}
+		name := src[n : n+int(nameLen)]
+		if !utf8.Valid(name) {
+			return nil, nil, errInvalidPalettes
+		}
+		if p != nil {
+			p(src[:n], "    Variant %d name length: %d\n", i, nameLen)
+			for j := 0; j < len(name); j += 4 {
+				k := j + 4
+				if k > len(name) {
+					k = len(name)
+				}
+				p(name[j:k], "    %q\n", name[j:k])
+			}
+		}
+		nps[i].Name = string(name)
+		for _, prev := range nps[:i] {
+			if prev.Name == nps[i].Name {
+				return nil, nil, errInvalidPalettes
+			}
+		}
+		src = src[n+int(nameLen):]
+
+		nps[i].Palette = DefaultPalette
+		if src, err = decodePalette(p, &nps[i].Palette, src); err != nil {
+			return nil, nil, errInvalidPalettes
+		}
+	}
+	return nps, src, nil
+}
+
 // modeFunc is the decoding mode: whether we are decoding styling or drawing
 // opcodes.
 //
@@ -275,6 +569,29 @@ func decodeStyling(dst Destination, p printer, src buffer) (modeFunc, buffer, er
 	return nil, nil, errUnsupportedStylingOpcode
 }
 
+// decodeClip decodes the styling opcodes that were added in clipVersion.
+func decodeClip(dst Destination, p printer, src buffer) (modeFunc, buffer, error) {
+	switch src[0] {
+	case 0xc8:
+		if p != nil {
+			p(src[:1], "Push clip; the next path is a clip path\n")
+		}
+		if dst != nil {
+			dst.PushClip()
+		}
+	case 0xc9:
+		if p != nil {
+			p(src[:1], "Pop clip\n")
+		}
+		if dst != nil {
+			dst.PopClip()
+		}
+	default:
+		return nil, nil, errUnsupportedStylingOpcode
+	}
+	return decodeStyling, src[1:], nil
+}
+
 func decodeSetCReg(dst Destination, p printer, src buffer, opcode byte) (modeFunc, buffer, error) {
 	nBytes, directness, adj := 0, "", opcode&0x07
 	var decode func(buffer) (Color, int)
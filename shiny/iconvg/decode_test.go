@@ -6,6 +6,7 @@ package iconvg
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -18,6 +19,7 @@ import (
 	"testing"
 
 	"golang.org/x/image/math/f32"
+	"golang.org/x/image/vector"
 )
 
 // disassemble returns a disassembly of an encoded IconVG graphic. Users of
@@ -39,7 +41,7 @@ func disassemble(src []byte) ([]byte, error) {
 		fmt.Fprintf(w, format, args...)
 	}
 	m := Metadata{}
-	if err := decode(nil, p, &m, false, buffer(src), nil); err != nil {
+	if err := decode(nil, p, &m, nil, false, buffer(src), nil); err != nil {
 		return nil, err
 	}
 	return w.Bytes(), nil
@@ -48,6 +50,11 @@ func disassemble(src []byte) ([]byte, error) {
 var (
 	_ Destination = (*Encoder)(nil)
 	_ Destination = (*Rasterizer)(nil)
+	_ Destination = (*validator)(nil)
+	_ Destination = (*paletteUser)(nil)
+	_ Destination = (*statsCounter)(nil)
+	_ Destination = (*limiter)(nil)
+	_ Destination = (*tracer)(nil)
 )
 
 func encodePNG(dstFilename string, src image.Image) error {
@@ -136,6 +143,7 @@ var testdataTestCases = []struct {
 	{"testdata/action-info.hires", ""},
 	{"testdata/arcs", ""},
 	{"testdata/blank", ""},
+	{"testdata/clip", ""},
 	{"testdata/cowbell", ""},
 	{"testdata/elliptical", ""},
 	{"testdata/favicon", ";pink"},
@@ -186,6 +194,7 @@ func TestDecodeEncodeRoundTrip(t *testing.T) {
 		}
 		var e resolutionPreservingEncoder
 		e.HighResolutionCoordinates = strings.HasSuffix(tc.filename, ".hires")
+		e.WriteVersion = ivgData[len(magic)]&versionIndicator != 0
 		if err := Decode(&e, ivgData, nil); err != nil {
 			t.Errorf("%s: Decode: %v", tc.filename, err)
 			continue
@@ -287,6 +296,270 @@ func TestDecodeAndRasterize(t *testing.T) {
 	}
 }
 
+func TestDecodeFit(t *testing.T) {
+	ivgData, err := os.ReadFile(filepath.FromSlash("testdata/favicon.ivg"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	// render rasterizes the favicon graphic, stretched to w×h pixels.
+	render := func(w, h int) *image.RGBA {
+		m := image.NewRGBA(image.Rect(0, 0, w, h))
+		var z Rasterizer
+		z.SetDstImage(m, m.Bounds(), draw.Src)
+		if err := Decode(&z, ivgData, nil); err != nil {
+			t.Fatalf("Decode: %v", err)
+		}
+		return m
+	}
+	stretched, small, large := render(128, 64), render(64, 64), render(128, 128)
+
+	// The favicon has a square viewBox, and it is drawn into a 128×64
+	// rectangle in the middle of a larger image.
+	dstRect := image.Rect(10, 10, 138, 74)
+	magenta := color.RGBA{0xff, 0x00, 0xff, 0xff}
+	transparent := color.RGBA{}
+
+	testCases := []struct {
+		fit Fit
+		// want holds the wanted colors at the dstRect's top-left, top-right,
+		// bottom-left and bottom-right pixels.
+		want [4]color.RGBA
+	}{{
+		fit: FitStretch,
+		want: [4]color.RGBA{
+			stretched.RGBAAt(0, 0),
+			stretched.RGBAAt(127, 0),
+			stretched.RGBAAt(0, 63),
+			stretched.RGBAAt(127, 63),
+		},
+	}, {
+		// The graphic is 64×64 and centered, so the rectangle's corners are
+		// outside it.
+		fit:  FitContain,
+		want: [4]color.RGBA{transparent, transparent, transparent, transparent},
+	}, {
+		// The graphic is 128×128 and centered, so its top and bottom 32
+		// rows are cut off.
+		fit: FitCover,
+		want: [4]color.RGBA{
+			large.RGBAAt(0, 32),
+			large.RGBAAt(127, 32),
+			large.RGBAAt(0, 95),
+			large.RGBAAt(127, 95),
+		},
+	}}
+
+	for _, tc := range testCases {
+		got := image.NewRGBA(image.Rect(0, 0, 148, 84))
+		draw.Draw(got, got.Bounds(), image.NewUniform(magenta), image.Point{}, draw.Src)
+		var z Rasterizer
+		z.SetDstImage(got, got.Bounds(), draw.Src)
+		if err := Decode(&z, ivgData, &DecodeOptions{DstRect: dstRect, Fit: tc.fit}); err != nil {
+			t.Errorf("fit %d: Decode: %v", tc.fit, err)
+			continue
+		}
+		corners := [4]image.Point{
+			{dstRect.Min.X, dstRect.Min.Y},
+			{dstRect.Max.X - 1, dstRect.Min.Y},
+			{dstRect.Min.X, dstRect.Max.Y - 1},
+			{dstRect.Max.X - 1, dstRect.Max.Y - 1},
+		}
+		for i, p := range corners {
+			if c := got.RGBAAt(p.X, p.Y); c != tc.want[i] {
+				t.Errorf("fit %d: at %v: got %v, want %v", tc.fit, p, c, tc.want[i])
+			}
+		}
+		// Nothing is drawn outside of dstRect.
+		for _, p := range []image.Point{{9, 9}, {138, 74}} {
+			if c := got.RGBAAt(p.X, p.Y); c != magenta {
+				t.Errorf("fit %d: at %v: got %v, want %v", tc.fit, p, c, magenta)
+			}
+		}
+		if tc.fit == FitContain {
+			// The graphic's corners are at x = 42 and x = 105.
+			for _, p := range []image.Point{{0, 0}, {63, 0}, {0, 63}, {63, 63}} {
+				q := p.Add(image.Point{42, 10})
+				if c, want := got.RGBAAt(q.X, q.Y), small.RGBAAt(p.X, p.Y); c != want {
+					t.Errorf("fit %d: at %v: got %v, want %v", tc.fit, q, c, want)
+				}
+			}
+		}
+
+		// The options only apply to that call to Decode.
+		if z.r != got.Bounds() || z.fit != FitStretch {
+			t.Errorf("fit %d: Rasterizer not restored: r=%v, fit=%d", tc.fit, z.r, z.fit)
+		}
+	}
+}
+
+func TestDecodeVersion(t *testing.T) {
+	var e Encoder
+	e.WriteVersion = true
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+	e.AppendRect(0, -8, -8, 8, 8)
+	versioned, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if got, want := versioned[len(magic)], byte(0x80); got != want {
+		t.Fatalf("version indicator: got %#02x, want %#02x", got, want)
+	}
+
+	e = Encoder{}
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+	e.AppendRect(0, -8, -8, 8, 8)
+	unversioned, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if !bytes.Equal(versioned[len(magic)+1:], unversioned[len(magic):]) {
+		t.Fatalf("versioned and unversioned graphics differ after the version indicator:\n% x\n% x",
+			versioned, unversioned)
+	}
+
+	// Both forms decode to the same thing.
+	for _, src := range [][]byte{versioned, unversioned} {
+		var got Encoder
+		if err := Decode(&got, src, nil); err != nil {
+			t.Fatalf("Decode(% x): %v", src, err)
+		}
+		if b, _ := got.Bytes(); !bytes.Equal(b, unversioned) {
+			t.Errorf("Decode(% x): got % x, want % x", src, b, unversioned)
+		}
+	}
+
+	// A version that this package does not implement is rejected.
+	future := append([]byte(nil), versioned...)
+	future[len(magic)] = 0x82
+	err = Decode(&Encoder{}, future, nil)
+	if !errors.Is(err, errUnsupportedVersion) {
+		t.Errorf("Decode: got %v, want %v", err, errUnsupportedVersion)
+	} else if got, want := err.Error(), "iconvg: unsupported version 2 (want at most 1)"; got != want {
+		t.Errorf("Decode: got %q, want %q", got, want)
+	}
+	if _, err := DecodeMetadata(future); !errors.Is(err, errUnsupportedVersion) {
+		t.Errorf("DecodeMetadata: got %v, want %v", err, errUnsupportedVersion)
+	}
+	if _, err := UpgradeToFileFormatVersion1(future, nil); !errors.Is(err, errUnsupportedVersion) {
+		t.Errorf("UpgradeToFileFormatVersion1: got %v, want %v", err, errUnsupportedVersion)
+	}
+}
+
+func TestDecodeReservedOpcode(t *testing.T) {
+	testCases := []struct {
+		desc string
+		src  []byte
+		want string
+	}{{
+		desc: "styling",
+		src: []byte{
+			0x89, 0x49, 0x56, 0x47, // Magic identifier.
+			0x00, // Zero metadata chunks.
+			0x01, // Set CSEL = 1.
+			0xc8, // Reserved.
+		},
+		want: "iconvg: reserved opcode 0xc8 at offset 6 in styling mode",
+	}, {
+		desc: "version 1 styling",
+		src: []byte{
+			0x89, 0x49, 0x56, 0x47, // Magic identifier.
+			0x81, // Version: 1.
+			0x00, // Zero metadata chunks.
+			0xc8, // Push clip.
+			0xca, // Reserved.
+		},
+		want: "iconvg: reserved opcode 0xca at offset 7 in styling mode",
+	}, {
+		desc: "drawing",
+		src: []byte{
+			0x89, 0x49, 0x56, 0x47, // Magic identifier.
+			0x00,             // Zero metadata chunks.
+			0xc0, 0x80, 0x80, // Start path at (0, 0).
+			0xe7, 0x88, // h +4.
+			0xe0, // Reserved.
+			0xe1, // z; end path.
+		},
+		want: "iconvg: reserved opcode 0xe0 at offset 10 in drawing mode",
+	}, {
+		desc: "after metadata",
+		src: []byte{
+			0x89, 0x49, 0x56, 0x47, // Magic identifier.
+			0x02,                   // One metadata chunk.
+			0x0a,                   // Metadata chunk length: 5.
+			0x00,                   // Metadata identifier: 0 (viewBox).
+			0x50, 0x50, 0xb0, 0xb0, // -24, -24, +24, +24.
+			0xc0, 0x80, 0x80, // Start path at (0, 0).
+			0xff, // Reserved.
+		},
+		want: "iconvg: reserved opcode 0xff at offset 14 in drawing mode",
+	}}
+
+	for _, tc := range testCases {
+		err := Decode(nil, tc.src, nil)
+		if err == nil {
+			t.Errorf("%s: got nil error, want %q", tc.desc, tc.want)
+			continue
+		}
+		if got := err.Error(); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestRasterizerClip(t *testing.T) {
+	colors := map[byte]color.RGBA{
+		'.': {},
+		'R': {0xff, 0x00, 0x00, 0xff},
+		'G': {0x00, 0xff, 0x00, 0xff},
+		'B': {0x00, 0x00, 0xff, 0xff},
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	var z Rasterizer
+	z.SetDstImage(dst, dst.Bounds(), draw.Over)
+	z.Reset(Metadata{
+		ViewBox: Rectangle{
+			Min: f32.Vec2{0, 0},
+			Max: f32.Vec2{4, 4},
+		},
+		Palette: DefaultPalette,
+	})
+	rect := func(c byte, minX, minY, maxX, maxY float32) {
+		z.SetCReg(0, false, RGBAColor(colors[c]))
+		z.StartPath(0, minX, minY)
+		z.AbsHLineTo(maxX)
+		z.AbsVLineTo(maxY)
+		z.AbsHLineTo(minX)
+		z.ClosePathEndPath()
+	}
+
+	// Clip to the left half, and then to the top half of that. The clip
+	// paths' colors are ignored.
+	z.PushClip()
+	rect('R', 0, 0, 2, 4)
+	rect('B', 0, 0, 4, 4)
+	z.PushClip()
+	rect('G', 0, 0, 4, 2)
+	rect('R', 0, 0, 4, 4)
+	z.PopClip()
+	z.PopClip()
+	rect('G', 3, 0, 4, 4)
+
+	want := [4]string{
+		"RR.G",
+		"RR.G",
+		"BB.G",
+		"BB.G",
+	}
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			if got, want := dst.RGBAAt(x, y), colors[want[y][x]]; got != want {
+				t.Errorf("(%d, %d): got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
 func TestInvalidAlphaPremultipliedColor(t *testing.T) {
 	// See http://golang.org/issue/39526 for some discussion.
 
@@ -338,3 +611,206 @@ func TestBlendColor(t *testing.T) {
 		t.Errorf("\ngot  %x\nwant %x", got, want)
 	}
 }
+
+func TestDecodeLimits(t *testing.T) {
+	// Each rectangle is a path of 4 points: the start and three lineTos.
+	const nRects = 1000
+	var e Encoder
+	for i := 0; i < nRects; i++ {
+		e.AppendRect(0, -30, -30, 30, 30)
+	}
+	ivgData, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	testCases := []struct {
+		maxPaths  int
+		maxPoints int
+		want      error
+	}{
+		{0, 0, nil},
+		{nRects, 0, nil},
+		{0, 4 * nRects, nil},
+		{nRects, 4 * nRects, nil},
+		{nRects - 1, 0, errTooManyPaths},
+		{0, 4*nRects - 1, errTooManyPoints},
+		{10, 4 * nRects, errTooManyPaths},
+		{nRects, 10, errTooManyPoints},
+	}
+
+	for _, tc := range testCases {
+		m := image.NewAlpha(image.Rect(0, 0, 16, 16))
+		var z Rasterizer
+		z.SetDstImage(m, m.Bounds(), draw.Src)
+		opts := &DecodeOptions{MaxPaths: tc.maxPaths, MaxPoints: tc.maxPoints}
+		if err := Decode(&z, ivgData, opts); err != tc.want {
+			t.Errorf("MaxPaths=%d, MaxPoints=%d: got %v, want %v", tc.maxPaths, tc.maxPoints, err, tc.want)
+		}
+	}
+
+	// Decoding stops at the first path that exceeds the limit, so the
+	// destination sees only the paths before it.
+	var d Encoder
+	if err := Decode(&d, ivgData, &DecodeOptions{MaxPaths: 3}); err != errTooManyPaths {
+		t.Fatalf("Decode: got %v, want %v", err, errTooManyPaths)
+	}
+	var want Encoder
+	for i := 0; i < 3; i++ {
+		want.AppendRect(0, -30, -30, 30, 30)
+	}
+	if got, want := d.buf, want.buf; !bytes.Equal(got, want) {
+		t.Errorf("\ngot  % x\nwant % x", got, want)
+	}
+}
+
+func TestDecodeToVector(t *testing.T) {
+	// The same vector.Rasterizer is used for every graphic, and its DrawOp is
+	// left unchanged.
+	var v vector.Rasterizer
+	v.DrawOp = draw.Src
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		md, err := DecodeMetadata(ivgData)
+		if err != nil {
+			t.Errorf("%s: DecodeMetadata: %v", tc.filename, err)
+			continue
+		}
+
+		for _, variant := range strings.Split(tc.variants, ";") {
+			length := 256
+			if variant == "64" {
+				length = 64
+			}
+			width, height := length, length
+			if dx, dy := md.ViewBox.AspectRatio(); dx < dy {
+				width = int(float32(length) * dx / dy)
+			} else {
+				height = int(float32(length) * dy / dx)
+			}
+
+			opts := &DecodeOptions{}
+			if variant == "pink" {
+				pal := DefaultPalette
+				pal[0] = color.RGBA{0xfe, 0x76, 0xea, 0xff}
+				opts.Palette = &pal
+			}
+
+			got := image.NewRGBA(image.Rect(0, 0, width, height))
+			if err := DecodeToVector(&v, got, got.Bounds(), ivgData, opts); err != nil {
+				t.Errorf("%s %q variant: DecodeToVector: %v", tc.filename, variant, err)
+				continue
+			}
+			if v.DrawOp != draw.Src {
+				t.Fatalf("%s %q variant: DrawOp: got %v, want %v", tc.filename, variant, v.DrawOp, draw.Src)
+			}
+
+			wantFilename := filepath.FromSlash(tc.filename)
+			if variant != "" {
+				wantFilename += "." + variant
+			}
+			want, err := decodePNG(wantFilename + ".png")
+			if err != nil {
+				t.Errorf("%s %q variant: decodePNG: %v", tc.filename, variant, err)
+				continue
+			}
+			if err := checkApproxEqual(got, want); err != nil {
+				t.Errorf("%s %q variant: %v", tc.filename, variant, err)
+			}
+		}
+	}
+}
+
+func TestRasterizerAllocs(t *testing.T) {
+	var srcs [][]byte
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Fatal(err)
+		}
+		srcs = append(srcs, ivgData)
+	}
+
+	// Draw every graphic at two sizes, as an icon-heavy UI might.
+	dst := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	var z Rasterizer
+	decodeAll := func() {
+		for _, r := range []image.Rectangle{dst.Bounds(), image.Rect(8, 8, 40, 40)} {
+			z.SetDstImage(dst, r, draw.Over)
+			for _, src := range srcs {
+				if err := Decode(&z, src, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+	decodeAll()
+	if n := testing.AllocsPerRun(10, decodeAll); n != 0 {
+		t.Errorf("got %v allocs per run, want 0", n)
+	}
+}
+
+func BenchmarkRasterizer(b *testing.B) {
+	var srcs [][]byte
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			b.Fatal(err)
+		}
+		srcs = append(srcs, ivgData)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	var z Rasterizer
+	z.SetDstImage(dst, dst.Bounds(), draw.Over)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, src := range srcs {
+			if err := Decode(&z, src, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestDecodeBackground(t *testing.T) {
+	bg := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	e := Encoder{WriteVersion: true}
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette, Background: bg})
+	e.SetCReg(0, false, RGBAColor(red))
+	e.AppendRect(0, -16, -16, 16, 16)
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	// Rasterize onto a destination that is already gray, with draw.Src. The
+	// background replaces the gray and the path is drawn over the background.
+	gray := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	dst := image.NewRGBA(image.Rect(0, 0, 80, 64))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(gray), image.Point{}, draw.Src)
+	var z Rasterizer
+	z.SetDstImage(dst, image.Rect(8, 0, 72, 64), draw.Src)
+	if err := Decode(&z, src, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	for _, tc := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{4, 32, gray},  // Outside of the SetDstImage rectangle.
+		{76, 32, gray}, // Ditto.
+		{10, 2, bg},    // Inside the view box, outside of the path.
+		{70, 62, bg},   // Ditto.
+		{40, 32, red},  // Inside the path.
+	} {
+		if got := dst.RGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("(%d, %d): got %v, want %v", tc.x, tc.y, got, tc.want)
+		}
+	}
+}
@@ -20,7 +20,8 @@ independent of the platform (and specifically its floating-point hardware).
 package iconvg
 
 // TODO: shapes (circles, rects) and strokes? Or can we assume that authoring
-// tools will convert shapes and strokes to paths?
+// tools will convert shapes and strokes to paths? For now, the Encoder's
+// AppendRect and AppendCircle methods convert the simplest shapes to paths.
 
 // TODO: mark somehow that a graphic (such as a back arrow) should be flipped
 // horizontally or its paths otherwise varied when presented in a Right-To-Left
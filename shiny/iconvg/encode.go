@@ -7,7 +7,9 @@ package iconvg
 import (
 	"errors"
 	"image/color"
+	"io"
 	"math"
+	"unicode/utf8"
 
 	"golang.org/x/image/math/f32"
 )
@@ -15,10 +17,19 @@ import (
 var (
 	errCSELUsedAsBothGradientAndStop = errors.New("iconvg: CSEL used as both gradient and stop")
 	errDrawingOpsUsedInStylingMode   = errors.New("iconvg: drawing ops used in styling mode")
+	errExceedsMaxBytes               = errors.New("iconvg: exceeds MaxBytes")
+	errGlyphNotBegun                 = errors.New("iconvg: EndGlyph without a matching BeginGlyph")
+	errGlyphNotEnded                 = errors.New("iconvg: BeginGlyph without a matching EndGlyph")
 	errInvalidSelectorAdjustment     = errors.New("iconvg: invalid selector adjustment")
 	errInvalidIncrementingAdjustment = errors.New("iconvg: invalid incrementing adjustment")
+	errInvalidPaletteFormat          = errors.New("iconvg: invalid palette format")
+	errNotEncodableAs1ByteColor      = errors.New("iconvg: color is not encodable as a 1 byte color")
+	errPaletteFormatTooNarrow        = errors.New("iconvg: palette format cannot represent the suggested palette")
+	errPathNotEnded                  = errors.New("iconvg: StartPath without a matching ClosePathEndPath")
+	errPathNotStarted                = errors.New("iconvg: path closed without a matching StartPath")
 	errStylingOpsUsedInDrawingMode   = errors.New("iconvg: styling ops used in drawing mode")
 	errTooManyGradientStops          = errors.New("iconvg: too many gradient stops")
+	errVersionIndicatorRequired      = errors.New("iconvg: version indicator required")
 )
 
 type mode uint8
@@ -54,6 +65,31 @@ type Encoder struct {
 	// encoding format.
 	HighResolutionCoordinates bool
 
+	// WriteVersion is whether the encoder should write a version indicator
+	// after the magic identifier. Unlike HighResolutionCoordinates, it is not
+	// changed by Reset, and must be set before Reset (or, if Reset is not
+	// called, before any other method) to take effect.
+	//
+	// The indicator gives the lowest version that can represent the
+	// graphic. Version 1 is needed if any of these are used:
+	//   - PushClip or PopClip,
+	//   - BeginGlyph, for the glyph table,
+	//   - a non-empty Metadata Title or Palettes, or a Metadata Background
+	//     other than transparent black.
+	// Otherwise the version is 0.
+	// Decoders treat a graphic without a version indicator as being of
+	// version 0. By default (false), the encoder omits the indicator, so that
+	// its output can be read by older decoders during the transition to
+	// versioned graphics. Using the features listed above without a version
+	// indicator is an error.
+	WriteVersion bool
+
+	// MaxBytes, if positive, is the maximum length of the encoded form. Once
+	// the encoded form would be longer, the Encoder stops encoding and Bytes
+	// returns an error, so that an oversized graphic is rejected early. Like
+	// WriteVersion, it is not changed by Reset.
+	MaxBytes int
+
 	// highResolutionCoordinates is a local copy, copied during StartPath, to
 	// avoid having to specify the semantics of modifying the exported field
 	// while drawing.
@@ -64,6 +100,17 @@ type Encoder struct {
 	metadata Metadata
 	err      error
 
+	// metadataLen is the length of buf's magic identifier, version indicator
+	// and metadata, or equivalently, the offset of the first opcode.
+	metadataLen int
+
+	// glyphs, inGlyph and glyphBuf hold the state for BeginGlyph and
+	// EndGlyph. If glyphs is non-empty, Bytes inserts a glyph table metadata
+	// chunk, assembling the encoded form in glyphBuf.
+	glyphs   []glyphRange
+	inGlyph  bool
+	glyphBuf buffer
+
 	lod0 float32
 	lod1 float32
 	cSel uint8
@@ -76,7 +123,8 @@ type Encoder struct {
 	scratch [12]byte
 }
 
-// Bytes returns the encoded form.
+// Bytes returns the encoded form. The returned slice is only valid until the
+// next call to Reset or ResetPaths.
 func (e *Encoder) Bytes() ([]byte, error) {
 	if e.err != nil {
 		return nil, e.err
@@ -84,18 +132,96 @@ func (e *Encoder) Bytes() ([]byte, error) {
 	if e.mode == modeInitial {
 		e.appendDefaultMetadata()
 	}
+	if e.mode == modeDrawing {
+		return nil, errPathNotEnded
+	}
+	if len(e.glyphs) > 0 {
+		if e.inGlyph {
+			return nil, errGlyphNotEnded
+		}
+		e.glyphBuf = e.appendWithGlyphTable(e.glyphBuf[:0])
+		if e.MaxBytes > 0 && len(e.glyphBuf) > e.MaxBytes {
+			e.err = errExceedsMaxBytes
+			return nil, e.err
+		}
+		return []byte(e.glyphBuf), nil
+	}
+	if e.checkMaxBytes(); e.err != nil {
+		return nil, e.err
+	}
 	return []byte(e.buf), nil
 }
 
+// Len returns the length of the encoded form, as returned by Bytes, without
+// copying or finalizing it. It returns 0 if an error occurred while encoding.
+func (e *Encoder) Len() int {
+	if e.err != nil {
+		return 0
+	}
+	if e.mode == modeInitial {
+		// Bytes would write the magic identifier, the version indicator if
+		// enabled and zero metadata chunks.
+		n := len(magic) + 1
+		if e.WriteVersion {
+			n++
+		}
+		return n
+	}
+	if len(e.glyphs) > 0 {
+		e.altBuf = e.appendGlyphChunk(e.altBuf[:0])
+		return len(e.buf) + len(e.altBuf)
+	}
+	return len(e.buf)
+}
+
+// WriteTo writes the encoded form to w. It implements io.WriterTo.
+//
+// If an error occurred while encoding, nothing is written and that error is
+// returned.
+func (e *Encoder) WriteTo(w io.Writer) (n int64, err error) {
+	b, err := e.Bytes()
+	if err != nil {
+		return 0, err
+	}
+	m, err := w.Write(b)
+	return int64(m), err
+}
+
+// ResetPaths discards the encoded styling and drawing opcodes, keeping the
+// metadata, so that another graphic with the same metadata can be encoded.
+// Unlike Reset, it leaves e.HighResolutionCoordinates unchanged. If neither
+// Reset nor any other method has been called, the default metadata is still
+// implied.
+//
+// Like Reset, ResetPaths reuses the Encoder's buffer, so it overwrites the
+// contents of the slice returned by an earlier call to Bytes. Copy that
+// slice first to keep it.
+func (e *Encoder) ResetPaths() {
+	if e.mode == modeInitial {
+		*e = Encoder{
+			HighResolutionCoordinates: e.HighResolutionCoordinates,
+			WriteVersion:              e.WriteVersion,
+			MaxBytes:                  e.MaxBytes,
+			buf:                       e.buf[:0],
+		}
+		return
+	}
+	highResolutionCoordinates := e.HighResolutionCoordinates
+	e.Reset(e.metadata)
+	e.HighResolutionCoordinates = highResolutionCoordinates
+}
+
 // Reset resets the Encoder for the given Metadata.
 //
 // This includes setting e.HighResolutionCoordinates to false.
 func (e *Encoder) Reset(m Metadata) {
 	*e = Encoder{
-		buf:      append(e.buf[:0], magic...),
-		metadata: m,
-		mode:     modeStyling,
-		lod1:     positiveInfinity,
+		WriteVersion: e.WriteVersion,
+		MaxBytes:     e.MaxBytes,
+		buf:          e.appendMagic(e.buf[:0]),
+		metadata:     m,
+		mode:         modeStyling,
+		lod1:         positiveInfinity,
 	}
 
 	nMetadataChunks := 0
@@ -107,6 +233,18 @@ func (e *Encoder) Reset(m Metadata) {
 	if mcSuggestedPalette {
 		nMetadataChunks++
 	}
+	mcTitle := m.Title != ""
+	if mcTitle {
+		nMetadataChunks++
+	}
+	mcPalettes := len(m.Palettes) > 0
+	if mcPalettes {
+		nMetadataChunks++
+	}
+	mcBackground := m.Background != (color.RGBA{})
+	if mcBackground {
+		nMetadataChunks++
+	}
 	e.buf.encodeNatural(uint32(nMetadataChunks))
 
 	if mcViewBox {
@@ -122,60 +260,182 @@ func (e *Encoder) Reset(m Metadata) {
 	}
 
 	if mcSuggestedPalette {
-		n := 63
-		for ; n >= 0 && m.Palette[n] == (color.RGBA{0x00, 0x00, 0x00, 0xff}); n-- {
+		e.altBuf = e.altBuf[:0]
+		e.altBuf.encodeNatural(midSuggestedPalette)
+		if e.altBuf, e.err = appendPalette(e.altBuf, &m.Palette, m.PaletteFormat); e.err != nil {
+			return
 		}
 
-		// Find the shortest encoding that can represent all of m.Palette's n+1
-		// explicit colors.
-		enc1, enc2, enc3 := true, true, true
-		for _, c := range m.Palette[:n+1] {
-			if enc1 && (!is1(c.R) || !is1(c.G) || !is1(c.B) || !is1(c.A)) {
-				enc1 = false
-			}
-			if enc2 && (!is2(c.R) || !is2(c.G) || !is2(c.B) || !is2(c.A)) {
-				enc2 = false
-			}
-			if enc3 && (c.A != 0xff) {
-				enc3 = false
-			}
+		e.buf.encodeNatural(uint32(len(e.altBuf)))
+		e.buf = append(e.buf, e.altBuf...)
+	}
+
+	if mcTitle {
+		if len(m.Title) > maxTitleLen || !utf8.ValidString(m.Title) {
+			e.err = errInvalidTitle
+			return
+		}
+		e.setVersion(titleVersion)
+		if e.err != nil {
+			return
 		}
+		e.altBuf = e.altBuf[:0]
+		e.altBuf.encodeNatural(midTitle)
+		e.altBuf = append(e.altBuf, m.Title...)
+
+		e.buf.encodeNatural(uint32(len(e.altBuf)))
+		e.buf = append(e.buf, e.altBuf...)
+	}
 
+	if mcPalettes {
+		e.setVersion(palettesVersion)
+		if e.err != nil {
+			return
+		}
 		e.altBuf = e.altBuf[:0]
-		e.altBuf.encodeNatural(midSuggestedPalette)
-		if enc1 {
-			e.altBuf = append(e.altBuf, byte(n)|0x00)
-			for _, c := range m.Palette[:n+1] {
-				x, _ := encodeColor1(RGBAColor(c))
-				e.altBuf = append(e.altBuf, x)
+		e.altBuf.encodeNatural(midPalettes)
+		e.altBuf.encodeNatural(uint32(len(m.Palettes)))
+		for i, np := range m.Palettes {
+			if len(np.Name) > maxPaletteNameLen || !utf8.ValidString(np.Name) {
+				e.err = errInvalidPalettes
+				return
 			}
-		} else if enc2 {
-			e.altBuf = append(e.altBuf, byte(n)|0x40)
-			for _, c := range m.Palette[:n+1] {
-				x, _ := encodeColor2(RGBAColor(c))
-				e.altBuf = append(e.altBuf, x[0], x[1])
-			}
-		} else if enc3 {
-			e.altBuf = append(e.altBuf, byte(n)|0x80)
-			for _, c := range m.Palette[:n+1] {
-				e.altBuf = append(e.altBuf, c.R, c.G, c.B)
+			for _, prev := range m.Palettes[:i] {
+				if prev.Name == np.Name {
+					e.err = errInvalidPalettes
+					return
+				}
 			}
-		} else {
-			e.altBuf = append(e.altBuf, byte(n)|0xc0)
-			for _, c := range m.Palette[:n+1] {
-				e.altBuf = append(e.altBuf, c.R, c.G, c.B, c.A)
+			e.altBuf.encodeNatural(uint32(len(np.Name)))
+			e.altBuf = append(e.altBuf, np.Name...)
+			if e.altBuf, e.err = appendPalette(e.altBuf, &np.Palette, m.PaletteFormat); e.err != nil {
+				return
 			}
 		}
 
 		e.buf.encodeNatural(uint32(len(e.altBuf)))
 		e.buf = append(e.buf, e.altBuf...)
 	}
+
+	if mcBackground {
+		if !validAlphaPremulColor(m.Background) {
+			e.err = errInvalidBackground
+			return
+		}
+		e.setVersion(backgroundVersion)
+		if e.err != nil {
+			return
+		}
+		e.altBuf = e.altBuf[:0]
+		e.altBuf.encodeNatural(midBackground)
+		e.altBuf.encodeColor4(RGBAColor(m.Background))
+
+		e.buf.encodeNatural(uint32(len(e.altBuf)))
+		e.buf = append(e.buf, e.altBuf...)
+	}
+	e.metadataLen = len(e.buf)
+	e.checkMaxBytes()
+}
+
+// appendPalette appends the encoding of pal's colors, in the given format,
+// to b. Trailing opaque black colors, the default, are omitted.
+func appendPalette(b buffer, pal *Palette, format PaletteFormat) (buffer, error) {
+	n := 63
+	for ; n > 0 && pal[n] == (color.RGBA{0x00, 0x00, 0x00, 0xff}); n-- {
+	}
+
+	// Find the shortest encoding that can represent all of pal's n+1
+	// explicit colors.
+	enc1, enc2, enc3 := true, true, true
+	for _, c := range pal[:n+1] {
+		if _, ok := encodeColor1(RGBAColor(c)); enc1 && !ok {
+			enc1 = false
+		}
+		if enc2 && (!is2(c.R) || !is2(c.G) || !is2(c.B) || !is2(c.A)) {
+			enc2 = false
+		}
+		if enc3 && (c.A != 0xff) {
+			enc3 = false
+		}
+	}
+
+	switch format {
+	case PaletteFormatAuto:
+		// Use the shortest encoding.
+	case PaletteFormatOneByte:
+		if !enc1 {
+			return b, errPaletteFormatTooNarrow
+		}
+	case PaletteFormatTwoByte:
+		if !enc2 {
+			return b, errPaletteFormatTooNarrow
+		}
+		enc1 = false
+	case PaletteFormatThreeByte:
+		if !enc3 {
+			return b, errPaletteFormatTooNarrow
+		}
+		enc1, enc2 = false, false
+	case PaletteFormatFourByte:
+		enc1, enc2, enc3 = false, false, false
+	default:
+		return b, errInvalidPaletteFormat
+	}
+
+	if enc1 {
+		b = append(b, byte(n)|0x00)
+		for _, c := range pal[:n+1] {
+			x, _ := encodeColor1(RGBAColor(c))
+			b = append(b, x)
+		}
+	} else if enc2 {
+		b = append(b, byte(n)|0x40)
+		for _, c := range pal[:n+1] {
+			x, _ := encodeColor2(RGBAColor(c))
+			b = append(b, x[0], x[1])
+		}
+	} else if enc3 {
+		b = append(b, byte(n)|0x80)
+		for _, c := range pal[:n+1] {
+			b = append(b, c.R, c.G, c.B)
+		}
+	} else {
+		b = append(b, byte(n)|0xc0)
+		for _, c := range pal[:n+1] {
+			b = append(b, c.R, c.G, c.B, c.A)
+		}
+	}
+	return b, nil
+}
+
+// appendMagic appends the magic identifier, and the version indicator if
+// e.WriteVersion is set, to b. The indicator starts out as version 0, and
+// is raised by setVersion.
+func (e *Encoder) appendMagic(b buffer) buffer {
+	b = append(b, magic...)
+	if e.WriteVersion {
+		b = append(b, versionIndicator|0)
+	}
+	return b
+}
+
+// setVersion raises the graphic's version indicator to at least v.
+func (e *Encoder) setVersion(v byte) {
+	if !e.WriteVersion {
+		e.err = errVersionIndicatorRequired
+		return
+	}
+	if e.buf[len(magic)]&^versionIndicator < v {
+		e.buf[len(magic)] = versionIndicator | v
+	}
 }
 
 func (e *Encoder) appendDefaultMetadata() {
-	e.buf = append(e.buf[:0], magic...)
+	e.buf = e.appendMagic(e.buf[:0])
 	e.buf = append(e.buf, 0x00) // There are zero metadata chunks.
+	e.metadataLen = len(e.buf)
 	e.mode = modeStyling
+	e.lod1 = positiveInfinity
 }
 
 func (e *Encoder) CSel() uint8 {
@@ -199,12 +459,23 @@ func (e *Encoder) LOD() (lod0, lod1 float32) {
 	return e.lod0, e.lod1
 }
 
+// checkMaxBytes sets a sticky error if the opcodes written so far exceed
+// e.MaxBytes. It is called before each styling op and after flushing drawing
+// ops, so that encoding stops shortly after the budget is exceeded.
+func (e *Encoder) checkMaxBytes() {
+	if e.err == nil && e.MaxBytes > 0 && len(e.buf) > e.MaxBytes {
+		e.err = errExceedsMaxBytes
+	}
+}
+
 func (e *Encoder) checkModeStyling() {
 	if e.mode == modeStyling {
+		e.checkMaxBytes()
 		return
 	}
 	if e.mode == modeInitial {
 		e.appendDefaultMetadata()
+		e.checkMaxBytes()
 		return
 	}
 	e.err = errStylingOpsUsedInDrawingMode
@@ -267,20 +538,35 @@ func (e *Encoder) SetCReg(adj uint8, incr bool, c Color) {
 	panic("unreachable")
 }
 
-func (e *Encoder) SetNReg(adj uint8, incr bool, f float32) {
-	e.checkModeStyling()
-	if e.err != nil {
+// SetCRegWithOpacity is like SetCReg, but with the opacity of base scaled by
+// opacity, which is clamped to the range [0, 1].
+//
+// The color is encoded as a 3 byte indirect color that blends fully
+// transparent black with base, so base must be encodable as a 1 byte color.
+// See BlendColor for how to use other colors.
+func (e *Encoder) SetCRegWithOpacity(adj uint8, incr bool, base Color, opacity float32) {
+	c1, ok := encodeColor1(base)
+	if !ok {
+		e.checkModeStyling()
+		if e.err == nil {
+			e.err = errNotEncodableAs1ByteColor
+		}
 		return
 	}
-	if adj > 6 {
-		e.err = errInvalidSelectorAdjustment
-		return
+	t := uint8(0)
+	if opacity >= 1 {
+		t = 0xff
+	} else if opacity > 0 {
+		t = uint8(opacity*0xff + 0.5)
 	}
-	if incr {
-		if adj != 0 {
-			e.err = errInvalidIncrementingAdjustment
-		}
-		adj = 7
+	// 0x7f is the 1 byte encoding of transparent black.
+	e.SetCReg(adj, incr, BlendColor(t, 0x7f, c1))
+}
+
+func (e *Encoder) SetNReg(adj uint8, incr bool, f float32) {
+	adj, ok := e.nRegAdj(adj, incr)
+	if !ok {
+		return
 	}
 
 	// Try three different encodings and pick the shortest.
@@ -301,6 +587,55 @@ func (e *Encoder) SetNReg(adj uint8, incr bool, f float32) {
 	e.buf = append(e.buf, e.scratch[iBest:iBest+nBest]...)
 }
 
+// SetNRegReal is like SetNReg, but always uses the real number encoding
+// (opcodes 0xa8 to 0xaf).
+func (e *Encoder) SetNRegReal(adj uint8, incr bool, f float32) {
+	if adj, ok := e.nRegAdj(adj, incr); ok {
+		e.buf = append(e.buf, adj|0xa8)
+		e.buf.encodeReal(f)
+	}
+}
+
+// SetNRegCoordinate is like SetNReg, but always uses the coordinate number
+// encoding (opcodes 0xb0 to 0xb7).
+func (e *Encoder) SetNRegCoordinate(adj uint8, incr bool, f float32) {
+	if adj, ok := e.nRegAdj(adj, incr); ok {
+		e.buf = append(e.buf, adj|0xb0)
+		e.buf.encodeCoordinate(f)
+	}
+}
+
+// SetNRegZeroToOne is like SetNReg, but always uses the zero-to-one number
+// encoding (opcodes 0xb8 to 0xbf). Values outside of the range [0, 1), or
+// that are not a multiple of 1/15120, take 4 bytes.
+func (e *Encoder) SetNRegZeroToOne(adj uint8, incr bool, f float32) {
+	if adj, ok := e.nRegAdj(adj, incr); ok {
+		e.buf = append(e.buf, adj|0xb8)
+		e.buf.encodeZeroToOne(f)
+	}
+}
+
+// nRegAdj checks the arguments to the SetNRegXxx methods and returns the
+// selector adjustment part of the opcode. It returns false if nothing should
+// be encoded.
+func (e *Encoder) nRegAdj(adj uint8, incr bool) (uint8, bool) {
+	e.checkModeStyling()
+	if e.err != nil {
+		return 0, false
+	}
+	if adj > 6 {
+		e.err = errInvalidSelectorAdjustment
+		return 0, false
+	}
+	if incr {
+		if adj != 0 {
+			e.err = errInvalidIncrementingAdjustment
+		}
+		adj = 7
+	}
+	return adj, true
+}
+
 func (e *Encoder) SetLOD(lod0, lod1 float32) {
 	e.checkModeStyling()
 	if e.err != nil {
@@ -313,6 +648,37 @@ func (e *Encoder) SetLOD(lod0, lod1 float32) {
 	e.buf.encodeReal(lod1)
 }
 
+// PushClip saves the clip region, and makes the next path a clip path, which
+// is not filled but instead intersects the clip region with the path's
+// interior. Subsequent paths are only drawn inside the clip region.
+//
+// Clip paths need version 1 of the graphic format, so e.WriteVersion must be
+// set.
+func (e *Encoder) PushClip() {
+	e.checkModeStyling()
+	if e.err != nil {
+		return
+	}
+	e.setVersion(clipVersion)
+	if e.err != nil {
+		return
+	}
+	e.buf = append(e.buf, 0xc8)
+}
+
+// PopClip restores the clip region saved by the matching PushClip.
+func (e *Encoder) PopClip() {
+	e.checkModeStyling()
+	if e.err != nil {
+		return
+	}
+	e.setVersion(clipVersion)
+	if e.err != nil {
+		return
+	}
+	e.buf = append(e.buf, 0xc9)
+}
+
 // SetGradient sets CREG[CSEL] to encode the gradient whose colors defined by
 // spread and stops. Its geometry is either linear or radial, depending on the
 // radial argument, and the given affine transformation matrix maps from
@@ -436,6 +802,12 @@ func (e *Encoder) SetEllipticalGradient(cBase, nBase uint8, cx, cy, rx, ry, sx,
 }
 
 func (e *Encoder) StartPath(adj uint8, x, y float32) {
+	if e.mode == modeDrawing {
+		if e.err == nil {
+			e.err = errPathNotEnded
+		}
+		return
+	}
 	e.checkModeStyling()
 	if e.err != nil {
 		return
@@ -477,7 +849,32 @@ func (e *Encoder) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool,
 	e.arcTo('a', rx, ry, xAxisRotation, largeArc, sweep, x, y)
 }
 
+// AppendRect appends a path for the axis-aligned rectangle from (minX, minY)
+// to (maxX, maxY), filled with CREG[CSEL-adj]. It is equivalent to calling
+// StartPath, three line-to methods and ClosePathEndPath.
+func (e *Encoder) AppendRect(adj uint8, minX, minY, maxX, maxY float32) {
+	e.StartPath(adj, minX, minY)
+	e.AbsHLineTo(maxX)
+	e.AbsVLineTo(maxY)
+	e.AbsHLineTo(minX)
+	e.ClosePathEndPath()
+}
+
+// AppendCircle appends a path for the circle with center (cx, cy) and radius
+// r, filled with CREG[CSEL-adj]. It is equivalent to calling StartPath, two
+// AbsArcTo calls that each trace half of the circle, and ClosePathEndPath.
+func (e *Encoder) AppendCircle(adj uint8, cx, cy, r float32) {
+	e.StartPath(adj, cx+r, cy)
+	e.AbsArcTo(r, r, 0, false, true, cx-r, cy)
+	e.AbsArcTo(r, r, 0, false, true, cx+r, cy)
+	e.ClosePathEndPath()
+}
+
 func (e *Encoder) arcTo(drawOp byte, rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	e.draw(drawOp, rx, ry, xAxisRotation, arcFlags(largeArc, sweep), x, y)
+}
+
+func arcFlags(largeArc, sweep bool) float32 {
 	flags := uint32(0)
 	if largeArc {
 		flags |= 0x01
@@ -485,7 +882,7 @@ func (e *Encoder) arcTo(drawOp byte, rx, ry, xAxisRotation float32, largeArc, sw
 	if sweep {
 		flags |= 0x02
 	}
-	e.draw(drawOp, rx, ry, xAxisRotation, float32(flags), x, y)
+	return float32(flags)
 }
 
 func (e *Encoder) draw(drawOp byte, arg0, arg1, arg2, arg3, arg4, arg5 float32) {
@@ -493,7 +890,12 @@ func (e *Encoder) draw(drawOp byte, arg0, arg1, arg2, arg3, arg4, arg5 float32)
 		return
 	}
 	if e.mode != modeDrawing {
-		e.err = errDrawingOpsUsedInStylingMode
+		switch drawOp {
+		case 'Z', 'Y', 'y':
+			e.err = errPathNotStarted
+		default:
+			e.err = errDrawingOpsUsedInStylingMode
+		}
 		return
 	}
 	if e.drawOp != drawOp {
@@ -564,6 +966,7 @@ func (e *Encoder) flushDrawOps() {
 
 	e.drawOp = 0x00
 	e.drawArgs = e.drawArgs[:0]
+	e.checkMaxBytes()
 }
 
 func quantize(coord float32, highResolutionCoordinates bool) float32 {
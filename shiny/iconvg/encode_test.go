@@ -11,8 +11,10 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strconv"
+	"strings"
 	"testing"
 
 	"golang.org/x/image/math/f32"
@@ -23,10 +25,14 @@ import (
 var updateFlag = flag.Bool("update", false, "Overwrite testdata files.")
 
 func testEncode(t *testing.T, e *Encoder, wantFilename string) {
+	n := e.Len()
 	got, err := e.Bytes()
 	if err != nil {
 		t.Fatalf("encoding: %v", err)
 	}
+	if n != len(got) {
+		t.Errorf("Len: got %d, want %d", n, len(got))
+	}
 	if *updateFlag {
 		if err := os.WriteFile(filepath.FromSlash(wantFilename), got, 0666); err != nil {
 			t.Fatalf("WriteFile: %v", err)
@@ -56,6 +62,276 @@ func testEncode(t *testing.T, e *Encoder, wantFilename string) {
 	}
 }
 
+func TestEncodeLen(t *testing.T) {
+	for _, writeVersion := range []bool{false, true} {
+		// Before any other method is called, Bytes writes default metadata.
+		e := Encoder{WriteVersion: writeVersion}
+		n := e.Len()
+		b, err := e.Bytes()
+		if err != nil {
+			t.Fatalf("Bytes: %v", err)
+		}
+		if n != len(b) {
+			t.Errorf("WriteVersion=%t: Len: got %d, want %d", writeVersion, n, len(b))
+		}
+	}
+
+	// An adjustment of 7 is invalid unless incrementing.
+	var e Encoder
+	e.SetNReg(7, false, 0)
+	if _, err := e.Bytes(); err == nil {
+		t.Fatal("Bytes: got nil error, want non-nil")
+	}
+	if got := e.Len(); got != 0 {
+		t.Errorf("Len after an error: got %d, want 0", got)
+	}
+}
+
+func TestEncodeResetPaths(t *testing.T) {
+	var e Encoder
+	e.Reset(Metadata{
+		ViewBox: Rectangle{Min: f32.Vec2{-24, -24}, Max: f32.Vec2{+24, +24}},
+		Palette: DefaultPalette,
+	})
+	prefix := len(e.buf)
+
+	e.HighResolutionCoordinates = true
+	e.AppendRect(0, -8, -8, 8, 8)
+	b0, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes #0: %v", err)
+	}
+	b0 = append([]byte(nil), b0...)
+
+	e.ResetPaths()
+	if !e.HighResolutionCoordinates {
+		t.Errorf("ResetPaths: HighResolutionCoordinates was cleared")
+	}
+	e.AppendCircle(0, 0, 0, 16)
+	b1, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes #1: %v", err)
+	}
+
+	if !bytes.Equal(b0[:prefix], b1[:prefix]) {
+		t.Errorf("metadata differs:\n% x\n% x", b0[:prefix], b1[:prefix])
+	}
+	if bytes.Equal(b0[prefix:], b1[prefix:]) {
+		t.Errorf("geometry is the same:\n% x", b0[prefix:])
+	}
+	m0, err0 := DecodeMetadata(b0)
+	m1, err1 := DecodeMetadata(b1)
+	if err0 != nil || err1 != nil || !reflect.DeepEqual(m0, m1) {
+		t.Errorf("DecodeMetadata: got %v, %v and %v, %v", m0, err0, m1, err1)
+	}
+
+	// Before any other method, ResetPaths keeps the default metadata.
+	var d Encoder
+	d.ResetPaths()
+	d.AppendRect(0, -8, -8, 8, 8)
+	var want Encoder
+	want.AppendRect(0, -8, -8, 8, 8)
+	if got, want := d.buf, want.buf; !bytes.Equal(got, want) {
+		t.Errorf("ResetPaths before Reset: got % x, want % x", got, want)
+	}
+}
+
+func TestEncodeWriteTo(t *testing.T) {
+	var e Encoder
+	e.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x80, 0xff}))
+	e.AppendRect(0, -24, -16, +24, +16)
+
+	want, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	var buf bytes.Buffer
+	n, err := e.WriteTo(&buf)
+	if err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	if n != int64(len(want)) || !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("WriteTo wrote %d bytes:\n% x\nwant %d bytes:\n% x", n, buf.Bytes(), len(want), want)
+	}
+
+	// Encoding errors are sticky.
+	e.AbsLineTo(0, 0)
+	buf.Reset()
+	if n, err := e.WriteTo(&buf); err != errDrawingOpsUsedInStylingMode || n != 0 || buf.Len() != 0 {
+		t.Errorf("WriteTo after error: got %d, %v, want 0, %v", n, err, errDrawingOpsUsedInStylingMode)
+	}
+}
+
+func TestEncodePathErrors(t *testing.T) {
+	var e Encoder
+	e.ClosePathEndPath()
+	if _, err := e.Bytes(); err != errPathNotStarted {
+		t.Errorf("ClosePathEndPath without StartPath: got %v, want %v", err, errPathNotStarted)
+	}
+
+	e = Encoder{}
+	e.StartPath(0, 0, 0)
+	e.AbsLineTo(8, 0)
+	if _, err := e.Bytes(); err != errPathNotEnded {
+		t.Errorf("unended path: got %v, want %v", err, errPathNotEnded)
+	}
+	e.StartPath(0, 0, 0)
+	e.ClosePathEndPath()
+	if _, err := e.Bytes(); err != errPathNotEnded {
+		t.Errorf("nested StartPath: got %v, want %v", err, errPathNotEnded)
+	}
+
+	// Closing a path twice is also an error.
+	e = Encoder{}
+	e.AppendRect(0, 0, 0, 8, 8)
+	e.ClosePathEndPath()
+	if _, err := e.Bytes(); err != errPathNotStarted {
+		t.Errorf("ClosePathEndPath twice: got %v, want %v", err, errPathNotStarted)
+	}
+}
+
+func TestEncodeMaxBytes(t *testing.T) {
+	encode := func(maxBytes int) ([]byte, error) {
+		var e Encoder
+		e.MaxBytes = maxBytes
+		e.Reset(Metadata{
+			ViewBox: DefaultViewBox,
+			Palette: DefaultPalette,
+		})
+		for _, data := range faviconSVGData {
+			if err := encodePathData(&e, data.d, 0, true); err != nil {
+				return nil, err
+			}
+		}
+		return e.Bytes()
+	}
+
+	full, err := encode(0)
+	if err != nil {
+		t.Fatalf("no budget: %v", err)
+	}
+	if _, err := encode(len(full)); err != nil {
+		t.Errorf("budget of %d bytes: %v", len(full), err)
+	}
+	for _, maxBytes := range []int{1, 64, len(full) - 1} {
+		if _, err := encode(maxBytes); err != errExceedsMaxBytes {
+			t.Errorf("budget of %d bytes: got %v, want %v", maxBytes, err, errExceedsMaxBytes)
+		}
+	}
+}
+
+func TestEncodeCRegWithOpacity(t *testing.T) {
+	// This is the 25% opaque "Orange 200" example from TestBlendColor.
+	var e Encoder
+	e.SetCRegWithOpacity(0, false, PaletteIndexColor(2), 0.25)
+	got, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	want := append([]byte(nil), magic...)
+	want = append(want,
+		0x00,             // Zero metadata chunks.
+		0xa0,             // Set CREG[CSEL-0] to a 3 byte (indirect) color.
+		0x40, 0x7f, 0x82, // 25% blend of transparent black and CUSTOM_PALETTE[2].
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("\ngot  % x\nwant % x", got, want)
+	}
+
+	for _, tc := range []struct {
+		opacity float32
+		want    byte
+	}{
+		{-1, 0x00},
+		{0, 0x00},
+		{0.5, 0x80},
+		{1, 0xff},
+		{2, 0xff},
+	} {
+		var e Encoder
+		e.SetCRegWithOpacity(0, false, PaletteIndexColor(2), tc.opacity)
+		got, err := e.Bytes()
+		if err != nil {
+			t.Errorf("opacity %v: Bytes: %v", tc.opacity, err)
+			continue
+		}
+		if g := got[len(got)-3]; g != tc.want {
+			t.Errorf("opacity %v: got blend %#02x, want %#02x", tc.opacity, g, tc.want)
+		}
+	}
+
+	e = Encoder{}
+	e.SetCRegWithOpacity(0, false, RGBAColor(color.RGBA{0x12, 0x34, 0x56, 0xff}), 0.5)
+	if _, err := e.Bytes(); err != errNotEncodableAs1ByteColor {
+		t.Errorf("base not encodable as 1 byte: got %v, want %v", err, errNotEncodableAs1ByteColor)
+	}
+}
+
+func TestEncodeNRegKinds(t *testing.T) {
+	const (
+		fifteenDegrees = 15.0 / 360
+		fortyDegrees   = 40.0 / 360
+	)
+	testCases := []struct {
+		desc string
+		set  func(e *Encoder)
+		want []byte
+	}{{
+		desc: "real 1",
+		set:  func(e *Encoder) { e.SetNRegReal(0, false, 1) },
+		want: []byte{0xa8, 0x02},
+	}, {
+		desc: "real 200",
+		set:  func(e *Encoder) { e.SetNRegReal(2, false, 200) },
+		want: []byte{0xaa, 0x21, 0x03},
+	}, {
+		desc: "real 0.5",
+		set:  func(e *Encoder) { e.SetNRegReal(0, true, 0.5) },
+		want: []byte{0xaf, 0x03, 0x00, 0x00, 0x3f},
+	}, {
+		desc: "coordinate 0",
+		set:  func(e *Encoder) { e.SetNRegCoordinate(0, false, 0) },
+		want: []byte{0xb0, 0x80},
+	}, {
+		desc: "coordinate 0.5",
+		set:  func(e *Encoder) { e.SetNRegCoordinate(1, false, 0.5) },
+		want: []byte{0xb1, 0x81, 0x80},
+	}, {
+		desc: "zero-to-one 15 degrees",
+		set:  func(e *Encoder) { e.SetNRegZeroToOne(0, false, fifteenDegrees) },
+		want: []byte{0xb8, 0x0a},
+	}, {
+		desc: "zero-to-one 40 degrees",
+		set:  func(e *Encoder) { e.SetNRegZeroToOne(0, true, fortyDegrees) },
+		want: []byte{0xbf, 0x41, 0x1a},
+	}, {
+		desc: "zero-to-one 1",
+		set:  func(e *Encoder) { e.SetNRegZeroToOne(0, false, 1) },
+		want: []byte{0xb8, 0x03, 0x00, 0x80, 0x3f},
+	}}
+	for _, tc := range testCases {
+		var e Encoder
+		tc.set(&e)
+		got, err := e.Bytes()
+		if err != nil {
+			t.Errorf("%s: Bytes: %v", tc.desc, err)
+			continue
+		}
+		want := append([]byte(nil), magic...)
+		want = append(want, 0x00) // Zero metadata chunks.
+		want = append(want, tc.want...)
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s:\ngot  % x\nwant % x", tc.desc, got, want)
+		}
+	}
+
+	var e Encoder
+	e.SetNRegZeroToOne(7, false, 0)
+	if _, err := e.Bytes(); err != errInvalidSelectorAdjustment {
+		t.Errorf("adj 7: got %v, want %v", err, errInvalidSelectorAdjustment)
+	}
+}
+
 func TestEncodeActionInfo(t *testing.T) {
 	for _, res := range []string{"lores", "hires"} {
 		var e Encoder
@@ -592,6 +868,272 @@ func TestEncodeGradient(t *testing.T) {
 	testEncode(t, &e, "testdata/gradient.ivg")
 }
 
+func TestEncodeClip(t *testing.T) {
+	rgb := []GradientStop{
+		{Offset: 0.00, Color: color.RGBA{0xff, 0x00, 0x00, 0xff}},
+		{Offset: 0.50, Color: color.RGBA{0x00, 0xff, 0x00, 0xff}},
+		{Offset: 1.00, Color: color.RGBA{0x00, 0x00, 0xff, 0xff}},
+	}
+
+	var e Encoder
+	e.WriteVersion = true
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+
+	// Fill a square with a gradient, clipped to a circle.
+	e.PushClip()
+	e.AppendCircle(0, 0, 0, 24)
+	e.SetLinearGradient(10, 10, -28, -28, +28, +28, GradientSpreadNone, rgb)
+	e.AppendRect(0, -28, -28, +28, +28)
+	e.PopClip()
+
+	// After PopClip, paths are no longer clipped.
+	e.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x00, 0x80}))
+	e.AppendRect(0, +12, +12, +30, +30)
+
+	testEncode(t, &e, "testdata/clip.ivg")
+}
+
+func TestEncodeClipRequiresVersion(t *testing.T) {
+	var e Encoder
+	e.PushClip()
+	if _, err := e.Bytes(); err != errVersionIndicatorRequired {
+		t.Errorf("PushClip without WriteVersion: got %v, want %v", err, errVersionIndicatorRequired)
+	}
+
+	// A graphic without clips keeps a version 0 indicator.
+	e = Encoder{WriteVersion: true}
+	e.AppendRect(0, -8, -8, 8, 8)
+	b, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	if got, want := b[len(magic)], byte(versionIndicator|0); got != want {
+		t.Errorf("version indicator: got %#02x, want %#02x", got, want)
+	}
+}
+
+func TestEncodeTitle(t *testing.T) {
+	for _, title := range []string{"Information", "Información ℹ", strings.Repeat("x", maxTitleLen)} {
+		var e Encoder
+		e.WriteVersion = true
+		e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette, Title: title})
+		e.AppendRect(0, -8, -8, 8, 8)
+		src, err := e.Bytes()
+		if err != nil {
+			t.Errorf("%.20q: Bytes: %v", title, err)
+			continue
+		}
+
+		m, err := DecodeMetadata(src)
+		if err != nil {
+			t.Errorf("%.20q: DecodeMetadata: %v", title, err)
+			continue
+		}
+		if m.Title != title {
+			t.Errorf("%.20q: DecodeMetadata: got title %.20q", title, m.Title)
+		}
+
+		var got Encoder
+		got.WriteVersion = true
+		if err := Decode(&got, src, nil); err != nil {
+			t.Errorf("%.20q: Decode: %v", title, err)
+			continue
+		}
+		if b, _ := got.Bytes(); !bytes.Equal(b, src) {
+			t.Errorf("%.20q: round trip: got % x, want % x", title, b, src)
+		}
+
+		if _, err := disassemble(src); err != nil {
+			t.Errorf("%.20q: disassemble: %v", title, err)
+		}
+
+		// Invalid UTF-8 is rejected when decoding, too.
+		invalid := append([]byte(nil), src...)
+		invalid[bytes.Index(invalid, []byte(title))] = 0xff
+		if _, err := DecodeMetadata(invalid); err != errInvalidTitle {
+			t.Errorf("%.20q: invalid UTF-8: got %v, want %v", title, err, errInvalidTitle)
+		}
+
+		// Without a version indicator, the title chunk is not recognized.
+		unversioned := append(append([]byte(nil), src[:len(magic)]...), src[len(magic)+1:]...)
+		if _, err := DecodeMetadata(unversioned); err != errUnsupportedMetadataIdentifier {
+			t.Errorf("%.20q: unversioned: got %v, want %v", title, err, errUnsupportedMetadataIdentifier)
+		}
+	}
+
+	testCases := []struct {
+		title        string
+		writeVersion bool
+		want         error
+	}{
+		{"Information", false, errVersionIndicatorRequired},
+		{"\xff", true, errInvalidTitle},
+		{strings.Repeat("x", maxTitleLen+1), true, errInvalidTitle},
+	}
+	for _, tc := range testCases {
+		e := Encoder{WriteVersion: tc.writeVersion}
+		e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette, Title: tc.title})
+		if _, err := e.Bytes(); err != tc.want {
+			t.Errorf("%.20q: got %v, want %v", tc.title, err, tc.want)
+		}
+	}
+}
+
+func TestEncodePalettes(t *testing.T) {
+	suggested, light, dark := DefaultPalette, DefaultPalette, DefaultPalette
+	suggested[1] = color.RGBA{0x80, 0x80, 0x80, 0xff}
+	light[0] = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	light[1] = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	dark[0] = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	dark[1] = color.RGBA{0x11, 0x22, 0x33, 0xff}
+	palettes := []NamedPalette{
+		{Name: "light", Palette: light},
+		{Name: "dark", Palette: dark},
+	}
+
+	e := Encoder{WriteVersion: true}
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: suggested, Palettes: palettes})
+	e.SetCReg(0, false, PaletteIndexColor(1))
+	e.AppendRect(0, -16, -16, 16, 16)
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	m, err := DecodeMetadata(src)
+	if err != nil {
+		t.Fatalf("DecodeMetadata: %v", err)
+	}
+	if !reflect.DeepEqual(m.Palettes, palettes) {
+		t.Errorf("DecodeMetadata: got palettes %v, want %v", m.Palettes, palettes)
+	}
+	if m.Palette != suggested {
+		t.Errorf("DecodeMetadata: got palette %v, want the suggested palette", m.Palette[:2])
+	}
+	if _, err := disassemble(src); err != nil {
+		t.Errorf("disassemble: %v", err)
+	}
+
+	testCases := []struct {
+		opts *DecodeOptions
+		want Palette
+	}{
+		{nil, suggested},
+		{&DecodeOptions{}, suggested},
+		{&DecodeOptions{PaletteVariant: "light"}, light},
+		{&DecodeOptions{PaletteVariant: "dark"}, dark},
+		{&DecodeOptions{PaletteVariant: "sepia"}, light},
+		{&DecodeOptions{PaletteVariant: "dark", Palette: &DefaultPalette}, DefaultPalette},
+	}
+	for i, tc := range testCases {
+		var got Encoder
+		got.WriteVersion = true
+		if err := Decode(&got, src, tc.opts); err != nil {
+			t.Errorf("#%d: Decode: %v", i, err)
+			continue
+		}
+		if got.metadata.Palette != tc.want {
+			t.Errorf("#%d: got palette %v, want %v", i, got.metadata.Palette[:2], tc.want[:2])
+		}
+		if !reflect.DeepEqual(got.metadata.Palettes, palettes) {
+			t.Errorf("#%d: got palettes %v, want %v", i, got.metadata.Palettes, palettes)
+		}
+	}
+
+	// Canonicalizing keeps both the suggested palette and the variants.
+	c, err := Canonicalize(src)
+	if err != nil {
+		t.Fatalf("Canonicalize: %v", err)
+	}
+	if !bytes.Equal(c, src) {
+		t.Errorf("Canonicalize:\ngot  % x\nwant % x", c, src)
+	}
+
+	for _, tc := range []struct {
+		palettes     []NamedPalette
+		writeVersion bool
+		want         error
+	}{
+		{palettes, false, errVersionIndicatorRequired},
+		{[]NamedPalette{{Name: "\xff"}}, true, errInvalidPalettes},
+		{[]NamedPalette{{Name: strings.Repeat("x", maxPaletteNameLen+1)}}, true, errInvalidPalettes},
+		{[]NamedPalette{{Name: "light"}, {Name: "light"}}, true, errInvalidPalettes},
+	} {
+		e := Encoder{WriteVersion: tc.writeVersion}
+		e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette, Palettes: tc.palettes})
+		if _, err := e.Bytes(); err != tc.want {
+			t.Errorf("%d palettes: got %v, want %v", len(tc.palettes), err, tc.want)
+		}
+	}
+}
+
+func TestEncodePaletteFormat(t *testing.T) {
+	blue := DefaultPalette
+	blue[0] = color.RGBA{0x00, 0x00, 0xff, 0xff}
+	blue[1] = color.RGBA{0xff, 0x00, 0xff, 0xff}
+	gray := DefaultPalette
+	gray[0] = color.RGBA{0x11, 0x22, 0x33, 0xff}
+	translucent := DefaultPalette
+	translucent[0] = color.RGBA{0x00, 0x00, 0x40, 0x40}
+
+	testCases := []struct {
+		palette Palette
+		format  PaletteFormat
+		// wantLen is the length of the metadata, or -1 for an error.
+		wantLen int
+	}{
+		{blue, PaletteFormatAuto, 8 + 2*1},
+		{blue, PaletteFormatOneByte, 8 + 2*1},
+		{blue, PaletteFormatTwoByte, 8 + 2*2},
+		{blue, PaletteFormatThreeByte, 8 + 2*3},
+		{blue, PaletteFormatFourByte, 8 + 2*4},
+		{blue, PaletteFormatFourByte + 1, -1},
+
+		{gray, PaletteFormatAuto, 8 + 2},
+		{gray, PaletteFormatOneByte, -1},
+		{gray, PaletteFormatTwoByte, 8 + 2},
+		{gray, PaletteFormatThreeByte, 8 + 3},
+		{gray, PaletteFormatFourByte, 8 + 4},
+
+		{translucent, PaletteFormatAuto, 8 + 4},
+		{translucent, PaletteFormatThreeByte, -1},
+		{translucent, PaletteFormatFourByte, 8 + 4},
+	}
+
+	for _, tc := range testCases {
+		var e Encoder
+		e.Reset(Metadata{
+			ViewBox:       DefaultViewBox,
+			Palette:       tc.palette,
+			PaletteFormat: tc.format,
+		})
+		got, err := e.Bytes()
+		if tc.wantLen < 0 {
+			if err == nil {
+				t.Errorf("palette %v, format %d: got no error", tc.palette[0], tc.format)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("palette %v, format %d: %v", tc.palette[0], tc.format, err)
+			continue
+		}
+		if len(got) != tc.wantLen {
+			t.Errorf("palette %v, format %d: got %d bytes, want %d", tc.palette[0], tc.format, len(got), tc.wantLen)
+			continue
+		}
+
+		m, err := DecodeMetadata(got)
+		if err != nil {
+			t.Errorf("palette %v, format %d: DecodeMetadata: %v", tc.palette[0], tc.format, err)
+			continue
+		}
+		if m.Palette != tc.palette {
+			t.Errorf("palette %v, format %d: palette did not round trip", tc.palette[0], tc.format)
+		}
+	}
+}
+
 func TestEncodeLODPolygon(t *testing.T) {
 	var e Encoder
 
@@ -628,6 +1170,48 @@ func TestEncodeLODPolygon(t *testing.T) {
 	testEncode(t, &e, "testdata/lod-polygon.ivg")
 }
 
+func TestEncodeRepCounts(t *testing.T) {
+	testCases := []struct {
+		nVertices int
+		want      []string
+	}{
+		{20, []string{"L (absolute lineTo), 19 reps"}},
+		{33, []string{"L (absolute lineTo), 32 reps"}},
+		{40, []string{"L (absolute lineTo), 32 reps", "L (absolute lineTo), 7 reps"}},
+	}
+	for _, tc := range testCases {
+		var e Encoder
+		const r = 28
+		angle := 2 * math.Pi / float64(tc.nVertices)
+		e.StartPath(0, r, 0)
+		for i := 1; i < tc.nVertices; i++ {
+			e.AbsLineTo(
+				float32(r*math.Cos(angle*float64(i))),
+				float32(r*math.Sin(angle*float64(i))),
+			)
+		}
+		e.ClosePathEndPath()
+		b, err := e.Bytes()
+		if err != nil {
+			t.Fatalf("%d vertices: Bytes: %v", tc.nVertices, err)
+		}
+		disasm, err := disassemble(b)
+		if err != nil {
+			t.Fatalf("%d vertices: disassemble: %v", tc.nVertices, err)
+		}
+
+		var got []string
+		for _, line := range strings.Split(string(disasm), "\n") {
+			if i := strings.Index(line, "L (absolute lineTo), "); i >= 0 && !strings.HasSuffix(line, "implicit") {
+				got = append(got, line[i:])
+			}
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%d vertices: lineTo opcodes: got %q, want %q", tc.nVertices, got, tc.want)
+		}
+	}
+}
+
 var video005PrimitiveSVGData = []struct {
 	r, g, b uint32
 	x0, y0  int
@@ -706,3 +1290,148 @@ func TestEncodeVideo005Primitive(t *testing.T) {
 
 	testEncode(t, &e, "testdata/video-005.primitive.ivg")
 }
+
+func TestEncodeQuads(t *testing.T) {
+	var e Encoder
+	e.StartPath(0, 0, 0)
+	e.AbsQuadTo(1, 2, 3, 4)
+	e.RelQuadTo(1, 1, 2, 2)
+	e.RelQuadTo(-1, -1, -2, -2)
+	e.AbsSmoothQuadTo(5, 6)
+	e.RelSmoothQuadTo(1, 0)
+	e.RelSmoothQuadTo(0, 1)
+	e.RelSmoothQuadTo(-1, 0)
+	for i := 0; i < 17; i++ {
+		e.AbsSmoothQuadTo(1, 0)
+	}
+	e.ClosePathEndPath()
+	got, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	want := append([]byte(nil), magic...)
+	want = append(want,
+		0x00,             // Zero metadata chunks.
+		0xc0, 0x80, 0x80, // Start path at (0, 0).
+		0x60,                   // Q (absolute quadTo), 1 rep.
+		0x82, 0x84, 0x86, 0x88, // (1, 2), (3, 4).
+		0x71,                   // q (relative quadTo), 2 reps.
+		0x82, 0x82, 0x84, 0x84, // (1, 1), (2, 2).
+		0x7e, 0x7e, 0x7c, 0x7c, // (-1, -1), (-2, -2).
+		0x40,       // T (absolute smooth quadTo), 1 rep.
+		0x8a, 0x8c, // (5, 6).
+		0x52,       // t (relative smooth quadTo), 3 reps.
+		0x82, 0x80, // (1, 0).
+		0x80, 0x82, // (0, 1).
+		0x7e, 0x80, // (-1, 0).
+		0x4f, // T (absolute smooth quadTo), 16 reps.
+	)
+	for i := 0; i < 16; i++ {
+		want = append(want, 0x82, 0x80) // (1, 0).
+	}
+	want = append(want,
+		0x40,       // T (absolute smooth quadTo), 1 rep.
+		0x82, 0x80, // (1, 0).
+		0xe1, // z (closePath); end path.
+	)
+	if !bytes.Equal(got, want) {
+		t.Errorf("\ngot  % x\nwant % x", got, want)
+	}
+}
+
+func TestEncodeShapes(t *testing.T) {
+	var got, want Encoder
+
+	got.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x80, 0xff}))
+	got.AppendRect(0, -24, -16, +24, +16)
+	got.SetCReg(0, false, RGBAColor(color.RGBA{0xff, 0xff, 0x00, 0xff}))
+	got.AppendCircle(0, 4, -2, 10.5)
+
+	want.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x80, 0xff}))
+	want.StartPath(0, -24, -16)
+	want.AbsHLineTo(+24)
+	want.AbsVLineTo(+16)
+	want.AbsHLineTo(-24)
+	want.ClosePathEndPath()
+	want.SetCReg(0, false, RGBAColor(color.RGBA{0xff, 0xff, 0x00, 0xff}))
+	want.StartPath(0, 14.5, -2)
+	want.AbsArcTo(10.5, 10.5, 0, false, true, -6.5, -2)
+	want.AbsArcTo(10.5, 10.5, 0, false, true, 14.5, -2)
+	want.ClosePathEndPath()
+
+	gotBytes, err := got.Bytes()
+	if err != nil {
+		t.Fatalf("got.Bytes: %v", err)
+	}
+	wantBytes, err := want.Bytes()
+	if err != nil {
+		t.Fatalf("want.Bytes: %v", err)
+	}
+	if !bytes.Equal(gotBytes, wantBytes) {
+		t.Fatalf("\ngot  % x\nwant % x", gotBytes, wantBytes)
+	}
+
+	// The two arcs are encoded as a single repeated opcode.
+	gotDisasm, err := disassemble(gotBytes)
+	if err != nil {
+		t.Fatalf("disassemble: %v", err)
+	}
+	if !bytes.Contains(gotDisasm, []byte("A (absolute arcTo), 2 reps")) {
+		t.Errorf("disassembly does not contain two arcs:\n%s", gotDisasm)
+	}
+}
+
+func TestEncodeBackground(t *testing.T) {
+	bg := color.RGBA{0x20, 0x40, 0x60, 0x80}
+	e := Encoder{WriteVersion: true}
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette, Background: bg})
+	e.AppendRect(0, -16, -16, 16, 16)
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	m, err := DecodeMetadata(src)
+	if err != nil {
+		t.Fatalf("DecodeMetadata: %v", err)
+	}
+	if m.Background != bg {
+		t.Errorf("DecodeMetadata: got background %v, want %v", m.Background, bg)
+	}
+	gotDisasm, err := disassemble(src)
+	if err != nil {
+		t.Fatalf("disassemble: %v", err)
+	}
+	if !bytes.Contains(gotDisasm, []byte("(background)")) {
+		t.Errorf("disassembly does not contain the background:\n%s", gotDisasm)
+	}
+
+	// Re-encoding the decoded graphic should give the same bytes.
+	var got Encoder
+	got.WriteVersion = true
+	if err := Decode(&got, src, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if gotBytes, err := got.Bytes(); err != nil {
+		t.Fatalf("re-encoding: %v", err)
+	} else if !bytes.Equal(gotBytes, src) {
+		t.Errorf("re-encoding:\ngot  % x\nwant % x", gotBytes, src)
+	}
+
+	for _, tc := range []struct {
+		background   color.RGBA
+		writeVersion bool
+		want         error
+	}{
+		{bg, false, errVersionIndicatorRequired},
+		{color.RGBA{0xff, 0x00, 0x00, 0x80}, true, errInvalidBackground},
+		{color.RGBA{}, false, nil},
+	} {
+		e := Encoder{WriteVersion: tc.writeVersion}
+		e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette, Background: tc.background})
+		if _, err := e.Bytes(); err != tc.want {
+			t.Errorf("background %v: got %v, want %v", tc.background, err, tc.want)
+		}
+	}
+}
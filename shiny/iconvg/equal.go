@@ -0,0 +1,409 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+	"math"
+)
+
+// Equal reports whether the IconVG graphics a and b draw the same picture,
+// even if they are encoded differently. It returns an error if either cannot
+// be decoded.
+//
+// Both graphics are decoded into a canonical form: every path is a list of
+// absolute PathSegments, with horizontal, vertical and relative ops made
+// absolute, smooth curves given their implicit control points, quadratic
+// curves raised to cubic ones and arcs converted to cubic curves. Each
+// path's fill is resolved from the color registers to a flat color or a
+// gradient. Paths that are never drawn, because their fill is transparent or
+// invalid, are dropped.
+//
+// Colors, the background color and levels of detail must match exactly.
+// Coordinates, including those of the view box, may differ by up to 1/1024
+// of the larger dimension of a's view box, which allows for the different
+// precisions of the Encoder's HighResolutionCoordinates setting. Other
+// metadata, such as the title and the suggested palette itself, is ignored.
+func Equal(a, b []byte) (bool, error) {
+	var da, db semanticRecorder
+	if err := Decode(&da, a, nil); err != nil {
+		return false, err
+	}
+	if err := Decode(&db, b, nil); err != nil {
+		return false, err
+	}
+
+	vb := da.metadata.ViewBox
+	tol := float32(math.Max(
+		math.Abs(float64(vb.Max[0]-vb.Min[0])),
+		math.Abs(float64(vb.Max[1]-vb.Min[1])),
+	)) / 1024
+
+	if da.metadata.Background != db.metadata.Background ||
+		!nearlyEqual(vb.Min[0], db.metadata.ViewBox.Min[0], tol) ||
+		!nearlyEqual(vb.Min[1], db.metadata.ViewBox.Min[1], tol) ||
+		!nearlyEqual(vb.Max[0], db.metadata.ViewBox.Max[0], tol) ||
+		!nearlyEqual(vb.Max[1], db.metadata.ViewBox.Max[1], tol) ||
+		len(da.paths) != len(db.paths) {
+		return false, nil
+	}
+	for i := range da.paths {
+		if !da.paths[i].equal(&db.paths[i], tol) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func nearlyEqual(a, b, tol float32) bool {
+	return a == b || float32(math.Abs(float64(a-b))) <= tol
+}
+
+// semanticPath is a path, or a PopClip, in the canonical form used by Equal.
+type semanticPath struct {
+	// popClip is whether this is a PopClip rather than a path, in which case
+	// the other fields are unused.
+	popClip bool
+	// clip is whether this is a clip path, in which case fill is unused.
+	clip bool
+
+	lod0, lod1 float32
+	fill       semanticFill
+	segs       []PathSegment
+}
+
+func (p *semanticPath) equal(q *semanticPath, tol float32) bool {
+	if p.popClip != q.popClip || p.clip != q.clip {
+		return false
+	}
+	if p.popClip {
+		return true
+	}
+	if p.lod0 != q.lod0 || p.lod1 != q.lod1 || len(p.segs) != len(q.segs) {
+		return false
+	}
+	if !p.clip && !p.fill.equal(&q.fill) {
+		return false
+	}
+	for i := range p.segs {
+		s, t := &p.segs[i], &q.segs[i]
+		if s.Op != t.Op ||
+			!nearlyEqual(s.X1, t.X1, tol) || !nearlyEqual(s.Y1, t.Y1, tol) ||
+			!nearlyEqual(s.X2, t.X2, tol) || !nearlyEqual(s.Y2, t.Y2, tol) ||
+			!nearlyEqual(s.X, t.X, tol) || !nearlyEqual(s.Y, t.Y, tol) {
+			return false
+		}
+	}
+	return true
+}
+
+// semanticFill is a path's resolved fill: a flat color if gradient is false,
+// or else a gradient.
+type semanticFill struct {
+	color color.RGBA
+
+	gradient  bool
+	radial    bool
+	spread    GradientSpread
+	transform [6]float32
+	stops     []semanticStop
+}
+
+type semanticStop struct {
+	offset float32
+	color  color.RGBA
+}
+
+// gradientTolerance is the tolerance for comparing a gradient's transform
+// and stop offsets, relative to their magnitude.
+const gradientTolerance = 1e-4
+
+func (f *semanticFill) equal(g *semanticFill) bool {
+	if f.gradient != g.gradient {
+		return false
+	}
+	if !f.gradient {
+		return f.color == g.color
+	}
+	if f.radial != g.radial || f.spread != g.spread || len(f.stops) != len(g.stops) {
+		return false
+	}
+	for i := range f.transform {
+		a, b := f.transform[i], g.transform[i]
+		m := float32(math.Max(1, math.Max(math.Abs(float64(a)), math.Abs(float64(b)))))
+		if !nearlyEqual(a, b, gradientTolerance*m) {
+			return false
+		}
+	}
+	for i := range f.stops {
+		if f.stops[i].color != g.stops[i].color ||
+			!nearlyEqual(f.stops[i].offset, g.stops[i].offset, gradientTolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// semanticRecorder is a Destination that records a graphic in the canonical
+// form used by Equal and DecodeToSVG. It tracks the registers and the pen in the same way
+// as the Rasterizer, but in graphic coordinates.
+type semanticRecorder struct {
+	metadata Metadata
+	paths    []semanticPath
+
+	lod0     float32
+	lod1     float32
+	cSel     uint8
+	nSel     uint8
+	cReg     [64]color.RGBA
+	nReg     [64]float32
+	clipping bool
+
+	// path is the path being recorded, if any.
+	path *semanticPath
+	// disabled is whether the current path is never drawn.
+	disabled bool
+
+	penX, penY     float32
+	startX, startY float32
+
+	prevSmoothType   uint8
+	prevSmoothPointX float32
+	prevSmoothPointY float32
+}
+
+func (d *semanticRecorder) Reset(m Metadata) {
+	*d = semanticRecorder{
+		metadata: m,
+		lod1:     positiveInfinity,
+		cReg:     m.Palette,
+	}
+}
+
+func (d *semanticRecorder) SetCSel(cSel uint8) { d.cSel = cSel & 0x3f }
+func (d *semanticRecorder) SetNSel(nSel uint8) { d.nSel = nSel & 0x3f }
+
+func (d *semanticRecorder) SetCReg(adj uint8, incr bool, c Color) {
+	d.cReg[(d.cSel-adj)&0x3f] = c.Resolve(&d.metadata.Palette, &d.cReg)
+	if incr {
+		d.cSel++
+	}
+}
+
+func (d *semanticRecorder) SetNReg(adj uint8, incr bool, f float32) {
+	d.nReg[(d.nSel-adj)&0x3f] = f
+	if incr {
+		d.nSel++
+	}
+}
+
+func (d *semanticRecorder) SetLOD(lod0, lod1 float32) {
+	d.lod0, d.lod1 = lod0, lod1
+}
+
+func (d *semanticRecorder) PushClip() {
+	d.clipping = true
+}
+
+func (d *semanticRecorder) PopClip() {
+	d.paths = append(d.paths, semanticPath{popClip: true})
+}
+
+// resolveFill resolves the fill of a path that starts with the given
+// CREG[CSEL-adj], as the Rasterizer does, and returns false if the path is
+// never drawn.
+func (d *semanticRecorder) resolveFill(adj uint8) (f semanticFill, ok bool) {
+	c := d.cReg[(d.cSel-adj)&0x3f]
+	if validAlphaPremulColor(c) {
+		return semanticFill{color: c}, c.A != 0
+	}
+	if c.A != 0x00 || c.B&0x80 == 0 {
+		return semanticFill{}, false
+	}
+
+	nStops := int(c.R & 0x3f)
+	cBase := int(c.G & 0x3f)
+	nBase := int(c.B & 0x3f)
+	f = semanticFill{
+		gradient: true,
+		radial:   (c.B>>6)&0x01 != 0,
+		spread:   GradientSpread(c.G >> 6),
+		stops:    make([]semanticStop, nStops),
+	}
+	for i := range f.transform {
+		f.transform[i] = d.nReg[(nBase-6+i)&0x3f]
+	}
+	prevN := negativeInfinity
+	for i := range f.stops {
+		sc := d.cReg[(cBase+i)&0x3f]
+		if !validAlphaPremulColor(sc) {
+			return semanticFill{}, false
+		}
+		n := d.nReg[(nBase+i)&0x3f]
+		if !(0 <= n && n <= 1) || !(n > prevN) {
+			return semanticFill{}, false
+		}
+		prevN = n
+		f.stops[i] = semanticStop{offset: n, color: sc}
+	}
+	return f, true
+}
+
+func (d *semanticRecorder) StartPath(adj uint8, x, y float32) {
+	p := semanticPath{
+		clip: d.clipping,
+		lod0: d.lod0,
+		lod1: d.lod1,
+	}
+	d.disabled = false
+	if !p.clip {
+		var ok bool
+		p.fill, ok = d.resolveFill(adj)
+		d.disabled = !ok
+	}
+	d.path = &p
+	d.startX, d.startY = x, y
+	d.moveTo(x, y)
+}
+
+func (d *semanticRecorder) ClosePathEndPath() {
+	d.closePath()
+	if !d.disabled {
+		d.paths = append(d.paths, *d.path)
+	}
+	d.path = nil
+	d.clipping = false
+}
+
+func (d *semanticRecorder) ClosePathAbsMoveTo(x, y float32) {
+	d.closePath()
+	d.startX, d.startY = x, y
+	d.moveTo(x, y)
+}
+
+func (d *semanticRecorder) ClosePathRelMoveTo(x, y float32) {
+	d.ClosePathAbsMoveTo(d.penX+x, d.penY+y)
+}
+
+func (d *semanticRecorder) moveTo(x, y float32) {
+	d.prevSmoothType = smoothTypeNone
+	d.penX, d.penY = x, y
+	d.path.segs = append(d.path.segs, PathSegment{Op: SegmentMoveTo, X: x, Y: y})
+}
+
+// closePath closes the current sub-path, after dropping any final line
+// segment that ends at the sub-path's start, since closing the sub-path
+// draws that line anyway.
+func (d *semanticRecorder) closePath() {
+	if n := len(d.path.segs); n > 0 {
+		if s := d.path.segs[n-1]; s.Op == SegmentLineTo && s.X == d.startX && s.Y == d.startY {
+			d.path.segs = d.path.segs[:n-1]
+		}
+	}
+	d.path.segs = append(d.path.segs, PathSegment{Op: SegmentClose})
+	d.prevSmoothType = smoothTypeNone
+	d.penX, d.penY = d.startX, d.startY
+}
+
+func (d *semanticRecorder) lineTo(x, y float32) {
+	d.prevSmoothType = smoothTypeNone
+	if x == d.penX && y == d.penY {
+		// A zero-length line draws nothing.
+		return
+	}
+	d.penX, d.penY = x, y
+	d.path.segs = append(d.path.segs, PathSegment{Op: SegmentLineTo, X: x, Y: y})
+}
+
+// quadTo appends a quadratic Bézier curve, raised to a cubic one.
+func (d *semanticRecorder) quadTo(x1, y1, x, y float32) {
+	px, py := d.penX, d.penY
+	d.cubeTo(
+		px+2*(x1-px)/3, py+2*(y1-py)/3,
+		x+2*(x1-x)/3, y+2*(y1-y)/3,
+		x, y,
+	)
+	d.prevSmoothType = smoothTypeQuad
+	d.prevSmoothPointX, d.prevSmoothPointY = x1, y1
+}
+
+func (d *semanticRecorder) cubeTo(x1, y1, x2, y2, x, y float32) {
+	d.prevSmoothType = smoothTypeCube
+	d.prevSmoothPointX, d.prevSmoothPointY = x2, y2
+	d.penX, d.penY = x, y
+	d.path.segs = append(d.path.segs, PathSegment{
+		Op: SegmentCubeTo,
+		X1: x1, Y1: y1,
+		X2: x2, Y2: y2,
+		X: x, Y: y,
+	})
+}
+
+// implicitSmoothPoint is like the Rasterizer method of the same name.
+func (d *semanticRecorder) implicitSmoothPoint(thisSmoothType uint8) (zx, zy float32) {
+	if d.prevSmoothType != thisSmoothType {
+		return d.penX, d.penY
+	}
+	return 2*d.penX - d.prevSmoothPointX, 2*d.penY - d.prevSmoothPointY
+}
+
+func (d *semanticRecorder) AbsHLineTo(x float32) { d.lineTo(x, d.penY) }
+func (d *semanticRecorder) RelHLineTo(x float32) { d.lineTo(d.penX+x, d.penY) }
+func (d *semanticRecorder) AbsVLineTo(y float32) { d.lineTo(d.penX, y) }
+func (d *semanticRecorder) RelVLineTo(y float32) { d.lineTo(d.penX, d.penY+y) }
+
+func (d *semanticRecorder) AbsLineTo(x, y float32) { d.lineTo(x, y) }
+func (d *semanticRecorder) RelLineTo(x, y float32) { d.lineTo(d.penX+x, d.penY+y) }
+
+func (d *semanticRecorder) AbsSmoothQuadTo(x, y float32) {
+	x1, y1 := d.implicitSmoothPoint(smoothTypeQuad)
+	d.quadTo(x1, y1, x, y)
+}
+
+func (d *semanticRecorder) RelSmoothQuadTo(x, y float32) {
+	d.AbsSmoothQuadTo(d.penX+x, d.penY+y)
+}
+
+func (d *semanticRecorder) AbsQuadTo(x1, y1, x, y float32) {
+	d.quadTo(x1, y1, x, y)
+}
+
+func (d *semanticRecorder) RelQuadTo(x1, y1, x, y float32) {
+	d.quadTo(d.penX+x1, d.penY+y1, d.penX+x, d.penY+y)
+}
+
+func (d *semanticRecorder) AbsSmoothCubeTo(x2, y2, x, y float32) {
+	x1, y1 := d.implicitSmoothPoint(smoothTypeCube)
+	d.cubeTo(x1, y1, x2, y2, x, y)
+}
+
+func (d *semanticRecorder) RelSmoothCubeTo(x2, y2, x, y float32) {
+	d.AbsSmoothCubeTo(d.penX+x2, d.penY+y2, d.penX+x, d.penY+y)
+}
+
+func (d *semanticRecorder) AbsCubeTo(x1, y1, x2, y2, x, y float32) {
+	d.cubeTo(x1, y1, x2, y2, x, y)
+}
+
+func (d *semanticRecorder) RelCubeTo(x1, y1, x2, y2, x, y float32) {
+	d.cubeTo(d.penX+x1, d.penY+y1, d.penX+x2, d.penY+y2, d.penX+x, d.penY+y)
+}
+
+func (d *semanticRecorder) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	var buf [4][6]float32
+	segs := appendArcBeziers(buf[:0], d.penX, d.penY, rx, ry, xAxisRotation, largeArc, sweep, x, y)
+	if len(segs) == 0 {
+		d.lineTo(x, y)
+		return
+	}
+	for _, c := range segs {
+		d.cubeTo(c[0], c[1], c[2], c[3], c[4], c[5])
+	}
+	d.prevSmoothType = smoothTypeNone
+}
+
+func (d *semanticRecorder) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	d.AbsArcTo(rx, ry, xAxisRotation, largeArc, sweep, d.penX+x, d.penY+y)
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readIVG(t *testing.T, filename string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.FromSlash(filename) + ".ivg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEqual(t *testing.T) {
+	lores := readIVG(t, "testdata/action-info.lores")
+	hires := readIVG(t, "testdata/action-info.hires")
+	cowbell := readIVG(t, "testdata/cowbell")
+
+	// The same square, drawn with H and V ops, with absolute lines and an
+	// explicit line back to the start, and with relative lines.
+	var rect, abs, rel Encoder
+	rect.AppendRect(0, -8, -8, 8, 8)
+	abs.StartPath(0, -8, -8)
+	abs.AbsLineTo(+8, -8)
+	abs.AbsLineTo(+8, +8)
+	abs.AbsLineTo(-8, +8)
+	abs.AbsLineTo(-8, -8)
+	abs.ClosePathEndPath()
+	rel.StartPath(0, -8, -8)
+	rel.RelLineTo(16, 0)
+	rel.RelLineTo(0, 16)
+	rel.RelLineTo(-16, 0)
+	rel.ClosePathEndPath()
+
+	// The same square, filled with a different color.
+	var recolored Encoder
+	recolored.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x80, 0xff}))
+	recolored.AppendRect(0, -8, -8, 8, 8)
+
+	// The same square, moved by one unit.
+	var moved Encoder
+	moved.AppendRect(0, -7, -8, 9, 8)
+
+	// The same square, preceded by a transparent one that is never drawn.
+	var hidden Encoder
+	hidden.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x00, 0x00}))
+	hidden.AppendRect(0, -16, -16, 16, 16)
+	hidden.SetCReg(0, false, PaletteIndexColor(0))
+	hidden.AppendRect(0, -8, -8, 8, 8)
+
+	encoded := map[string][]byte{}
+	for name, e := range map[string]*Encoder{
+		"rect":      &rect,
+		"abs":       &abs,
+		"rel":       &rel,
+		"recolored": &recolored,
+		"moved":     &moved,
+		"hidden":    &hidden,
+	} {
+		b, err := e.Bytes()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		encoded[name] = b
+	}
+
+	canonical, err := Canonicalize(lores)
+	if err != nil {
+		t.Fatalf("Canonicalize: %v", err)
+	}
+
+	testCases := []struct {
+		desc string
+		a, b []byte
+		want bool
+	}{
+		{"lores vs lores", lores, lores, true},
+		{"lores vs hires", lores, hires, true},
+		{"hires vs lores", hires, lores, true},
+		{"lores vs canonical", lores, canonical, true},
+		{"lores vs cowbell", lores, cowbell, false},
+		{"rect vs abs", encoded["rect"], encoded["abs"], true},
+		{"rect vs rel", encoded["rect"], encoded["rel"], true},
+		{"rect vs hidden", encoded["rect"], encoded["hidden"], true},
+		{"rect vs recolored", encoded["rect"], encoded["recolored"], false},
+		{"rect vs moved", encoded["rect"], encoded["moved"], false},
+	}
+	for _, tc := range testCases {
+		got, err := Equal(tc.a, tc.b)
+		if err != nil {
+			t.Errorf("%s: %v", tc.desc, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %t, want %t", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestEqualTestdata(t *testing.T) {
+	for _, tc := range testdataTestCases {
+		ivgData := readIVG(t, tc.filename)
+		canonical, err := Canonicalize(ivgData)
+		if err != nil {
+			t.Errorf("%s: Canonicalize: %v", tc.filename, err)
+			continue
+		}
+		if got, err := Equal(ivgData, canonical); err != nil || !got {
+			t.Errorf("%s: got %t, %v, want true, <nil>", tc.filename, got, err)
+		}
+	}
+}
+
+func TestEqualInvalid(t *testing.T) {
+	valid := readIVG(t, "testdata/cowbell")
+	invalid := []byte("not an IconVG graphic")
+	if _, err := Equal(valid, invalid); err == nil {
+		t.Errorf("valid, invalid: got nil error, want non-nil")
+	}
+	if _, err := Equal(invalid, valid); err == nil {
+		t.Errorf("invalid, valid: got nil error, want non-nil")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+// A graphic can hold several independent glyphs, such as the icons of a
+// sprite sheet or an icon font. The optional glyph table metadata chunk,
+// which needs version 1 of the graphic format, lists the opcodes that make up
+// each glyph. After its metadata identifier, the chunk holds a natural
+// number N, followed by N pairs of natural numbers. Each pair is the byte
+// offset of a glyph's first opcode and the byte offset just past its last
+// opcode. Offsets are relative to the graphic's first opcode, the byte after
+// the metadata, so that they do not depend on the metadata's length.
+
+// glyphRange is a glyph's opcodes' byte offsets, relative to the first opcode.
+type glyphRange struct {
+	start, end uint32
+}
+
+// glyphSelection is the glyph table decoded from a graphic's metadata, and
+// the index of the glyph to decode, or -1 to decode the whole graphic.
+type glyphSelection struct {
+	ranges []glyphRange
+	index  int
+
+	// base is set by decode to the byte offset of the graphic's first
+	// opcode.
+	base int
+}
+
+// BeginGlyph starts a glyph, whose opcodes are those encoded before the
+// matching EndGlyph. It must not be called while drawing a path. Glyphs
+// cannot be nested.
+//
+// A glyph can be decoded on its own, by DecodeGlyph, which starts from the
+// initial CSEL, NSEL, LOD and register values. BeginGlyph resets the
+// selectors and LOD to their initial values, if they have changed, but a
+// glyph must not rely on register values set before the glyph began.
+//
+// The glyph table needs version 1 of the graphic format, so e.WriteVersion
+// must be set.
+func (e *Encoder) BeginGlyph() {
+	e.beginGlyph(true)
+}
+
+// beginGlyph is BeginGlyph, except that it resets the selectors and LOD only
+// if reset is set.
+func (e *Encoder) beginGlyph(reset bool) {
+	e.checkModeStyling()
+	if e.err != nil {
+		return
+	}
+	if e.inGlyph {
+		e.err = errGlyphNotEnded
+		return
+	}
+	e.setVersion(glyphsVersion)
+	if e.err != nil {
+		return
+	}
+	e.inGlyph = true
+	e.glyphs = append(e.glyphs, glyphRange{start: uint32(len(e.buf) - e.metadataLen)})
+
+	if !reset {
+		return
+	}
+	if e.cSel != 0 {
+		e.SetCSel(0)
+	}
+	if e.nSel != 0 {
+		e.SetNSel(0)
+	}
+	if e.lod0 != 0 || e.lod1 != positiveInfinity {
+		e.SetLOD(0, positiveInfinity)
+	}
+}
+
+// EndGlyph ends the glyph started by the matching BeginGlyph. It must not be
+// called while drawing a path.
+func (e *Encoder) EndGlyph() {
+	e.checkModeStyling()
+	if e.err != nil {
+		return
+	}
+	if !e.inGlyph {
+		e.err = errGlyphNotBegun
+		return
+	}
+	e.inGlyph = false
+	e.glyphs[len(e.glyphs)-1].end = uint32(len(e.buf) - e.metadataLen)
+}
+
+// appendGlyphChunk appends the glyph table metadata chunk, including its
+// length, to b.
+func (e *Encoder) appendGlyphChunk(b buffer) buffer {
+	var c buffer
+	c.encodeNatural(midGlyphs)
+	c.encodeNatural(uint32(len(e.glyphs)))
+	for _, g := range e.glyphs {
+		c.encodeNatural(g.start)
+		c.encodeNatural(g.end)
+	}
+	b.encodeNatural(uint32(len(c)))
+	return append(b, c...)
+}
+
+// appendWithGlyphTable appends e.buf, with an extra glyph table metadata
+// chunk after its other metadata chunks, to b.
+func (e *Encoder) appendWithGlyphTable(b buffer) buffer {
+	i := len(magic)
+	if e.WriteVersion {
+		i++
+	}
+	nMetadataChunks, n := e.buf[i:].decodeNatural()
+	b = append(b, e.buf[:i]...)
+	b.encodeNatural(nMetadataChunks + 1)
+	b = append(b, e.buf[i+n:e.metadataLen]...)
+	b = e.appendGlyphChunk(b)
+	return append(b, e.buf[e.metadataLen:]...)
+}
+
+// decodeGlyphTable decodes the body of a glyph table metadata chunk.
+func decodeGlyphTable(p printer, src buffer) (ranges []glyphRange, src1 buffer, err error) {
+	nGlyphs, n := src.decodeNatural()
+	if n == 0 {
+		return nil, nil, errInvalidGlyphTable
+	}
+	if p != nil {
+		p(src[:n], "    Number of glyphs: %d\n", nGlyphs)
+	}
+	src = src[n:]
+	for i := uint32(0); i < nGlyphs; i++ {
+		start, n := src.decodeNatural()
+		if n == 0 {
+			return nil, nil, errInvalidGlyphTable
+		}
+		if p != nil {
+			p(src[:n], "    Glyph %d start: %d\n", i, start)
+		}
+		src = src[n:]
+		end, n := src.decodeNatural()
+		if n == 0 || end < start {
+			return nil, nil, errInvalidGlyphTable
+		}
+		if p != nil {
+			p(src[:n], "    Glyph %d end: %d\n", i, end)
+		}
+		src = src[n:]
+		ranges = append(ranges, glyphRange{start, end})
+	}
+	return ranges, src, nil
+}
+
+// NumGlyphs returns the number of glyphs in an IconVG graphic's glyph table.
+// It returns zero if the graphic has no glyph table.
+func NumGlyphs(src []byte) (int, error) {
+	m := Metadata{
+		ViewBox: DefaultViewBox,
+		Palette: DefaultPalette,
+	}
+	g := glyphSelection{index: -1}
+	if err := decode(nil, nil, &m, &g, true, src, nil); err != nil {
+		return 0, err
+	}
+	return len(g.ranges), nil
+}
+
+// DecodeGlyph decodes the glyph with the given index, counting from zero, in
+// an IconVG graphic. Like Decode, it first calls dst.Reset with the graphic's
+// metadata, but then only decodes that glyph's opcodes.
+//
+// It returns an error if the graphic has no glyph table, or if index is out
+// of range.
+func DecodeGlyph(src []byte, index int, dst Destination) error {
+	if index < 0 {
+		return errInvalidGlyphIndex
+	}
+	m := Metadata{
+		ViewBox: DefaultViewBox,
+		Palette: DefaultPalette,
+	}
+	if t, ok := dst.(Tracer); ok {
+		dst = &tracer{Destination: dst, t: t}
+	}
+	return decode(dst, nil, &m, &glyphSelection{index: index}, false, src, nil)
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+)
+
+func encodeGlyph(e *Encoder, i int) {
+	switch i {
+	case 0:
+		e.AppendRect(0, -24, -24, -8, -8)
+	case 1:
+		e.SetCSel(1)
+		e.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x80, 0x00, 0xff}))
+		e.AppendCircle(0, 8, 8, 12)
+	case 2:
+		e.SetLOD(0, 64)
+		e.AppendRect(0, 8, -24, 24, -8)
+	}
+}
+
+func TestGlyphs(t *testing.T) {
+	var e Encoder
+	e.WriteVersion = true
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+	for i := 0; i < 3; i++ {
+		e.BeginGlyph()
+		encodeGlyph(&e, i)
+		e.EndGlyph()
+	}
+	if got, want := e.Len(), len(mustBytes(t, &e)); got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+	src := mustBytes(t, &e)
+
+	if n, err := NumGlyphs(src); err != nil {
+		t.Fatalf("NumGlyphs: %v", err)
+	} else if n != 3 {
+		t.Fatalf("NumGlyphs: got %d, want 3", n)
+	}
+	if _, err := DecodeMetadata(src); err != nil {
+		t.Fatalf("DecodeMetadata: %v", err)
+	}
+	if err := Validate(src); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if _, err := disassemble(src); err != nil {
+		t.Fatalf("disassemble: %v", err)
+	}
+
+	// Decoding the middle glyph on its own gives the same opcodes as encoding
+	// it on its own, after the second glyph's SetCSel(1) is reset.
+	var want Encoder
+	want.WriteVersion = true
+	want.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+	encodeGlyph(&want, 1)
+	var got Encoder
+	got.WriteVersion = true
+	if err := DecodeGlyph(src, 1, &got); err != nil {
+		t.Fatalf("DecodeGlyph: %v", err)
+	}
+	if g, w := mustBytes(t, &got), mustBytes(t, &want); !bytes.Equal(g, w) {
+		t.Errorf("DecodeGlyph(1):\ngot  % x\nwant % x", g, w)
+	}
+
+	// The third glyph starts by resetting the CSEL set by the second glyph, so
+	// that decoding the whole graphic draws it with the same CSEL as decoding
+	// it on its own.
+	var r traceRecorder
+	r.WriteVersion = true
+	if err := Decode(&r, src, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if len(r.events) != 6 {
+		t.Fatalf("Decode: got %d trace events, want 6", len(r.events))
+	}
+	if got := r.events[4].CSel; got != 0 {
+		t.Errorf("third glyph: got CSEL %d, want 0", got)
+	}
+
+	for _, index := range []int{-1, 3} {
+		if err := DecodeGlyph(src, index, &Encoder{}); err != errInvalidGlyphIndex {
+			t.Errorf("DecodeGlyph(%d): got %v, want %v", index, err, errInvalidGlyphIndex)
+		}
+	}
+}
+
+func TestGlyphErrors(t *testing.T) {
+	var e Encoder
+	e.BeginGlyph()
+	if _, err := e.Bytes(); err != errVersionIndicatorRequired {
+		t.Errorf("without WriteVersion: got %v, want %v", err, errVersionIndicatorRequired)
+	}
+
+	e = Encoder{WriteVersion: true}
+	e.BeginGlyph()
+	e.AppendRect(0, 0, 0, 8, 8)
+	if _, err := e.Bytes(); err != errGlyphNotEnded {
+		t.Errorf("unended glyph: got %v, want %v", err, errGlyphNotEnded)
+	}
+	e.BeginGlyph()
+	if _, err := e.Bytes(); err != errGlyphNotEnded {
+		t.Errorf("nested glyph: got %v, want %v", err, errGlyphNotEnded)
+	}
+
+	e = Encoder{WriteVersion: true}
+	e.EndGlyph()
+	if _, err := e.Bytes(); err != errGlyphNotBegun {
+		t.Errorf("EndGlyph without BeginGlyph: got %v, want %v", err, errGlyphNotBegun)
+	}
+
+	// A graphic without a glyph table has no glyphs.
+	e = Encoder{}
+	e.AppendRect(0, 0, 0, 8, 8)
+	src := mustBytes(t, &e)
+	if n, err := NumGlyphs(src); n != 0 || err != nil {
+		t.Errorf("NumGlyphs: got %d, %v, want 0, nil", n, err)
+	}
+	if err := DecodeGlyph(src, 0, &Encoder{}); err != errInvalidGlyphIndex {
+		t.Errorf("DecodeGlyph: got %v, want %v", err, errInvalidGlyphIndex)
+	}
+}
+
+func mustBytes(t *testing.T, e *Encoder) []byte {
+	t.Helper()
+	b, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// goldenSize is the width and height, in pixels, at which TestGolden
+// rasterizes each testdata graphic. Graphics that aren't square are fit
+// within a goldenSize by goldenSize square.
+const goldenSize = 48
+
+// goldenTolerance is the largest per-channel difference, out of 0xffff, that
+// checkGolden allows between a rendering and its golden image. Renderings
+// aren't pixel-exact across rasterizer implementations, so some slack is
+// needed, but much less than checkApproxEqual's.
+const goldenTolerance = 0xffff * 4 / 100
+
+// rasterizeAt decodes the IconVG graphic src and rasterizes it, fit within a
+// length by length square.
+func rasterizeAt(src []byte, length int) (*image.RGBA, error) {
+	md, err := DecodeMetadata(src)
+	if err != nil {
+		return nil, err
+	}
+	width, height := length, length
+	if dx, dy := md.ViewBox.AspectRatio(); dx < dy {
+		width = int(float32(length) * dx / dy)
+	} else {
+		height = int(float32(length) * dy / dx)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	var z Rasterizer
+	z.SetDstImage(dst, dst.Bounds(), draw.Src)
+	if err := Decode(&z, src, nil); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// goldenDiff returns an image that highlights, in red, the pixels of got that
+// differ from want by more than goldenTolerance, on top of a faded copy of
+// want. It also returns the number of such pixels. got and want must have the
+// same bounds.
+func goldenDiff(got, want image.Image) (*image.RGBA, int) {
+	diff := func(a, b uint32) uint32 {
+		if a < b {
+			return b - a
+		}
+		return a - b
+	}
+
+	b := want.Bounds()
+	dst := image.NewRGBA(b)
+	n := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			r0, g0, b0, a0 := got.At(x, y).RGBA()
+			r1, g1, b1, a1 := want.At(x, y).RGBA()
+			if diff(r0, r1) > goldenTolerance || diff(g0, g1) > goldenTolerance ||
+				diff(b0, b1) > goldenTolerance || diff(a0, a1) > goldenTolerance {
+				dst.SetRGBA(x, y, color.RGBA{0xff, 0x00, 0x00, 0xff})
+				n++
+				continue
+			}
+			gray := color.GrayModel.Convert(want.At(x, y)).(color.Gray)
+			v := 0xc0 + gray.Y/4
+			dst.SetRGBA(x, y, color.RGBA{v, v, v, 0xff})
+		}
+	}
+	return dst, n
+}
+
+// checkGolden compares got to the golden PNG image goldenFilename. If they
+// differ by more than goldenTolerance, got and a diff image are written to a
+// temporary directory, whose path is included in the returned error.
+//
+// If the -update flag is set, got is written to goldenFilename instead.
+func checkGolden(got image.Image, goldenFilename string) error {
+	if *updateFlag {
+		return encodePNG(goldenFilename, got)
+	}
+	want, err := decodePNG(goldenFilename)
+	if err != nil {
+		return err
+	}
+	if gb, wb := got.Bounds(), want.Bounds(); gb != wb {
+		return fmt.Errorf("bounds differ: got %v, want %v", gb, wb)
+	}
+	d, n := goldenDiff(got, want)
+	if n == 0 {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "iconvg-golden-")
+	if err != nil {
+		return fmt.Errorf("%d pixels differ (could not write diff image: %v)", n, err)
+	}
+	base := filepath.Base(goldenFilename)
+	gotFilename := filepath.Join(dir, "got."+base)
+	diffFilename := filepath.Join(dir, "diff."+base)
+	if err := encodePNG(gotFilename, got); err != nil {
+		return fmt.Errorf("%d pixels differ (could not write got image: %v)", n, err)
+	}
+	if err := encodePNG(diffFilename, d); err != nil {
+		return fmt.Errorf("%d pixels differ (could not write diff image: %v)", n, err)
+	}
+	return fmt.Errorf("%d pixels differ; see %s and %s", n, gotFilename, diffFilename)
+}
+
+func TestGolden(t *testing.T) {
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		got, err := rasterizeAt(ivgData, goldenSize)
+		if err != nil {
+			t.Errorf("%s: rasterize: %v", tc.filename, err)
+			continue
+		}
+		goldenFilename := fmt.Sprintf("%s.%d.png", filepath.FromSlash(tc.filename), goldenSize)
+		if err := checkGolden(got, goldenFilename); err != nil {
+			t.Errorf("%s: %v", tc.filename, err)
+		}
+	}
+}
+
+func TestGoldenDiff(t *testing.T) {
+	want := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	draw.Draw(want, want.Bounds(), image.White, image.Point{}, draw.Src)
+	got := image.NewRGBA(want.Bounds())
+	draw.Draw(got, got.Bounds(), want, image.Point{}, draw.Src)
+
+	// A small difference is within tolerance.
+	got.SetRGBA(1, 1, color.RGBA{0xfc, 0xfc, 0xfc, 0xff})
+	// A large one is not.
+	got.SetRGBA(2, 3, color.RGBA{0x80, 0xff, 0xff, 0xff})
+
+	d, n := goldenDiff(got, want)
+	if n != 1 {
+		t.Fatalf("got %d differing pixels, want 1", n)
+	}
+	if c := d.RGBAAt(2, 3); c != (color.RGBA{0xff, 0x00, 0x00, 0xff}) {
+		t.Errorf("diff at (2, 3): got %v, want red", c)
+	}
+	if c := d.RGBAAt(1, 1); c.R != c.G {
+		t.Errorf("diff at (1, 1): got %v, want gray", c)
+	}
+}
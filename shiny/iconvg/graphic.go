@@ -0,0 +1,79 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+)
+
+var errZeroGraphic = errors.New("iconvg: zero Graphic")
+
+// Graphic is an IconVG graphic: its decoded metadata together with its
+// encoded form, which holds the drawing instructions.
+//
+// Graphic implements encoding.BinaryMarshaler and encoding.BinaryUnmarshaler,
+// with the IconVG encoded form as the binary form, so that it can be stored
+// by packages such as encoding/gob. The zero Graphic holds no graphic, and
+// its methods other than UnmarshalBinary return an error.
+type Graphic struct {
+	metadata Metadata
+	src      []byte
+}
+
+// NewGraphic returns the Graphic encoded in src. It retains a copy of src.
+//
+// It returns an error if src is not a graphic that Decode accepts.
+func NewGraphic(src []byte) (*Graphic, error) {
+	g := new(Graphic)
+	if err := g.UnmarshalBinary(src); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// Metadata returns the graphic's metadata, as returned by DecodeMetadata.
+func (g *Graphic) Metadata() Metadata {
+	return g.metadata
+}
+
+// MarshalBinary returns the graphic's IconVG encoded form.
+func (g *Graphic) MarshalBinary() ([]byte, error) {
+	if g.src == nil {
+		return nil, errZeroGraphic
+	}
+	return append([]byte(nil), g.src...), nil
+}
+
+// UnmarshalBinary sets g to the graphic encoded in data. It retains a copy
+// of data.
+//
+// It returns an error, and leaves g unchanged, if data is not a graphic that
+// Decode accepts.
+func (g *Graphic) UnmarshalBinary(data []byte) error {
+	m, err := DecodeMetadata(data)
+	if err != nil {
+		return err
+	}
+	if err := Decode(nil, data, nil); err != nil {
+		return err
+	}
+	g.metadata = m
+	g.src = append([]byte(nil), data...)
+	return nil
+}
+
+// RasterizeTo draws the graphic onto dst, over what is already there, scaled
+// to fit the rectangle r. It is equivalent to decoding the graphic to a
+// Rasterizer whose destination was set by SetDstImage(dst, r, draw.Over).
+func (g *Graphic) RasterizeTo(dst draw.Image, r image.Rectangle, opts *DecodeOptions) error {
+	if g.src == nil {
+		return errZeroGraphic
+	}
+	var z Rasterizer
+	z.SetDstImage(dst, r, draw.Over)
+	return Decode(&z, g.src, opts)
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"bytes"
+	"encoding/gob"
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGraphicRoundTrip(t *testing.T) {
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		g, err := NewGraphic(ivgData)
+		if err != nil {
+			t.Errorf("%s: NewGraphic: %v", tc.filename, err)
+			continue
+		}
+		wantMetadata, err := DecodeMetadata(ivgData)
+		if err != nil {
+			t.Errorf("%s: DecodeMetadata: %v", tc.filename, err)
+			continue
+		}
+		if got := g.Metadata(); !reflect.DeepEqual(got, wantMetadata) {
+			t.Errorf("%s: Metadata:\ngot  %v\nwant %v", tc.filename, got, wantMetadata)
+		}
+
+		b, err := g.MarshalBinary()
+		if err != nil {
+			t.Errorf("%s: MarshalBinary: %v", tc.filename, err)
+			continue
+		}
+		if !bytes.Equal(b, ivgData) {
+			t.Errorf("%s: MarshalBinary: got % x, want % x", tc.filename, b, ivgData)
+		}
+		var h Graphic
+		if err := h.UnmarshalBinary(b); err != nil {
+			t.Errorf("%s: UnmarshalBinary: %v", tc.filename, err)
+			continue
+		}
+		if !reflect.DeepEqual(&h, g) {
+			t.Errorf("%s: UnmarshalBinary: Graphics differ", tc.filename)
+		}
+
+		// Round-trip through encoding/gob, which uses the BinaryMarshaler
+		// and BinaryUnmarshaler interfaces.
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(g); err != nil {
+			t.Errorf("%s: gob Encode: %v", tc.filename, err)
+			continue
+		}
+		var gobbed Graphic
+		if err := gob.NewDecoder(&buf).Decode(&gobbed); err != nil {
+			t.Errorf("%s: gob Decode: %v", tc.filename, err)
+			continue
+		}
+		if !reflect.DeepEqual(&gobbed, g) {
+			t.Errorf("%s: gob: Graphics differ", tc.filename)
+		}
+
+		// RasterizeTo should match decoding to a Rasterizer.
+		want := image.NewRGBA(image.Rect(0, 0, 64, 64))
+		var z Rasterizer
+		z.SetDstImage(want, want.Bounds(), draw.Over)
+		if err := Decode(&z, ivgData, nil); err != nil {
+			t.Errorf("%s: Decode: %v", tc.filename, err)
+			continue
+		}
+		got := image.NewRGBA(image.Rect(0, 0, 64, 64))
+		if err := gobbed.RasterizeTo(got, got.Bounds(), nil); err != nil {
+			t.Errorf("%s: RasterizeTo: %v", tc.filename, err)
+			continue
+		}
+		if !bytes.Equal(got.Pix, want.Pix) {
+			t.Errorf("%s: RasterizeTo: pixels differ", tc.filename)
+		}
+	}
+}
+
+func TestGraphicInvalid(t *testing.T) {
+	var g Graphic
+	if _, err := g.MarshalBinary(); err != errZeroGraphic {
+		t.Errorf("zero Graphic: MarshalBinary: got %v, want %v", err, errZeroGraphic)
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	if err := g.RasterizeTo(dst, dst.Bounds(), nil); err != errZeroGraphic {
+		t.Errorf("zero Graphic: RasterizeTo: got %v, want %v", err, errZeroGraphic)
+	}
+
+	if _, err := NewGraphic([]byte("not an IconVG graphic")); err == nil {
+		t.Errorf("NewGraphic: got nil error, want non-nil")
+	}
+
+	// This graphic has valid metadata but ends with a reserved opcode.
+	ivgData, err := os.ReadFile(filepath.FromSlash("testdata/cowbell.ivg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ivgData = append(ivgData, 0xff)
+	if err := g.UnmarshalBinary(ivgData); err == nil {
+		t.Errorf("UnmarshalBinary: reserved opcode: got nil error, want non-nil")
+	}
+	if g.src != nil {
+		t.Errorf("UnmarshalBinary: reserved opcode: Graphic was modified")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"image/color"
 	"math"
 
+	"golang.org/x/exp/shiny/iconvg/internal/gradient"
 	"golang.org/x/image/math/f32"
 )
 
@@ -15,6 +16,43 @@ const magic = "\x89IVG"
 
 var magicBytes = []byte(magic)
 
+// version is the latest version of the graphic format that this package
+// implements. A graphic's version is given by an optional version indicator,
+// a byte after the magic identifier that has its versionIndicator bit set and
+// the version in its low 7 bits. A graphic without a version indicator is of
+// version 0. Decoders accept graphics of any version up to and including
+// version.
+//
+// Version 1 adds the clip styling opcodes, which are reserved in version 0:
+//   - 0xc8 (push clip) saves the clip region on a stack. The next path is a
+//     clip path: instead of being filled, it intersects the clip region with
+//     the path's interior. The clip path's color register is ignored.
+//   - 0xc9 (pop clip) restores the clip region saved by the matching push
+//     clip.
+//
+// Paths are only drawn inside the clip region, which is initially unbounded.
+//
+// Version 1 also adds the title, glyph table, palette variants and background
+// metadata chunks.
+//
+// Without a version indicator, the magic identifier is followed by the
+// number of metadata chunks. That number is at most 6 in a valid graphic, so
+// its first byte never has the versionIndicator bit set.
+const (
+	version          = 1
+	versionIndicator = 0x80
+
+	// clipVersion, titleVersion, glyphsVersion, palettesVersion and
+	// backgroundVersion are the first versions with the clip opcodes, the
+	// title metadata chunk, the glyph table metadata chunk, the palette
+	// variants metadata chunk and the background metadata chunk.
+	clipVersion       = 1
+	titleVersion      = 1
+	glyphsVersion     = 1
+	palettesVersion   = 1
+	backgroundVersion = 1
+)
+
 var (
 	negativeInfinity = math.Float32frombits(0xff800000)
 	positiveInfinity = math.Float32frombits(0x7f800000)
@@ -28,6 +66,10 @@ const (
 	// File Format Version 0.
 	midViewBox          = 0
 	midSuggestedPalette = 1
+	midTitle            = 2
+	midGlyphs           = 3
+	midPalettes         = 4
+	midBackground       = 5
 
 	// File Format Version 1.
 	ffv1MIDViewBox          = 8
@@ -57,6 +99,21 @@ const (
 	GradientSpreadRepeat  GradientSpread = 3
 )
 
+// ApplySpread maps a gradient offset t to the range [0, 1] according to
+// spread. Offsets inside that range are unchanged. Outside of it, pad
+// clamps t to 0 or 1, reflect maps t back and forth, and repeat wraps t
+// around. For GradientSpreadNone, an offset outside [0, 1] means that the
+// gradient is transparent there, which ApplySpread reports as transparent.
+//
+// It is for custom Destinations that implement gradients themselves.
+func ApplySpread(spread GradientSpread, t float32) (clampedT float32, transparent bool) {
+	x := gradient.Spread(spread).Clamp(float64(t))
+	if x < 0 {
+		return 0, true
+	}
+	return float32(x), false
+}
+
 // GradientStop is a color/offset gradient stop.
 type GradientStop struct {
 	Offset float32
@@ -77,6 +134,15 @@ func (r *Rectangle) AspectRatio() (dx, dy float32) {
 // Palette is an IconVG palette.
 type Palette [64]color.RGBA
 
+// NamedPalette is a variant of a graphic's suggested palette, such as a
+// "light" or "dark" theme.
+type NamedPalette struct {
+	// Name identifies the variant. It must be valid UTF-8 of at most 255
+	// bytes, and unique within a graphic's variants.
+	Name    string
+	Palette Palette
+}
+
 // Metadata is an IconVG's metadata.
 type Metadata struct {
 	ViewBox Rectangle
@@ -84,10 +150,71 @@ type Metadata struct {
 	// Palette is a 64 color palette. When encoding, it is the suggested
 	// palette to place within the IconVG graphic. When decoding, it is either
 	// the optional palette passed to Decode, or if no optional palette was
-	// given, the suggested palette within the IconVG graphic.
+	// given, the suggested palette within the IconVG graphic. The Metadata
+	// passed to a Destination's Reset instead has the palette variant chosen
+	// by DecodeOptions.PaletteVariant, if any.
 	Palette Palette
+
+	// PaletteFormat is the format in which the suggested palette is
+	// encoded. It is ignored when decoding.
+	PaletteFormat PaletteFormat
+
+	// Title is an optional description of the graphic, such as alt text for
+	// accessibility, like SVG's <title> element. It must be valid UTF-8 of
+	// at most 1024 bytes. Encoding a non-empty Title requires the Encoder's
+	// WriteVersion.
+	Title string
+
+	// Palettes are optional variants of the suggested palette, so that one
+	// graphic can carry, for example, both light and dark themes. Encoding
+	// a non-empty Palettes requires the Encoder's WriteVersion. Each
+	// variant is encoded in PaletteFormat.
+	//
+	// When decoding, Palettes holds the graphic's variants. Palette stays
+	// the suggested palette, unless DecodeOptions.PaletteVariant chooses a
+	// variant for the Destination to draw with.
+	Palettes []NamedPalette
+
+	// Background is an optional alpha-premultiplied color that fills the
+	// graphic's view box before any paths are drawn. The zero value,
+	// transparent black, means no background. Encoding any other Background
+	// requires the Encoder's WriteVersion.
+	//
+	// Decoders that do not know about the background still draw the paths
+	// correctly, on top of whatever the destination already holds.
+	Background color.RGBA
 }
 
+// maxPaletteNameLen is the maximum length, in bytes, of a
+// NamedPalette.Name.
+const maxPaletteNameLen = 255
+
+// maxTitleLen is the maximum length, in bytes, of a Metadata.Title.
+const maxTitleLen = 1024
+
+// PaletteFormat is the number of bytes per color in an encoded suggested
+// palette.
+//
+// The narrower formats can only represent some colors. Encoding a palette in
+// a format that cannot represent all of its colors exactly is an error.
+type PaletteFormat uint8
+
+const (
+	// PaletteFormatAuto picks the narrowest format that represents the
+	// palette exactly.
+	PaletteFormatAuto PaletteFormat = iota
+	// PaletteFormatOneByte represents opaque colors whose channels are
+	// each 0x00, 0x40, 0x80, 0xc0 or 0xff, and a few semi-transparent ones.
+	PaletteFormatOneByte
+	// PaletteFormatTwoByte represents colors whose channels, including
+	// alpha, are each a multiple of 0x11.
+	PaletteFormatTwoByte
+	// PaletteFormatThreeByte represents opaque colors.
+	PaletteFormatThreeByte
+	// PaletteFormatFourByte represents all colors.
+	PaletteFormatFourByte
+)
+
 // DefaultViewBox is the default ViewBox. Its values should not be modified.
 var DefaultViewBox = Rectangle{
 	Min: f32.Vec2{-32, -32},
@@ -0,0 +1,48 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestApplySpread(t *testing.T) {
+	testCases := []struct {
+		spread          GradientSpread
+		t               float32
+		want            float32
+		wantTransparent bool
+	}{
+		{GradientSpreadNone, -0.3, 0, true},
+		{GradientSpreadNone, 0.0, 0.0, false},
+		{GradientSpreadNone, 0.5, 0.5, false},
+		{GradientSpreadNone, 1.0, 1.0, false},
+		{GradientSpreadNone, 1.7, 0, true},
+
+		{GradientSpreadPad, -0.3, 0.0, false},
+		{GradientSpreadPad, 0.5, 0.5, false},
+		{GradientSpreadPad, 1.7, 1.0, false},
+
+		{GradientSpreadReflect, -0.3, 0.3, false},
+		{GradientSpreadReflect, -1.3, 0.7, false},
+		{GradientSpreadReflect, 0.5, 0.5, false},
+		{GradientSpreadReflect, 1.7, 0.3, false},
+		{GradientSpreadReflect, 2.7, 0.7, false},
+
+		{GradientSpreadRepeat, -0.3, 0.7, false},
+		{GradientSpreadRepeat, 0.5, 0.5, false},
+		{GradientSpreadRepeat, 1.7, 0.7, false},
+		{GradientSpreadRepeat, 2.7, 0.7, false},
+	}
+
+	for _, tc := range testCases {
+		got, gotTransparent := ApplySpread(tc.spread, tc.t)
+		if gotTransparent != tc.wantTransparent || math.Abs(float64(got-tc.want)) > 1e-6 {
+			t.Errorf("ApplySpread(%s, %v): got %v, %t, want %v, %t",
+				gradientSpreadNames[tc.spread], tc.t, got, gotTransparent, tc.want, tc.wantTransparent)
+		}
+	}
+}
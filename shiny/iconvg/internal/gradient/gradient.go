@@ -204,6 +204,12 @@ func (g *Gradient) Bounds() image.Rectangle {
 
 // At satisfies the image.Image interface.
 func (g *Gradient) At(x, y int) color.Color {
+	return g.RGBA64At(x, y)
+}
+
+// RGBA64At satisfies the image.RGBA64Image interface. Unlike At, it does not
+// allocate, so image/draw uses it when g is the source image.
+func (g *Gradient) RGBA64At(x, y int) color.RGBA64 {
 	if len(g.Ranges) == 0 {
 		return color.RGBA64{}
 	}
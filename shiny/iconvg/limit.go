@@ -0,0 +1,181 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"errors"
+)
+
+var (
+	errTooManyPaths  = errors.New("iconvg: too many paths")
+	errTooManyPoints = errors.New("iconvg: too many points")
+)
+
+// limiter is a Destination that forwards to another Destination until the
+// number of paths or points exceeds DecodeOptions.MaxPaths or MaxPoints.
+//
+// A point is a coordinate pair given to a drawing op: the start of a path or
+// subpath, and each end point and control point. An arc counts as one point.
+type limiter struct {
+	Destination
+
+	err       error
+	maxPaths  int
+	maxPoints int
+	nPaths    int
+	nPoints   int
+}
+
+func (l *limiter) startOpcode(offset int) {
+	if o, ok := l.Destination.(opcodeObserver); ok {
+		o.startOpcode(offset)
+	}
+}
+
+func (l *limiter) endOpcode() error {
+	if l.err != nil {
+		return l.err
+	}
+	if o, ok := l.Destination.(opcodeObserver); ok {
+		return o.endOpcode()
+	}
+	return nil
+}
+
+func (l *limiter) addPath() bool {
+	l.nPaths++
+	if l.maxPaths > 0 && l.nPaths > l.maxPaths {
+		l.err = errTooManyPaths
+	}
+	return l.addPoints(1)
+}
+
+func (l *limiter) addPoints(n int) bool {
+	l.nPoints += n
+	if l.maxPoints > 0 && l.nPoints > l.maxPoints && l.err == nil {
+		l.err = errTooManyPoints
+	}
+	return l.err == nil
+}
+
+func (l *limiter) StartPath(adj uint8, x, y float32) {
+	if l.addPath() {
+		l.Destination.StartPath(adj, x, y)
+	}
+}
+
+func (l *limiter) ClosePathEndPath() {
+	if l.err == nil {
+		l.Destination.ClosePathEndPath()
+	}
+}
+
+func (l *limiter) ClosePathAbsMoveTo(x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.ClosePathAbsMoveTo(x, y)
+	}
+}
+
+func (l *limiter) ClosePathRelMoveTo(x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.ClosePathRelMoveTo(x, y)
+	}
+}
+
+func (l *limiter) AbsHLineTo(x float32) {
+	if l.addPoints(1) {
+		l.Destination.AbsHLineTo(x)
+	}
+}
+
+func (l *limiter) RelHLineTo(x float32) {
+	if l.addPoints(1) {
+		l.Destination.RelHLineTo(x)
+	}
+}
+
+func (l *limiter) AbsVLineTo(y float32) {
+	if l.addPoints(1) {
+		l.Destination.AbsVLineTo(y)
+	}
+}
+
+func (l *limiter) RelVLineTo(y float32) {
+	if l.addPoints(1) {
+		l.Destination.RelVLineTo(y)
+	}
+}
+
+func (l *limiter) AbsLineTo(x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.AbsLineTo(x, y)
+	}
+}
+
+func (l *limiter) RelLineTo(x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.RelLineTo(x, y)
+	}
+}
+
+func (l *limiter) AbsSmoothQuadTo(x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.AbsSmoothQuadTo(x, y)
+	}
+}
+
+func (l *limiter) RelSmoothQuadTo(x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.RelSmoothQuadTo(x, y)
+	}
+}
+
+func (l *limiter) AbsQuadTo(x1, y1, x, y float32) {
+	if l.addPoints(2) {
+		l.Destination.AbsQuadTo(x1, y1, x, y)
+	}
+}
+
+func (l *limiter) RelQuadTo(x1, y1, x, y float32) {
+	if l.addPoints(2) {
+		l.Destination.RelQuadTo(x1, y1, x, y)
+	}
+}
+
+func (l *limiter) AbsSmoothCubeTo(x2, y2, x, y float32) {
+	if l.addPoints(2) {
+		l.Destination.AbsSmoothCubeTo(x2, y2, x, y)
+	}
+}
+
+func (l *limiter) RelSmoothCubeTo(x2, y2, x, y float32) {
+	if l.addPoints(2) {
+		l.Destination.RelSmoothCubeTo(x2, y2, x, y)
+	}
+}
+
+func (l *limiter) AbsCubeTo(x1, y1, x2, y2, x, y float32) {
+	if l.addPoints(3) {
+		l.Destination.AbsCubeTo(x1, y1, x2, y2, x, y)
+	}
+}
+
+func (l *limiter) RelCubeTo(x1, y1, x2, y2, x, y float32) {
+	if l.addPoints(3) {
+		l.Destination.RelCubeTo(x1, y1, x2, y2, x, y)
+	}
+}
+
+func (l *limiter) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.AbsArcTo(rx, ry, xAxisRotation, largeArc, sweep, x, y)
+	}
+}
+
+func (l *limiter) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	if l.addPoints(1) {
+		l.Destination.RelArcTo(rx, ry, xAxisRotation, largeArc, sweep, x, y)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
-	"math"
 
 	"golang.org/x/exp/shiny/iconvg/internal/gradient"
 	"golang.org/x/image/math/f64"
@@ -21,6 +20,22 @@ const (
 	smoothTypeCube
 )
 
+// Fit is how a graphic's viewBox is fitted to a destination rectangle.
+type Fit uint8
+
+const (
+	// FitStretch scales the viewBox to fill the rectangle. The scaling
+	// factors may differ in the X and Y dimensions.
+	FitStretch Fit = 0
+	// FitContain scales the viewBox uniformly to fit inside the rectangle,
+	// and centers it.
+	FitContain Fit = 1
+	// FitCover scales the viewBox uniformly to cover the rectangle, and
+	// centers it. The parts of the graphic outside the rectangle are not
+	// drawn.
+	FitCover Fit = 2
+)
+
 // Rasterizer is a Destination that draws an IconVG graphic onto a raster
 // image.
 //
@@ -28,12 +43,19 @@ const (
 // that calling Decode with this Destination is a no-op (other than checking
 // the encoded form for errors in the byte code). Call SetDstImage to change
 // the raster image, before calling Decode or between calls to Decode.
+//
+// A Rasterizer reuses its buffers across calls to Decode. Once it has drawn
+// graphics onto an *image.RGBA at a given size, decoding graphics without
+// metadata palettes or titles into it again, at that size or smaller, makes
+// no heap allocations. Programs that draw many icons every frame can keep
+// one Rasterizer and call SetDstImage before each Decode.
 type Rasterizer struct {
 	z vector.Rasterizer
 
 	dst    draw.Image
 	r      image.Rectangle
 	drawOp draw.Op
+	fit    Fit
 
 	// scale and bias transforms the metadata.ViewBox rectangle to the (0, 0) -
 	// (r.Dx(), r.Dy()) rectangle.
@@ -51,6 +73,16 @@ type Rasterizer struct {
 
 	disabled bool
 
+	// clips is the stack of clip regions, as coverage masks the size of r,
+	// pushed by PushClip. A nil mask means that the region is unbounded.
+	// clipping is whether the current path is a clip path. clipBufs[i] is
+	// the buffer reused for clips[i]'s mask, when that mask is not shared
+	// with the entry below it.
+	clips    []*image.Alpha
+	clipBufs []*image.Alpha
+	clipping bool
+	mask     *image.Alpha
+
 	firstStartPath   bool
 	prevSmoothType   uint8
 	prevSmoothPointX float32
@@ -71,7 +103,8 @@ type Rasterizer struct {
 //
 // The IconVG graphic (which does not have a fixed size in pixels) will be
 // scaled in the X and Y dimensions to fit the rectangle r. The scaling factors
-// may differ in the two dimensions.
+// may differ in the two dimensions. DecodeOptions can override r and how the
+// graphic is fitted to it.
 func (z *Rasterizer) SetDstImage(dst draw.Image, r image.Rectangle, drawOp draw.Op) {
 	z.dst = dst
 	if r.Empty() {
@@ -82,6 +115,25 @@ func (z *Rasterizer) SetDstImage(dst draw.Image, r image.Rectangle, drawOp draw.
 	z.recalcTransform()
 }
 
+// DecodeToVector decodes an IconVG graphic, drawing it onto dst with the
+// given vector.Rasterizer. The graphic is scaled to fit the rectangle r.
+//
+// Each path is reset, filled and drawn with v, so that v's buffers are reused
+// across paths and across calls. Curves and arcs are converted to the cubic
+// Bézier curves that v accepts. The first path is drawn with v.DrawOp and
+// subsequent paths are drawn with draw.Over, as with a Rasterizer whose
+// destination was set with v.DrawOp. v.DrawOp is unchanged on return.
+func DecodeToVector(v *vector.Rasterizer, dst *image.RGBA, r image.Rectangle, src []byte, opts *DecodeOptions) error {
+	op := v.DrawOp
+	var z Rasterizer
+	z.z = *v
+	z.SetDstImage(dst, r, op)
+	err := Decode(&z, src, opts)
+	*v = z.z
+	v.DrawOp = op
+	return err
+}
+
 // Reset resets the Rasterizer for the given Metadata.
 func (z *Rasterizer) Reset(m Metadata) {
 	z.metadata = m
@@ -95,14 +147,59 @@ func (z *Rasterizer) Reset(m Metadata) {
 	z.prevSmoothPointY = 0
 	z.cReg = m.Palette
 	z.nReg = [64]float32{}
+	z.clips = z.clips[:0]
+	z.clipping = false
 	z.recalcTransform()
+	z.fillBackground()
+}
+
+// fillBackground fills the graphic's view box with its background color, if
+// it has one. Paths are then drawn over it, instead of the first path being
+// drawn with the SetDstImage draw.Op.
+func (z *Rasterizer) fillBackground() {
+	if z.dst == nil || z.metadata.Background == (color.RGBA{}) {
+		return
+	}
+	x0, y0 := z.absVec2(z.metadata.ViewBox.Min[0], z.metadata.ViewBox.Min[1])
+	x1, y1 := z.absVec2(z.metadata.ViewBox.Max[0], z.metadata.ViewBox.Max[1])
+	r := image.Rect(int(x0+0.5), int(y0+0.5), int(x1+0.5), int(y1+0.5)).Add(z.r.Min).Intersect(z.r)
+	z.flatColor = z.metadata.Background
+	z.flatImage.C = &z.flatColor
+	draw.Draw(z.dst, r, &z.flatImage, image.Point{}, z.drawOp)
+	z.firstStartPath = false
+	z.z.DrawOp = draw.Over
 }
 
 func (z *Rasterizer) recalcTransform() {
-	z.scaleX = float32(z.r.Dx()) / (z.metadata.ViewBox.Max[0] - z.metadata.ViewBox.Min[0])
+	w, h := float32(z.r.Dx()), float32(z.r.Dy())
+	vw := z.metadata.ViewBox.Max[0] - z.metadata.ViewBox.Min[0]
+	vh := z.metadata.ViewBox.Max[1] - z.metadata.ViewBox.Min[1]
+	z.scaleX = w / vw
 	z.biasX = -z.metadata.ViewBox.Min[0]
-	z.scaleY = float32(z.r.Dy()) / (z.metadata.ViewBox.Max[1] - z.metadata.ViewBox.Min[1])
+	z.scaleY = h / vh
 	z.biasY = -z.metadata.ViewBox.Min[1]
+
+	s := z.scaleX
+	switch z.fit {
+	case FitContain:
+		if s > z.scaleY {
+			s = z.scaleY
+		}
+	case FitCover:
+		if s < z.scaleY {
+			s = z.scaleY
+		}
+	default:
+		return
+	}
+	if s == 0 || isNaNOrInfinity(s) {
+		return
+	}
+	// Center the viewBox in r. The bias is in graphic coordinate space, so
+	// the centering offset is divided by the scale.
+	z.scaleX, z.scaleY = s, s
+	z.biasX += (w/s - vw) / 2
+	z.biasY += (h/s - vh) / 2
 }
 
 func (z *Rasterizer) SetCSel(cSel uint8) { z.cSel = cSel & 0x3f }
@@ -126,6 +223,82 @@ func (z *Rasterizer) SetLOD(lod0, lod1 float32) {
 	z.lod0, z.lod1 = lod0, lod1
 }
 
+func (z *Rasterizer) PushClip() {
+	var top *image.Alpha
+	if n := len(z.clips); n > 0 {
+		top = z.clips[n-1]
+	}
+	// The new entry shares its mask with the one below it. intersectClip
+	// replaces, rather than modifies, the mask.
+	z.clips = append(z.clips, top)
+	z.clipping = true
+}
+
+func (z *Rasterizer) PopClip() {
+	if n := len(z.clips); n > 0 {
+		z.clips = z.clips[:n-1]
+	}
+}
+
+// clip returns the current clip region's mask, or nil if it is unbounded.
+func (z *Rasterizer) clip() *image.Alpha {
+	if n := len(z.clips); n > 0 {
+		return z.clips[n-1]
+	}
+	return nil
+}
+
+// coverage returns the coverage of the current path as a mask the size of
+// z.r, overwriting the previous result.
+func (z *Rasterizer) coverage() *image.Alpha {
+	b := image.Rect(0, 0, z.r.Dx(), z.r.Dy())
+	z.mask = reuseAlpha(z.mask, b)
+	z.z.DrawOp = draw.Src
+	z.z.Draw(z.mask, b, image.Opaque, image.Point{})
+	return z.mask
+}
+
+// intersectClip intersects the current clip region with the coverage of the
+// current path.
+func (z *Rasterizer) intersectClip() {
+	m := z.coverage()
+	i := len(z.clips) - 1
+	for len(z.clipBufs) <= i {
+		z.clipBufs = append(z.clipBufs, nil)
+	}
+	// Entries below i only refer to clipBufs[j] for j < i, so clipBufs[i]
+	// can be overwritten.
+	clip := reuseAlpha(z.clipBufs[i], m.Rect)
+	z.clipBufs[i] = clip
+	copy(clip.Pix, m.Pix)
+	if top := z.clips[i]; top != nil {
+		mulAlpha(clip, top)
+	}
+	z.clips[i] = clip
+}
+
+// reuseAlpha returns an image with bounds r, reusing m's pixel buffer if it
+// is large enough. The pixels of the returned image are undefined.
+func reuseAlpha(m *image.Alpha, r image.Rectangle) *image.Alpha {
+	if m != nil && m.Rect == r {
+		return m
+	}
+	n := r.Dx() * r.Dy()
+	if m == nil || cap(m.Pix) < n {
+		return image.NewAlpha(r)
+	}
+	m.Pix, m.Stride, m.Rect = m.Pix[:n], r.Dx(), r
+	return m
+}
+
+// mulAlpha multiplies the coverage in dst by that in src, which has the same
+// bounds.
+func mulAlpha(dst, src *image.Alpha) {
+	for i, a := range src.Pix {
+		dst.Pix[i] = uint8((uint32(dst.Pix[i])*uint32(a) + 0x7f) / 0xff)
+	}
+}
+
 func (z *Rasterizer) unabsX(x float32) float32 { return x/z.scaleX - z.biasX }
 func (z *Rasterizer) unabsY(y float32) float32 { return y/z.scaleY - z.biasY }
 
@@ -226,6 +399,18 @@ func (z *Rasterizer) initGradient(rgba color.RGBA) (ok bool) {
 }
 
 func (z *Rasterizer) StartPath(adj uint8, x, y float32) {
+	if z.clipping {
+		// A clip path's color is ignored.
+		h := float32(z.r.Dy())
+		z.disabled = !(z.lod0 <= h && h < z.lod1)
+		if !z.disabled {
+			z.z.Reset(z.r.Dx(), z.r.Dy())
+			z.prevSmoothType = smoothTypeNone
+			z.z.MoveTo(z.absVec2(x, y))
+		}
+		return
+	}
+
 	z.flatColor = z.cReg[(z.cSel-adj)&0x3f]
 	if validAlphaPremulColor(z.flatColor) {
 		z.flatImage.C = &z.flatColor
@@ -256,6 +441,8 @@ func (z *Rasterizer) StartPath(adj uint8, x, y float32) {
 }
 
 func (z *Rasterizer) ClosePathEndPath() {
+	clipping := z.clipping
+	z.clipping = false
 	if z.disabled {
 		return
 	}
@@ -263,6 +450,22 @@ func (z *Rasterizer) ClosePathEndPath() {
 	if z.dst == nil {
 		return
 	}
+	if clipping {
+		z.intersectClip()
+		return
+	}
+	if clip := z.clip(); clip != nil || z.fill != &z.flatImage {
+		// The vector.Rasterizer draws sources other than an *image.Uniform
+		// with their At method, which allocates for every pixel. Drawing a
+		// gradient via a mask lets image/draw use its RGBA64At method.
+		op := z.z.DrawOp
+		m := z.coverage()
+		if clip != nil {
+			mulAlpha(m, clip)
+		}
+		draw.DrawMask(z.dst, z.r, z.fill, image.Point{}, m, image.Point{}, op)
+		return
+	}
 	z.z.Draw(z.dst, z.r, z.fill, image.Point{})
 }
 
@@ -434,28 +637,6 @@ func (z *Rasterizer) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep boo
 	}
 	z.prevSmoothType = smoothTypeNone
 
-	// We follow the "Conversion from endpoint to center parameterization"
-	// algorithm as per
-	// https://www.w3.org/TR/SVG/implnote.html#ArcConversionEndpointToCenter
-
-	// There seems to be a bug in the spec's "implementation notes".
-	//
-	// Actual implementations, such as
-	//	- https://git.gnome.org/browse/librsvg/tree/rsvg-path.c
-	//	- http://svn.apache.org/repos/asf/xmlgraphics/batik/branches/svg11/sources/org/apache/batik/ext/awt/geom/ExtendedGeneralPath.java
-	//	- https://java.net/projects/svgsalamander/sources/svn/content/trunk/svg-core/src/main/java/com/kitfox/svg/pathcmd/Arc.java
-	//	- https://github.com/millermedeiros/SVGParser/blob/master/com/millermedeiros/geom/SVGArc.as
-	// do something slightly different (marked with a †).
-
-	// (†) The Abs isn't part of the spec. Neither is checking that Rx and Ry
-	// are non-zero (and non-NaN).
-	Rx := math.Abs(float64(rx))
-	Ry := math.Abs(float64(ry))
-	if !(Rx > 0 && Ry > 0) {
-		z.z.LineTo(x, y)
-		return
-	}
-
 	// We work in IconVG coordinates (e.g. from -32 to +32 by default), rather
 	// than destination image coordinates (e.g. the width of the dst image),
 	// since the rx and ry radii also need to be scaled, but their scaling
@@ -463,133 +644,25 @@ func (z *Rasterizer) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep boo
 	// xAxisRotation.
 	//
 	// We convert back to destination image coordinates via absX and absY calls
-	// later, during arcSegmentTo.
+	// afterwards.
 	penX, penY := z.z.Pen()
-	x1 := float64(z.unabsX(penX))
-	y1 := float64(z.unabsY(penY))
-	x2 := float64(x)
-	y2 := float64(y)
-
-	phi := 2 * math.Pi * float64(xAxisRotation)
-
-	// Step 1: Compute (x1′, y1′)
-	halfDx := (x1 - x2) / 2
-	halfDy := (y1 - y2) / 2
-	cosPhi := math.Cos(phi)
-	sinPhi := math.Sin(phi)
-	x1Prime := +cosPhi*halfDx + sinPhi*halfDy
-	y1Prime := -sinPhi*halfDx + cosPhi*halfDy
-
-	// Step 2: Compute (cx′, cy′)
-	rxSq := Rx * Rx
-	rySq := Ry * Ry
-	x1PrimeSq := x1Prime * x1Prime
-	y1PrimeSq := y1Prime * y1Prime
-
-	// (†) Check that the radii are large enough.
-	radiiCheck := x1PrimeSq/rxSq + y1PrimeSq/rySq
-	if radiiCheck > 1 {
-		c := math.Sqrt(radiiCheck)
-		Rx *= c
-		Ry *= c
-		rxSq = Rx * Rx
-		rySq = Ry * Ry
-	}
-
-	denom := rxSq*y1PrimeSq + rySq*x1PrimeSq
-	step2 := 0.0
-	if a := rxSq*rySq/denom - 1; a > 0 {
-		step2 = math.Sqrt(a)
-	}
-	if largeArc == sweep {
-		step2 = -step2
-	}
-	cxPrime := +step2 * Rx * y1Prime / Ry
-	cyPrime := -step2 * Ry * x1Prime / Rx
-
-	// Step 3: Compute (cx, cy) from (cx′, cy′)
-	cx := +cosPhi*cxPrime - sinPhi*cyPrime + (x1+x2)/2
-	cy := +sinPhi*cxPrime + cosPhi*cyPrime + (y1+y2)/2
-
-	// Step 4: Compute θ1 and Δθ
-	ax := (+x1Prime - cxPrime) / Rx
-	ay := (+y1Prime - cyPrime) / Ry
-	bx := (-x1Prime - cxPrime) / Rx
-	by := (-y1Prime - cyPrime) / Ry
-	theta1 := angle(1, 0, ax, ay)
-	deltaTheta := angle(ax, ay, bx, by)
-	if sweep {
-		if deltaTheta < 0 {
-			deltaTheta += 2 * math.Pi
-		}
-	} else {
-		if deltaTheta > 0 {
-			deltaTheta -= 2 * math.Pi
-		}
+	var buf [4][6]float32
+	segs := appendArcBeziers(buf[:0], z.unabsX(penX), z.unabsY(penY),
+		rx, ry, xAxisRotation, largeArc, sweep, x, y)
+	if len(segs) == 0 {
+		z.z.LineTo(z.absVec2(x, y))
+		return
 	}
-
-	// This ends the
-	// https://www.w3.org/TR/SVG/implnote.html#ArcConversionEndpointToCenter
-	// algorithm. What follows below is specific to this implementation.
-
-	// We approximate an arc by one or more cubic Bézier curves.
-	n := int(math.Ceil(math.Abs(deltaTheta) / (math.Pi/2 + 0.001)))
-	for i := 0; i < n; i++ {
-		z.arcSegmentTo(cx, cy,
-			theta1+deltaTheta*float64(i+0)/float64(n),
-			theta1+deltaTheta*float64(i+1)/float64(n),
-			Rx, Ry, cosPhi, sinPhi,
+	for _, c := range segs {
+		z.z.CubeTo(
+			z.absX(c[0]), z.absY(c[1]),
+			z.absX(c[2]), z.absY(c[3]),
+			z.absX(c[4]), z.absY(c[5]),
 		)
 	}
 }
 
-// arcSegmentTo approximates an arc by a cubic Bézier curve. The mathematical
-// formulae for the control points are the same as that used by librsvg.
-func (z *Rasterizer) arcSegmentTo(cx, cy, theta1, theta2, rx, ry, cosPhi, sinPhi float64) {
-	halfDeltaTheta := (theta2 - theta1) * 0.5
-	q := math.Sin(halfDeltaTheta * 0.5)
-	t := (8 * q * q) / (3 * math.Sin(halfDeltaTheta))
-	cos1 := math.Cos(theta1)
-	sin1 := math.Sin(theta1)
-	cos2 := math.Cos(theta2)
-	sin2 := math.Sin(theta2)
-	x1 := rx * (+cos1 - t*sin1)
-	y1 := ry * (+sin1 + t*cos1)
-	x2 := rx * (+cos2 + t*sin2)
-	y2 := ry * (+sin2 - t*cos2)
-	x3 := rx * (+cos2)
-	y3 := ry * (+sin2)
-	z.z.CubeTo(
-		z.absX(float32(cx+cosPhi*x1-sinPhi*y1)),
-		z.absY(float32(cy+sinPhi*x1+cosPhi*y1)),
-		z.absX(float32(cx+cosPhi*x2-sinPhi*y2)),
-		z.absY(float32(cy+sinPhi*x2+cosPhi*y2)),
-		z.absX(float32(cx+cosPhi*x3-sinPhi*y3)),
-		z.absY(float32(cy+sinPhi*x3+cosPhi*y3)),
-	)
-}
-
 func (z *Rasterizer) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
 	ax, ay := z.relVec2(x, y)
 	z.AbsArcTo(rx, ry, xAxisRotation, largeArc, sweep, z.unabsX(ax), z.unabsY(ay))
 }
-
-// angle returns the angle between the u and v vectors.
-func angle(ux, uy, vx, vy float64) float64 {
-	uNorm := math.Sqrt(ux*ux + uy*uy)
-	vNorm := math.Sqrt(vx*vx + vy*vy)
-	norm := uNorm * vNorm
-	cos := (ux*vx + uy*vy) / norm
-	ret := 0.0
-	if cos <= -1 {
-		ret = math.Pi
-	} else if cos >= +1 {
-		ret = 0
-	} else {
-		ret = math.Acos(cos)
-	}
-	if ux*vy < uy*vx {
-		return -ret
-	}
-	return +ret
-}
@@ -0,0 +1,116 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+)
+
+// Recolor returns the IconVG graphic src with every path filled with the flat
+// color from filled with the color to instead. It is a simple way to theme a
+// graphic that does not use the custom palette.
+//
+// Only styling opcodes that set a color register to exactly from, as an RGBA
+// color, and whose register is then read by a path are changed. Gradients,
+// including their stops, colors that refer to the custom palette or to other
+// color registers, and the geometry are left untouched.
+//
+// Like Canonicalize, Recolor decodes and re-encodes the graphic, so the
+// result is in canonical form, and it returns an error for the same glyph
+// tables.
+func Recolor(src []byte, from, to color.RGBA) ([]byte, error) {
+	f := fillFinder{from: from}
+	if err := Decode(&f, src, nil); err != nil {
+		return nil, err
+	}
+	return reencode(src, func(e *canonicalEncoder) Destination {
+		return &recolorer{canonicalEncoder: e, fills: f.fills, to: RGBAColor(to)}
+	})
+}
+
+// fillFinder is a Destination that records which of the styling opcodes that
+// set a color register to the from color are for a path's fill. It numbers
+// those opcodes, in order, from 1.
+type fillFinder struct {
+	from     color.RGBA
+	fills    map[int]bool
+	n        int
+	cSel     uint8
+	cRegSet  [64]int // the number of the opcode that set the register, or 0
+	clipping bool    // whether the current path is a clip path
+}
+
+func (f *fillFinder) Reset(m Metadata) {
+	*f = fillFinder{from: f.from, fills: map[int]bool{}}
+}
+
+func (f *fillFinder) SetCSel(cSel uint8) { f.cSel = cSel & 0x3f }
+func (f *fillFinder) SetNSel(nSel uint8) {}
+
+func (f *fillFinder) SetCReg(adj uint8, incr bool, c Color) {
+	i := (f.cSel - adj) & 0x3f
+	f.cRegSet[i] = 0
+	if c.typ == ColorTypeRGBA {
+		f.n++
+		if c.rgba() == f.from {
+			f.cRegSet[i] = f.n
+		}
+	}
+	if incr {
+		f.cSel++
+	}
+}
+
+func (f *fillFinder) SetNReg(adj uint8, incr bool, x float32) {}
+func (f *fillFinder) SetLOD(lod0, lod1 float32)               {}
+func (f *fillFinder) PushClip()                               { f.clipping = true }
+func (f *fillFinder) PopClip()                                {}
+
+func (f *fillFinder) StartPath(adj uint8, x, y float32) {
+	if n := f.cRegSet[(f.cSel-adj)&0x3f]; n != 0 && !f.clipping {
+		f.fills[n] = true
+	}
+}
+
+func (f *fillFinder) ClosePathEndPath()               { f.clipping = false }
+func (f *fillFinder) ClosePathAbsMoveTo(x, y float32) {}
+func (f *fillFinder) ClosePathRelMoveTo(x, y float32) {}
+
+func (f *fillFinder) AbsHLineTo(x float32)                   {}
+func (f *fillFinder) RelHLineTo(x float32)                   {}
+func (f *fillFinder) AbsVLineTo(y float32)                   {}
+func (f *fillFinder) RelVLineTo(y float32)                   {}
+func (f *fillFinder) AbsLineTo(x, y float32)                 {}
+func (f *fillFinder) RelLineTo(x, y float32)                 {}
+func (f *fillFinder) AbsSmoothQuadTo(x, y float32)           {}
+func (f *fillFinder) RelSmoothQuadTo(x, y float32)           {}
+func (f *fillFinder) AbsQuadTo(x1, y1, x, y float32)         {}
+func (f *fillFinder) RelQuadTo(x1, y1, x, y float32)         {}
+func (f *fillFinder) AbsSmoothCubeTo(x2, y2, x, y float32)   {}
+func (f *fillFinder) RelSmoothCubeTo(x2, y2, x, y float32)   {}
+func (f *fillFinder) AbsCubeTo(x1, y1, x2, y2, x, y float32) {}
+func (f *fillFinder) RelCubeTo(x1, y1, x2, y2, x, y float32) {}
+
+func (f *fillFinder) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {}
+func (f *fillFinder) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {}
+
+// recolorer is a canonicalEncoder that substitutes the to color in the
+// styling opcodes found by a fillFinder.
+type recolorer struct {
+	*canonicalEncoder
+	fills map[int]bool
+	n     int
+	to    Color
+}
+
+func (r *recolorer) SetCReg(adj uint8, incr bool, c Color) {
+	if c.typ == ColorTypeRGBA {
+		r.n++
+		if r.fills[r.n] {
+			c = r.to
+		}
+	}
+	r.canonicalEncoder.SetCReg(adj, incr, c)
+}
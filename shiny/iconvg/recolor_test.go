@@ -0,0 +1,96 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cRegRecorder is a Destination that records the colors that styling opcodes
+// set the color registers to.
+type cRegRecorder struct {
+	Encoder
+	colors []Color
+}
+
+func (r *cRegRecorder) SetCReg(adj uint8, incr bool, c Color) {
+	r.colors = append(r.colors, c)
+	r.Encoder.SetCReg(adj, incr, c)
+}
+
+func TestRecolor(t *testing.T) {
+	src, err := os.ReadFile(filepath.FromSlash("testdata/video-005.primitive.ivg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	background := color.RGBA{0x7c, 0x7e, 0x7c, 0xff}
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+
+	got, err := Recolor(src, background, red)
+	if err != nil {
+		t.Fatalf("Recolor: %v", err)
+	}
+
+	// Only the background's fill color changed.
+	var before, after cRegRecorder
+	if err := Decode(&before, src, nil); err != nil {
+		t.Fatalf("Decode(src): %v", err)
+	}
+	if err := Decode(&after, got, nil); err != nil {
+		t.Fatalf("Decode(recolored): %v", err)
+	}
+	if len(before.colors) != len(after.colors) {
+		t.Fatalf("got %d colors, want %d", len(after.colors), len(before.colors))
+	}
+	for i := range before.colors {
+		want := before.colors[i]
+		if i == 0 {
+			if want != RGBAColor(background) {
+				t.Fatalf("color #0: got %v, want the background", want)
+			}
+			want = RGBAColor(red)
+		}
+		if after.colors[i] != want {
+			t.Errorf("color #%d: got %v, want %v", i, after.colors[i], want)
+		}
+	}
+
+	// Recoloring back gives the canonical encoding of the original.
+	back, err := Recolor(got, red, background)
+	if err != nil {
+		t.Fatalf("Recolor back: %v", err)
+	}
+	canonical, err := Canonicalize(src)
+	if err != nil {
+		t.Fatalf("Canonicalize: %v", err)
+	}
+	if !bytes.Equal(back, canonical) {
+		t.Errorf("Recolor back:\ngot  % x\nwant % x", back, canonical)
+	}
+}
+
+func TestRecolorGradient(t *testing.T) {
+	src, err := os.ReadFile(filepath.FromSlash("testdata/gradient.ivg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, err := Canonicalize(src)
+	if err != nil {
+		t.Fatalf("Canonicalize: %v", err)
+	}
+
+	// Red is a gradient stop, not a fill, so it is left untouched.
+	got, err := Recolor(src, color.RGBA{0xff, 0x00, 0x00, 0xff}, color.RGBA{0x00, 0x00, 0x00, 0xff})
+	if err != nil {
+		t.Fatalf("Recolor: %v", err)
+	}
+	if !bytes.Equal(got, canonical) {
+		t.Errorf("Recolor:\ngot  % x\nwant % x", got, canonical)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import "errors"
+
+var errInvalidPathSegments = errors.New("iconvg: invalid path segments")
+
+// PathSegmentOp is the kind of a PathSegment.
+type PathSegmentOp uint8
+
+const (
+	SegmentMoveTo PathSegmentOp = iota + 1
+	SegmentLineTo
+	SegmentQuadTo
+	SegmentCubeTo
+	SegmentClose
+)
+
+// PathSegment is one segment of a path, in the style of the MoveTo, LineTo,
+// QuadTo, CubeTo and ClosePath methods of golang.org/x/image/vector's
+// Rasterizer. All coordinates are absolute.
+//
+// A SegmentMoveTo or SegmentLineTo uses only X and Y. A SegmentQuadTo also
+// uses X1 and Y1 for its control point, and a SegmentCubeTo also uses X1, Y1,
+// X2 and Y2 for its two control points. A SegmentClose uses no coordinates.
+type PathSegment struct {
+	Op     PathSegmentOp
+	X1, Y1 float32
+	X2, Y2 float32
+	X, Y   float32
+}
+
+// AppendSegments appends a path made of segs, filled with CREG[CSEL-adj].
+//
+// The first segment must be a SegmentMoveTo. As with golang.org/x/image/vector,
+// a later SegmentMoveTo implicitly closes the current sub-path, and the path
+// as a whole is closed at the end, whether or not segs ends with a
+// SegmentClose.
+//
+// Each segment is encoded with whichever of the equivalent drawing opcodes
+// takes the fewest bytes: horizontal and vertical lines use the H and V
+// opcodes, and each segment's coordinates are encoded as absolute or
+// relative, as long as the relative form decodes to exactly the same point.
+func (e *Encoder) AppendSegments(adj uint8, segs []PathSegment) {
+	if e.err != nil {
+		return
+	}
+	if len(segs) == 0 || segs[0].Op != SegmentMoveTo {
+		e.err = errInvalidPathSegments
+		return
+	}
+	for _, s := range segs[1:] {
+		if s.Op < SegmentMoveTo || SegmentClose < s.Op {
+			e.err = errInvalidPathSegments
+			return
+		}
+	}
+
+	e.StartPath(adj, segs[0].X, segs[0].Y)
+	if e.err != nil {
+		return
+	}
+	// cur and start are the current point and the start of the current
+	// sub-path, as a decoder will see them after quantization.
+	cur := [2]float32{e.roundTrip(segs[0].X), e.roundTrip(segs[0].Y)}
+	start := cur
+	// move is the start of a sub-path that has been moved to, but that no
+	// drawing op has yet been encoded for.
+	move, moving := [2]float32{}, false
+
+	for _, s := range segs[1:] {
+		switch s.Op {
+		case SegmentMoveTo:
+			move, moving = [2]float32{s.X, s.Y}, true
+			continue
+		case SegmentClose:
+			if !moving {
+				move, moving = start, true
+			}
+			continue
+		}
+
+		if moving {
+			// After closing a sub-path, a decoder's current point is the
+			// start of that sub-path.
+			cur = e.appendSegment('Y', 'y', start, move[:])
+			start, moving = cur, false
+		}
+		switch s.Op {
+		case SegmentLineTo:
+			x, y := e.roundTrip(s.X), e.roundTrip(s.Y)
+			switch {
+			case y == cur[1]:
+				cur = e.appendSegment('H', 'h', cur, []float32{x})
+			case x == cur[0]:
+				cur = e.appendSegment('V', 'v', cur, []float32{y})
+			default:
+				cur = e.appendSegment('L', 'l', cur, []float32{x, y})
+			}
+		case SegmentQuadTo:
+			cur = e.appendSegment('Q', 'q', cur, []float32{s.X1, s.Y1, s.X, s.Y})
+		case SegmentCubeTo:
+			cur = e.appendSegment('C', 'c', cur, []float32{s.X1, s.Y1, s.X2, s.Y2, s.X, s.Y})
+		}
+	}
+	e.ClosePathEndPath()
+}
+
+// roundTrip returns the coordinate that a decoder reads back after the
+// Encoder writes x.
+func (e *Encoder) roundTrip(x float32) float32 {
+	var b buffer
+	b.encodeCoordinate(quantize(x, e.highResolutionCoordinates))
+	v, _ := b.decodeCoordinate()
+	return v
+}
+
+// appendSegment appends a drawing op with the given absolute arguments, as
+// either absOp or its relative form relOp, and returns the new current point.
+// Relative arguments are relative to cur.
+//
+// The arguments alternate x and y coordinates, except for the H and V ops,
+// whose one argument is an x or y coordinate respectively.
+func (e *Encoder) appendSegment(absOp, relOp byte, cur [2]float32, args []float32) [2]float32 {
+	var abs, rel [6]float32
+	absN, relN, relOK := 0, 0, true
+	for i, a := range args {
+		origin := cur[i&1]
+		if absOp == 'V' {
+			origin = cur[1]
+		}
+		var b buffer
+		absN += b.encodeCoordinate(quantize(a, e.highResolutionCoordinates))
+		abs[i], _ = b.decodeCoordinate()
+
+		b = b[:0]
+		relN += b.encodeCoordinate(quantize(a-origin, e.highResolutionCoordinates))
+		rel[i], _ = b.decodeCoordinate()
+		relOK = relOK && origin+rel[i] == abs[i]
+	}
+
+	// Continuing a run of the same op saves an opcode byte.
+	if e.drawOp == absOp {
+		relN++
+	} else if e.drawOp == relOp {
+		absN++
+	}
+	op, v := absOp, abs
+	if relOK && relN < absN {
+		op, v = relOp, rel
+	}
+	e.draw(op, v[0], v[1], v[2], v[3], v[4], v[5])
+
+	switch absOp {
+	case 'H':
+		return [2]float32{abs[0], cur[1]}
+	case 'V':
+		return [2]float32{cur[0], abs[0]}
+	}
+	n := len(args)
+	return [2]float32{abs[n-2], abs[n-1]}
+}
@@ -0,0 +1,168 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+	"math"
+	"testing"
+)
+
+func TestAppendSegmentsRect(t *testing.T) {
+	var got, want Encoder
+	got.AppendSegments(0, []PathSegment{
+		{Op: SegmentMoveTo, X: -8, Y: -8},
+		{Op: SegmentLineTo, X: +8, Y: -8},
+		{Op: SegmentLineTo, X: +8, Y: +8},
+		{Op: SegmentLineTo, X: -8, Y: +8},
+		{Op: SegmentClose},
+	})
+	want.AppendRect(0, -8, -8, 8, 8)
+
+	gotBytes, err := got.Bytes()
+	if err != nil {
+		t.Fatalf("got: %v", err)
+	}
+	wantBytes, err := want.Bytes()
+	if err != nil {
+		t.Fatalf("want: %v", err)
+	}
+	if !bytes.Equal(gotBytes, wantBytes) {
+		t.Errorf("\ngot  % x\nwant % x", gotBytes, wantBytes)
+	}
+}
+
+func TestAppendSegmentsSubPaths(t *testing.T) {
+	var got, want Encoder
+	got.AppendSegments(0, []PathSegment{
+		{Op: SegmentMoveTo, X: 0, Y: 0},
+		{Op: SegmentLineTo, X: 10, Y: 10},
+		{Op: SegmentLineTo, X: 20, Y: 0},
+		{Op: SegmentClose},
+		// This sub-path is closed by the SegmentMoveTo that follows it.
+		{Op: SegmentLineTo, X: -10, Y: 10},
+		{Op: SegmentMoveTo, X: 100, Y: 100},
+		{Op: SegmentQuadTo, X1: 120, Y1: 100, X: 120, Y: 120},
+	})
+
+	want.StartPath(0, 0, 0)
+	want.AbsLineTo(10, 10)
+	want.AbsLineTo(20, 0)
+	want.ClosePathAbsMoveTo(0, 0)
+	want.AbsLineTo(-10, 10)
+	want.ClosePathAbsMoveTo(100, 100)
+	want.RelQuadTo(20, 0, 20, 20)
+	want.ClosePathEndPath()
+
+	gotBytes, err := got.Bytes()
+	if err != nil {
+		t.Fatalf("got: %v", err)
+	}
+	wantBytes, err := want.Bytes()
+	if err != nil {
+		t.Fatalf("want: %v", err)
+	}
+	if !bytes.Equal(gotBytes, wantBytes) {
+		t.Errorf("\ngot  % x\nwant % x", gotBytes, wantBytes)
+	}
+}
+
+func TestAppendSegmentsErrors(t *testing.T) {
+	for _, segs := range [][]PathSegment{
+		nil,
+		{{Op: SegmentLineTo, X: 1, Y: 1}},
+		{{Op: SegmentMoveTo}, {Op: 0}},
+		{{Op: SegmentMoveTo}, {Op: SegmentClose + 1}},
+	} {
+		var e Encoder
+		e.AppendSegments(0, segs)
+		if _, err := e.Bytes(); err != errInvalidPathSegments {
+			t.Errorf("%v: got %v, want %v", segs, err, errInvalidPathSegments)
+		}
+	}
+}
+
+// starSegments returns the segments of a five-pointed star centered on the
+// origin, with points at the given outer radius and inner vertices at the
+// given inner radius.
+func starSegments(outer, inner float64) []PathSegment {
+	segs := []PathSegment(nil)
+	for i := 0; i < 10; i++ {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		s, c := math.Sincos(2 * math.Pi * float64(i) / 10)
+		op := SegmentLineTo
+		if i == 0 {
+			op = SegmentMoveTo
+		}
+		segs = append(segs, PathSegment{Op: op, X: float32(r * s), Y: float32(-r * c)})
+	}
+	return append(segs, PathSegment{Op: SegmentClose})
+}
+
+func TestAppendSegmentsStar(t *testing.T) {
+	segs := starSegments(30, 12)
+	for _, hiRes := range []bool{false, true} {
+		var got, want Encoder
+		got.HighResolutionCoordinates = hiRes
+		want.HighResolutionCoordinates = hiRes
+
+		got.AppendSegments(0, segs)
+
+		want.StartPath(0, segs[0].X, segs[0].Y)
+		for _, s := range segs[1 : len(segs)-1] {
+			want.AbsLineTo(s.X, s.Y)
+		}
+		want.ClosePathEndPath()
+
+		gotBytes, err := got.Bytes()
+		if err != nil {
+			t.Fatalf("hiRes=%t: got: %v", hiRes, err)
+		}
+		wantBytes, err := want.Bytes()
+		if err != nil {
+			t.Fatalf("hiRes=%t: want: %v", hiRes, err)
+		}
+		if len(gotBytes) > len(wantBytes) {
+			t.Errorf("hiRes=%t: got %d bytes, want at most %d", hiRes, len(gotBytes), len(wantBytes))
+		}
+
+		// The two encodings should rasterize to the same star.
+		var images [2]*image.RGBA
+		for i, b := range [][]byte{gotBytes, wantBytes} {
+			images[i] = image.NewRGBA(image.Rect(0, 0, 64, 64))
+			var z Rasterizer
+			z.SetDstImage(images[i], images[i].Bounds(), draw.Src)
+			if err := Decode(&z, b, nil); err != nil {
+				t.Fatalf("hiRes=%t: Decode: %v", hiRes, err)
+			}
+		}
+		if err := checkApproxEqual(images[0], images[1]); err != nil {
+			t.Errorf("hiRes=%t: %v", hiRes, err)
+		}
+
+		// Check that the rasterized star is not blank: its center and the
+		// tip of its top point are filled, but the corners are not.
+		m := images[0]
+		for _, p := range []struct {
+			at     image.Point
+			filled bool
+		}{
+			{image.Point{32, 32}, true},
+			{image.Point{32, 5}, true},
+			{image.Point{1, 1}, false},
+			{image.Point{62, 62}, false},
+		} {
+			_, _, _, a := m.At(p.at.X, p.at.Y).RGBA()
+			if filled := a > 0x8000; filled != p.filled {
+				t.Errorf("hiRes=%t: at %v: got filled=%t, want %t", hiRes, p.at, filled, p.filled)
+			}
+		}
+	}
+}
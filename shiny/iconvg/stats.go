@@ -0,0 +1,138 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+// Stats holds summary statistics about an IconVG graphic, such as the number
+// of paths and path segments. They estimate the complexity of a graphic
+// without rendering it.
+type Stats struct {
+	// Paths is the number of paths, including clip paths.
+	Paths int
+
+	// Lines, Curves and Arcs are the number of line, curve (quadratic or
+	// cubic Bézier) and arc segments. Implicit segments, such as those added
+	// by closing a path, are not counted.
+	Lines  int
+	Curves int
+	Arcs   int
+
+	// Colors and Gradients are the number of distinct flat colors and
+	// gradients that paths are filled with; clip paths are not filled.
+	// Colors that refer to the custom palette are distinguished by palette
+	// index, not by RGBA value, and blends are distinguished by their
+	// encoding.
+	Colors    int
+	Gradients int
+
+	// UsesLOD is whether any path has level of detail bounds other than the
+	// default of [0, +∞).
+	UsesLOD bool
+}
+
+// Segments returns the total number of path segments.
+func (s Stats) Segments() int {
+	return s.Lines + s.Curves + s.Arcs
+}
+
+// ComputeStats returns summary statistics about the IconVG graphic src. It
+// decodes but does not render src.
+func ComputeStats(src []byte) (Stats, error) {
+	c := statsCounter{}
+	if err := Decode(&c, src, nil); err != nil {
+		return Stats{}, err
+	}
+	return c.stats, nil
+}
+
+// statsCounter is a Destination that computes Stats.
+type statsCounter struct {
+	stats     Stats
+	cSel      uint8
+	cReg      [64]Color
+	colors    map[Color]bool
+	gradients map[Color]bool
+	clipping  bool
+}
+
+func (c *statsCounter) Reset(m Metadata) {
+	*c = statsCounter{
+		colors:    map[Color]bool{},
+		gradients: map[Color]bool{},
+	}
+	// The color registers start out holding the custom palette.
+	for i := range c.cReg {
+		c.cReg[i] = PaletteIndexColor(uint8(i))
+	}
+}
+
+func (c *statsCounter) SetCSel(cSel uint8) { c.cSel = cSel & 0x3f }
+func (c *statsCounter) SetNSel(nSel uint8) {}
+
+func (c *statsCounter) SetCReg(adj uint8, incr bool, col Color) {
+	if col.typ == ColorTypeCReg {
+		col = c.cReg[col.cReg()&0x3f]
+	}
+	c.cReg[(c.cSel-adj)&0x3f] = col
+	if incr {
+		c.cSel++
+	}
+}
+
+func (c *statsCounter) SetNReg(adj uint8, incr bool, f float32) {}
+
+func (c *statsCounter) SetLOD(lod0, lod1 float32) {
+	if lod0 != 0 || lod1 != positiveInfinity {
+		c.stats.UsesLOD = true
+	}
+}
+
+func (c *statsCounter) PushClip() { c.clipping = true }
+func (c *statsCounter) PopClip()  {}
+
+func (c *statsCounter) StartPath(adj uint8, x, y float32) {
+	c.stats.Paths++
+	if c.clipping {
+		return
+	}
+	col := c.cReg[(c.cSel-adj)&0x3f]
+	if col.typ == ColorTypeRGBA && !validAlphaPremulColor(col.data) {
+		if col.data.A == 0x00 && col.data.B&0x80 != 0 && !c.gradients[col] {
+			c.gradients[col] = true
+			c.stats.Gradients++
+		}
+		return
+	}
+	if !c.colors[col] {
+		c.colors[col] = true
+		c.stats.Colors++
+	}
+}
+
+func (c *statsCounter) ClosePathEndPath()               { c.clipping = false }
+func (c *statsCounter) ClosePathAbsMoveTo(x, y float32) {}
+func (c *statsCounter) ClosePathRelMoveTo(x, y float32) {}
+
+func (c *statsCounter) AbsHLineTo(x float32)                   { c.stats.Lines++ }
+func (c *statsCounter) RelHLineTo(x float32)                   { c.stats.Lines++ }
+func (c *statsCounter) AbsVLineTo(y float32)                   { c.stats.Lines++ }
+func (c *statsCounter) RelVLineTo(y float32)                   { c.stats.Lines++ }
+func (c *statsCounter) AbsLineTo(x, y float32)                 { c.stats.Lines++ }
+func (c *statsCounter) RelLineTo(x, y float32)                 { c.stats.Lines++ }
+func (c *statsCounter) AbsSmoothQuadTo(x, y float32)           { c.stats.Curves++ }
+func (c *statsCounter) RelSmoothQuadTo(x, y float32)           { c.stats.Curves++ }
+func (c *statsCounter) AbsQuadTo(x1, y1, x, y float32)         { c.stats.Curves++ }
+func (c *statsCounter) RelQuadTo(x1, y1, x, y float32)         { c.stats.Curves++ }
+func (c *statsCounter) AbsSmoothCubeTo(x2, y2, x, y float32)   { c.stats.Curves++ }
+func (c *statsCounter) RelSmoothCubeTo(x2, y2, x, y float32)   { c.stats.Curves++ }
+func (c *statsCounter) AbsCubeTo(x1, y1, x2, y2, x, y float32) { c.stats.Curves++ }
+func (c *statsCounter) RelCubeTo(x1, y1, x2, y2, x, y float32) { c.stats.Curves++ }
+
+func (c *statsCounter) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	c.stats.Arcs++
+}
+
+func (c *statsCounter) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {
+	c.stats.Arcs++
+}
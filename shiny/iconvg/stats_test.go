@@ -0,0 +1,61 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestComputeStats(t *testing.T) {
+	testCases := []struct {
+		filename string
+		want     Stats
+	}{{
+		filename: "action-info.hires.ivg",
+		want: Stats{
+			Paths:  1,
+			Lines:  8,
+			Curves: 4,
+			Colors: 1,
+		},
+	}, {
+		filename: "gradient.ivg",
+		want: Stats{
+			Paths:     4,
+			Lines:     12,
+			Gradients: 4,
+		},
+	}, {
+		filename: "lod-polygon.ivg",
+		want: Stats{
+			Paths:   4,
+			Lines:   10,
+			Colors:  1,
+			UsesLOD: true,
+		},
+	}}
+
+	for _, tc := range testCases {
+		ivgData, err := os.ReadFile(filepath.Join("testdata", tc.filename))
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		got, err := ComputeStats(ivgData)
+		if err != nil {
+			t.Errorf("%s: ComputeStats: %v", tc.filename, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", tc.filename, got, tc.want)
+		}
+	}
+
+	if _, err := ComputeStats([]byte("not an IconVG graphic")); err == nil {
+		t.Error("invalid data: got nil error")
+	}
+}
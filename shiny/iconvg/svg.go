@@ -0,0 +1,205 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// DecodeToSVG decodes an IconVG graphic and returns an SVG document that
+// draws the same picture. It does not recover any SVG that the graphic was
+// converted from: each path becomes a <path> element whose data is made of
+// absolute M, L, C and Z commands, in the canonical form described at Equal.
+//
+// Flat fills become fill colors. Gradients become <linearGradient> and
+// <radialGradient> elements, whose gradientTransform is the inverse of the
+// graphic's transformation from graphic coordinates to gradient
+// coordinates. SVG has no equivalent of GradientSpreadNone, so it is
+// emulated by the pad spread method and extra transparent stops.
+//
+// Clip paths become <clipPath> elements, applied to a group of the paths
+// that they clip. The background color, if any, becomes a <rect> that fills
+// the view box, and the title, if any, becomes a <title> element.
+//
+// SVG also has no equivalent of levels of detail. Each path is included if
+// its levels of detail include the height of the view box, which is how it
+// would be rendered at one pixel per unit.
+func DecodeToSVG(src []byte) (string, error) {
+	var d semanticRecorder
+	if err := Decode(&d, src, nil); err != nil {
+		return "", err
+	}
+	m := &d.metadata
+	vb := m.ViewBox
+	h := vb.Max[1] - vb.Min[1]
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s">`+"\n",
+		svgNumber(vb.Min[0]), svgNumber(vb.Min[1]), svgNumber(vb.Max[0]-vb.Min[0]), svgNumber(h))
+	if m.Title != "" {
+		b.WriteString("<title>")
+		xml.EscapeText(&b, []byte(m.Title))
+		b.WriteString("</title>\n")
+	}
+	if m.Background.A != 0 {
+		fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s"%s/>`+"\n",
+			svgNumber(vb.Min[0]), svgNumber(vb.Min[1]), svgNumber(vb.Max[0]-vb.Min[0]), svgNumber(h),
+			svgColorAttrs("fill", m.Background))
+	}
+
+	// groups is the number of <g> elements opened for clip paths and not yet
+	// closed by a PopClip. ids is the number of ids used.
+	groups, ids := 0, 0
+	for i := range d.paths {
+		p := &d.paths[i]
+		switch {
+		case p.popClip:
+			if groups > 0 {
+				b.WriteString("</g>\n")
+				groups--
+			}
+			continue
+		case p.clip:
+			groups++
+			if !(p.lod0 <= h && h < p.lod1) {
+				// Like the Rasterizer, ignore a clip path that is not
+				// drawn, but still match its PopClip.
+				b.WriteString("<g>\n")
+				continue
+			}
+			ids++
+			fmt.Fprintf(&b, `<clipPath id="c%d"><path d="%s"/></clipPath>`+"\n", ids, svgPathData(p.segs))
+			fmt.Fprintf(&b, `<g clip-path="url(#c%d)">`+"\n", ids)
+			continue
+		case !(p.lod0 <= h && h < p.lod1):
+			continue
+		}
+
+		fill := ""
+		if f := &p.fill; f.gradient {
+			ids++
+			fill = fmt.Sprintf(` fill="url(#g%d)"`, ids)
+			writeSVGGradient(&b, f, ids)
+		} else {
+			fill = svgColorAttrs("fill", f.color)
+		}
+		fmt.Fprintf(&b, `<path d="%s"%s/>`+"\n", svgPathData(p.segs), fill)
+	}
+	for ; groups > 0; groups-- {
+		b.WriteString("</g>\n")
+	}
+	b.WriteString("</svg>\n")
+	return b.String(), nil
+}
+
+// writeSVGGradient writes the gradient f as an SVG element with the id "g"
+// followed by id.
+func writeSVGGradient(b *strings.Builder, f *semanticFill, id int) {
+	spread := ""
+	switch f.spread {
+	case GradientSpreadReflect:
+		spread = ` spreadMethod="reflect"`
+	case GradientSpreadRepeat:
+		spread = ` spreadMethod="repeat"`
+	}
+
+	// The transform maps graphic coordinates (x, y) to gradient coordinates:
+	//
+	//	gx = a*x + b*y + c
+	//	gy = d*x + e*y + f
+	//
+	// A linear gradient's offset is gx, and a radial gradient's is the
+	// distance from (gx, gy) to the origin. An SVG gradientTransform goes
+	// the other way, so we invert the transform.
+	t := f.transform
+	a, bb, c := float64(t[0]), float64(t[1]), float64(t[2])
+	d, e, ff := float64(t[3]), float64(t[4]), float64(t[5])
+	if !f.radial {
+		// Only gx matters. Pick any gy for which the transform is invertible.
+		d, e, ff = -bb, a, 0
+	}
+	tag, geom := "linearGradient", `x1="0" y1="0" x2="1" y2="0"`
+	if f.radial {
+		tag, geom = "radialGradient", `cx="0" cy="0" r="1"`
+	}
+	transform := ""
+	if det := a*e - bb*d; det != 0 {
+		ia, ib, id, ie := e/det, -bb/det, -d/det, a/det
+		ic, iff := -(ia*c + ib*ff), -(id*c + ie*ff)
+		transform = fmt.Sprintf(` gradientTransform="matrix(%s %s %s %s %s %s)"`,
+			svgNumber(float32(ia)), svgNumber(float32(id)), svgNumber(float32(ib)),
+			svgNumber(float32(ie)), svgNumber(float32(ic)), svgNumber(float32(iff)))
+	} else if f.radial {
+		// A zero radius paints with the last stop's color, as does a
+		// degenerate transform.
+		geom = `cx="0" cy="0" r="0"`
+	} else {
+		geom = `x1="0" y1="0" x2="0" y2="0"`
+	}
+
+	fmt.Fprintf(b, `<%s id="g%d" gradientUnits="userSpaceOnUse" %s%s%s>`+"\n", tag, id, geom, transform, spread)
+	stops := f.stops
+	if f.spread == GradientSpreadNone && len(stops) > 0 {
+		// Emulate the transparency outside of offsets 0 to 1 with the pad
+		// spread method and transparent stops at each end.
+		stops = make([]semanticStop, 0, len(f.stops)+4)
+		stops = append(stops, semanticStop{}, semanticStop{color: f.stops[0].color})
+		stops = append(stops, f.stops...)
+		stops = append(stops, semanticStop{offset: 1, color: f.stops[len(f.stops)-1].color}, semanticStop{offset: 1})
+	}
+	for _, s := range stops {
+		fmt.Fprintf(b, `<stop offset="%s"%s/>`+"\n", svgNumber(s.offset), svgColorAttrs("stop-color", s.color))
+	}
+	fmt.Fprintf(b, "</%s>\n", tag)
+}
+
+// svgPathData returns the SVG path data for segs.
+func svgPathData(segs []PathSegment) string {
+	var b strings.Builder
+	for i, s := range segs {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		switch s.Op {
+		case SegmentMoveTo:
+			fmt.Fprintf(&b, "M%s %s", svgNumber(s.X), svgNumber(s.Y))
+		case SegmentLineTo:
+			fmt.Fprintf(&b, "L%s %s", svgNumber(s.X), svgNumber(s.Y))
+		case SegmentCubeTo:
+			fmt.Fprintf(&b, "C%s %s %s %s %s %s",
+				svgNumber(s.X1), svgNumber(s.Y1), svgNumber(s.X2), svgNumber(s.Y2), svgNumber(s.X), svgNumber(s.Y))
+		case SegmentClose:
+			b.WriteByte('Z')
+		}
+	}
+	return b.String()
+}
+
+// svgColorAttrs returns the SVG attributes, starting with a space, for the
+// alpha-premultiplied color c as the named color property. The opacity is
+// given by a property whose name has "-color" replaced by "-opacity", or
+// "-opacity" appended.
+func svgColorAttrs(name string, c color.RGBA) string {
+	unpremul := func(x uint8) uint32 {
+		if c.A == 0 {
+			return 0
+		}
+		return (uint32(x)*0xff + uint32(c.A)/2) / uint32(c.A)
+	}
+	s := fmt.Sprintf(` %s="#%02x%02x%02x"`, name, unpremul(c.R), unpremul(c.G), unpremul(c.B))
+	if c.A != 0xff {
+		opacity := strings.TrimSuffix(name, "-color") + "-opacity"
+		s += fmt.Sprintf(` %s="%s"`, opacity, svgNumber(float32(c.A)/0xff))
+	}
+	return s
+}
+
+func svgNumber(x float32) string {
+	return strconv.FormatFloat(float64(x), 'g', -1, 32)
+}
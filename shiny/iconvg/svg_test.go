@@ -0,0 +1,339 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/math/f64"
+	"golang.org/x/image/vector"
+)
+
+// svgNode is an element of an SVG document.
+type svgNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []svgNode  `xml:",any"`
+}
+
+func (n *svgNode) attr(name string) string {
+	for _, a := range n.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseSVGNumbers parses the space-separated numbers in s.
+func parseSVGNumbers(s string) ([]float64, error) {
+	var v []float64
+	for _, f := range strings.Fields(s) {
+		x, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+		v = append(v, x)
+	}
+	return v, nil
+}
+
+// parseSVGColor parses the SVG color attributes of n with the given names.
+func parseSVGColor(n *svgNode, colorName, opacityName string) (color.NRGBA, error) {
+	var c color.NRGBA
+	if _, err := fmt.Sscanf(n.attr(colorName), "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("%s %q: %v", colorName, n.attr(colorName), err)
+	}
+	c.A = 0xff
+	if s := n.attr(opacityName); s != "" {
+		o, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return c, err
+		}
+		c.A = uint8(o*0xff + 0.5)
+	}
+	return c, nil
+}
+
+// svgGradient is an image.Image that paints an SVG gradient, in the
+// userSpaceOnUse units of the elements produced by DecodeToSVG, with the
+// colors interpolated in premultiplied space.
+type svgGradient struct {
+	radial bool
+	spread string
+	// pix2Grad maps pixels to gradient coordinates.
+	pix2Grad f64.Aff3
+	stops    []svgStop
+}
+
+type svgStop struct {
+	offset float64
+	color  color.RGBA64
+}
+
+func (g *svgGradient) ColorModel() color.Model { return color.RGBA64Model }
+
+func (g *svgGradient) Bounds() image.Rectangle {
+	return image.Rectangle{Min: image.Point{-1e9, -1e9}, Max: image.Point{+1e9, +1e9}}
+}
+
+func (g *svgGradient) At(x, y int) color.Color {
+	px, py := float64(x)+0.5, float64(y)+0.5
+	m := &g.pix2Grad
+	gx, gy := m[0]*px+m[1]*py+m[2], m[3]*px+m[4]*py+m[5]
+	t := gx
+	if g.radial {
+		t = math.Sqrt(gx*gx + gy*gy)
+	}
+	switch g.spread {
+	case "reflect":
+		t = math.Abs(t - 2*math.Floor(t/2))
+		if t > 1 {
+			t = 2 - t
+		}
+	case "repeat":
+		t -= math.Floor(t)
+	}
+
+	s := g.stops
+	if t <= s[0].offset {
+		return s[0].color
+	}
+	for i := 1; i < len(s); i++ {
+		if t < s[i].offset {
+			u := (t - s[i-1].offset) / (s[i].offset - s[i-1].offset)
+			lerp := func(a, b uint16) uint16 { return uint16((1-u)*float64(a) + u*float64(b)) }
+			c0, c1 := s[i-1].color, s[i].color
+			return color.RGBA64{lerp(c0.R, c1.R), lerp(c0.G, c1.G), lerp(c0.B, c1.B), lerp(c0.A, c1.A)}
+		}
+	}
+	return s[len(s)-1].color
+}
+
+// parseSVGGradient parses a gradient element produced by DecodeToSVG into a
+// gradient for an image whose pixels are units of the view box vb.
+func parseSVGGradient(n *svgNode, vb [4]float32) (*svgGradient, error) {
+	g := &svgGradient{
+		radial: n.XMLName.Local == "radialGradient",
+		spread: n.attr("spreadMethod"),
+	}
+	if g.radial && n.attr("r") == "0" || !g.radial && n.attr("x2") == "0" {
+		return nil, fmt.Errorf("unsupported degenerate gradient")
+	}
+
+	// The gradientTransform m maps gradient coordinates to user space. Invert
+	// it, and apply it after mapping pixels to user space.
+	m, err := parseSVGNumbers(strings.TrimSuffix(strings.TrimPrefix(n.attr("gradientTransform"), "matrix("), ")"))
+	if err != nil || len(m) != 6 {
+		return nil, fmt.Errorf("invalid gradientTransform %q", n.attr("gradientTransform"))
+	}
+	a, b, c, d, e, f := m[0], m[2], m[4], m[1], m[3], m[5]
+	det := a*e - b*d
+	ia, ib, id, ie := e/det, -b/det, -d/det, a/det
+	ic, iff := -(ia*c + ib*f), -(id*c + ie*f)
+	x0, y0 := float64(vb[0]), float64(vb[1])
+	g.pix2Grad = f64.Aff3{
+		ia, ib, ia*x0 + ib*y0 + ic,
+		id, ie, id*x0 + ie*y0 + iff,
+	}
+
+	for i := range n.Children {
+		s := &n.Children[i]
+		offset, err := strconv.ParseFloat(s.attr("offset"), 64)
+		if err != nil {
+			return nil, err
+		}
+		nc, err := parseSVGColor(s, "stop-color", "stop-opacity")
+		if err != nil {
+			return nil, err
+		}
+		r, gg, b, a := nc.RGBA()
+		g.stops = append(g.stops, svgStop{offset, color.RGBA64{uint16(r), uint16(gg), uint16(b), uint16(a)}})
+	}
+	if len(g.stops) == 0 {
+		return nil, fmt.Errorf("no gradient stops")
+	}
+	return g, nil
+}
+
+// rasterizeSVG rasterizes the SVG documents produced by DecodeToSVG that
+// have no clip paths, at one pixel per unit.
+func rasterizeSVG(src string) (*image.RGBA, error) {
+	var root svgNode
+	if err := xml.Unmarshal([]byte(src), &root); err != nil {
+		return nil, err
+	}
+	v, err := parseSVGNumbers(root.attr("viewBox"))
+	if err != nil || len(v) != 4 {
+		return nil, fmt.Errorf("invalid viewBox %q", root.attr("viewBox"))
+	}
+	vb := [4]float32{float32(v[0]), float32(v[1]), float32(v[2]), float32(v[3])}
+	w, h := int(vb[2]), int(vb[3])
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	z := vector.NewRasterizer(w, h)
+	gradients := map[string]*svgGradient{}
+	for i := range root.Children {
+		n := &root.Children[i]
+		d := ""
+		switch n.XMLName.Local {
+		case "title":
+			continue
+		case "linearGradient", "radialGradient":
+			g, err := parseSVGGradient(n, vb)
+			if err != nil {
+				return nil, err
+			}
+			gradients["url(#"+n.attr("id")+")"] = g
+			continue
+		case "rect":
+			d = fmt.Sprintf("M%s %s L%s %s L%s %s L%s %s Z",
+				n.attr("x"), n.attr("y"),
+				svgNumber(vb[0]+vb[2]), n.attr("y"),
+				svgNumber(vb[0]+vb[2]), svgNumber(vb[1]+vb[3]),
+				n.attr("x"), svgNumber(vb[1]+vb[3]))
+		case "path":
+			d = n.attr("d")
+		default:
+			return nil, fmt.Errorf("unsupported element <%s>", n.XMLName.Local)
+		}
+
+		var fill image.Image
+		if g := gradients[n.attr("fill")]; g != nil {
+			fill = g
+		} else {
+			c, err := parseSVGColor(n, "fill", "fill-opacity")
+			if err != nil {
+				return nil, err
+			}
+			fill = image.NewUniform(c)
+		}
+
+		z.Reset(w, h)
+		z.DrawOp = draw.Over
+		var args []float32
+		op := byte(0)
+		flush := func() {
+			for j := range args {
+				args[j] -= vb[j&1]
+			}
+			switch op {
+			case 'M':
+				z.MoveTo(args[0], args[1])
+			case 'L':
+				z.LineTo(args[0], args[1])
+			case 'C':
+				z.CubeTo(args[0], args[1], args[2], args[3], args[4], args[5])
+			case 'Z':
+				z.ClosePath()
+			}
+			args = args[:0]
+		}
+		for _, f := range strings.Fields(d) {
+			if f[0] >= 'A' && f[0] <= 'Z' {
+				flush()
+				op, f = f[0], f[1:]
+				if f == "" {
+					continue
+				}
+			}
+			v, err := strconv.ParseFloat(f, 32)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, float32(v))
+		}
+		flush()
+		z.Draw(dst, dst.Bounds(), fill, image.Point{})
+	}
+	return dst, nil
+}
+
+func TestDecodeToSVG(t *testing.T) {
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		svg, err := DecodeToSVG(ivgData)
+		if err != nil {
+			t.Errorf("%s: DecodeToSVG: %v", tc.filename, err)
+			continue
+		}
+		var root svgNode
+		if err := xml.Unmarshal([]byte(svg), &root); err != nil {
+			t.Errorf("%s: invalid XML: %v\n%s", tc.filename, err, svg)
+			continue
+		}
+
+		switch tc.filename {
+		case "testdata/clip":
+			if !strings.Contains(svg, "<clipPath ") {
+				t.Errorf("%s: no <clipPath> element:\n%s", tc.filename, svg)
+			}
+			continue
+		case "testdata/gradient":
+			if !strings.Contains(svg, "<linearGradient ") || !strings.Contains(svg, "<radialGradient ") {
+				t.Errorf("%s: no <linearGradient> or <radialGradient> element:\n%s", tc.filename, svg)
+			}
+		}
+
+		// Rasterizing the SVG should give the same image as rasterizing the
+		// IconVG graphic.
+		got, err := rasterizeSVG(svg)
+		if err != nil {
+			t.Errorf("%s: rasterizeSVG: %v", tc.filename, err)
+			continue
+		}
+		want := image.NewRGBA(got.Bounds())
+		var z Rasterizer
+		z.SetDstImage(want, want.Bounds(), draw.Over)
+		if err := Decode(&z, ivgData, nil); err != nil {
+			t.Errorf("%s: Decode: %v", tc.filename, err)
+			continue
+		}
+		if err := checkApproxEqual(got, want); err != nil {
+			t.Errorf("%s: %v", tc.filename, err)
+		}
+	}
+}
+
+func TestDecodeToSVGSimple(t *testing.T) {
+	var e Encoder
+	e.WriteVersion = true
+	e.Reset(Metadata{
+		ViewBox: Rectangle{Min: [2]float32{0, 0}, Max: [2]float32{16, 16}},
+		Palette: DefaultPalette,
+		Title:   "a <square>",
+	})
+	e.SetCReg(0, false, RGBAColor(color.RGBA{0x00, 0x00, 0x80, 0x80}))
+	e.AppendRect(0, 4, 4, 12, 12)
+	ivgData, err := e.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeToSVG(ivgData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 16">
+<title>a &lt;square&gt;</title>
+<path d="M4 4 L12 4 L12 12 L4 12 Z" fill="#0000ff" fill-opacity="0.5019608"/>
+</svg>
+`
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
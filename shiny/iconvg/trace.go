@@ -0,0 +1,125 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+)
+
+// TraceEventKind is the kind of a TraceEvent.
+type TraceEventKind uint8
+
+const (
+	// TraceStartPath is traced before a path is started.
+	TraceStartPath TraceEventKind = iota
+	// TraceEndPath is traced after a path is closed and ended.
+	TraceEndPath
+)
+
+func (k TraceEventKind) String() string {
+	switch k {
+	case TraceStartPath:
+		return "StartPath"
+	case TraceEndPath:
+		return "EndPath"
+	}
+	return "TraceEventKind(?)"
+}
+
+// TraceEvent is a snapshot of the decoder virtual machine's registers when
+// a path is started or ended.
+type TraceEvent struct {
+	Kind TraceEventKind
+
+	// CSel and NSel are the CSEL and NSEL selector registers, and Adj is the
+	// path's selector adjustment.
+	CSel uint8
+	NSel uint8
+	Adj  uint8
+
+	// Fill is CREG[CSEL-Adj], the color the path is filled with, resolved
+	// against the palette. It may encode a gradient instead of a flat color.
+	// Clip is whether the path is a clip path, which isn't filled.
+	Fill color.RGBA
+	Clip bool
+
+	// CReg and NReg are the color and number registers.
+	CReg [64]color.RGBA
+	NReg [64]float32
+}
+
+// Tracer is an optional interface that a Destination can implement to
+// inspect the decoder's register state, as a debugging aid. If the
+// Destination passed to Decode implements Tracer, Decode calls Trace with a
+// TraceStartPath event before each call to StartPath, and with a
+// TraceEndPath event after each call to ClosePathEndPath.
+type Tracer interface {
+	Trace(e TraceEvent)
+}
+
+// tracer is a Destination that tracks the decoder's registers, forwarding to
+// another Destination and calling its Trace method.
+type tracer struct {
+	Destination
+	t Tracer
+
+	palette  Palette
+	clipping bool
+	e        TraceEvent
+}
+
+func (t *tracer) Reset(m Metadata) {
+	t.palette = m.Palette
+	t.clipping = false
+	t.e = TraceEvent{CReg: m.Palette}
+	t.Destination.Reset(m)
+}
+
+func (t *tracer) SetCSel(cSel uint8) {
+	t.e.CSel = cSel & 0x3f
+	t.Destination.SetCSel(cSel)
+}
+
+func (t *tracer) SetNSel(nSel uint8) {
+	t.e.NSel = nSel & 0x3f
+	t.Destination.SetNSel(nSel)
+}
+
+func (t *tracer) SetCReg(adj uint8, incr bool, c Color) {
+	t.e.CReg[(t.e.CSel-adj)&0x3f] = c.Resolve(&t.palette, &t.e.CReg)
+	if incr {
+		t.e.CSel = (t.e.CSel + 1) & 0x3f
+	}
+	t.Destination.SetCReg(adj, incr, c)
+}
+
+func (t *tracer) SetNReg(adj uint8, incr bool, f float32) {
+	t.e.NReg[(t.e.NSel-adj)&0x3f] = f
+	if incr {
+		t.e.NSel = (t.e.NSel + 1) & 0x3f
+	}
+	t.Destination.SetNReg(adj, incr, f)
+}
+
+func (t *tracer) PushClip() {
+	t.clipping = true
+	t.Destination.PushClip()
+}
+
+func (t *tracer) StartPath(adj uint8, x, y float32) {
+	t.e.Kind = TraceStartPath
+	t.e.Adj = adj
+	t.e.Fill = t.e.CReg[(t.e.CSel-adj)&0x3f]
+	t.e.Clip = t.clipping
+	t.t.Trace(t.e)
+	t.Destination.StartPath(adj, x, y)
+}
+
+func (t *tracer) ClosePathEndPath() {
+	t.Destination.ClosePathEndPath()
+	t.clipping = false
+	t.e.Kind = TraceEndPath
+	t.t.Trace(t.e)
+}
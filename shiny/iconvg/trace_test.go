@@ -0,0 +1,67 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// traceRecorder is a Destination that records the TraceEvents passed to its
+// Trace method. It embeds an Encoder to implement the other methods.
+type traceRecorder struct {
+	Encoder
+	events []TraceEvent
+}
+
+func (r *traceRecorder) Trace(e TraceEvent) {
+	r.events = append(r.events, e)
+}
+
+func TestTraceFavicon(t *testing.T) {
+	ivgData, err := os.ReadFile(filepath.FromSlash("testdata/favicon.ivg"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	var r traceRecorder
+	if err := Decode(&r, ivgData, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+
+	var gotFills, wantFills []color.RGBA
+	for i, e := range r.events {
+		wantKind := TraceStartPath
+		if i%2 == 1 {
+			wantKind = TraceEndPath
+		}
+		if e.Kind != wantKind {
+			t.Fatalf("event #%d: got kind %v, want %v", i, e.Kind, wantKind)
+		}
+		if e.Fill != e.CReg[(e.CSel-e.Adj)&0x3f] {
+			t.Errorf("event #%d: Fill %v is not CREG[CSEL-Adj]", i, e.Fill)
+		}
+		if e.Kind == TraceStartPath {
+			gotFills = append(gotFills, e.Fill)
+		}
+	}
+	for _, data := range faviconSVGData {
+		wantFills = append(wantFills, faviconColors[data.faviconColorsIndex])
+	}
+	if !reflect.DeepEqual(gotFills, wantFills) {
+		t.Errorf("fills:\ngot  %v\nwant %v", gotFills, wantFills)
+	}
+
+	// Tracing doesn't change what the Destination sees.
+	var e Encoder
+	if err := Decode(&e, ivgData, nil); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got, want := r.Encoder.buf, e.buf; !reflect.DeepEqual(got, want) {
+		t.Errorf("traced Encoder output differs")
+	}
+}
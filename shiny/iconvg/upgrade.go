@@ -50,6 +50,12 @@ func UpgradeToFileFormatVersion1(v0 []byte, opts *UpgradeToFileFormatVersion1Opt
 	}
 	v1 = append(v1, "\x8AIVG"...)
 	v0 = v0[4:]
+	if len(v0) > 0 && v0[0]&versionIndicator != 0 {
+		if u.version = v0[0] &^ versionIndicator; u.version > version {
+			return nil, &versionError{u.version}
+		}
+		v0 = v0[1:]
+	}
 
 	v1, v0, retErr = u.upgradeMetadata(v1, v0)
 	if retErr != nil {
@@ -74,6 +80,9 @@ const (
 type upgrader struct {
 	opts UpgradeToFileFormatVersion1Options
 
+	// version is the version of the FFV0 graphic, from its version indicator.
+	version byte
+
 	// These fields hold the current path's geometry.
 	verbs []uint8
 	args  [][2]float32
@@ -139,6 +148,19 @@ func (u *upgrader) upgradeMetadataChunk(v0 buffer) (v1 buffer, retErr error) {
 		mid = ffv1MIDViewBox
 	case midSuggestedPalette:
 		mid = ffv1MIDSuggestedPalette
+	case midTitle:
+		// FFV1 has no equivalent of the title.
+		return nil, errUnsupportedUpgrade
+	case midPalettes:
+		// FFV1 has no equivalent of the palette variants.
+		return nil, errUnsupportedUpgrade
+	case midBackground:
+		// FFV1 has no equivalent of the background.
+		return nil, errUnsupportedUpgrade
+	case midGlyphs:
+		// FFV1 has no equivalent of the glyph table, and upgrading changes
+		// the opcodes' byte offsets anyway.
+		return nil, errUnsupportedUpgrade
 	default:
 		return nil, errInvalidMetadataIdentifier
 	}
@@ -327,6 +349,10 @@ func upgradeStyling(u *upgrader, v1 buffer, v0 buffer) (uf upgradeFunc, newV1 bu
 			v1.encodeCoordinateFFV1(lod[1])
 			v1 = append(v1, ifTrue...)
 
+		case (opcode == 0xc8 || opcode == 0xc9) && u.version >= clipVersion: // "Push clip" or "Pop clip".
+			// FFV1 has no equivalent of clip paths.
+			return nil, nil, nil, errUnsupportedUpgrade
+
 		default:
 			return nil, nil, nil, errUnsupportedStylingOpcode
 		}
@@ -838,141 +864,33 @@ func (u *upgrader) upgradeArcs(pen *[2]float32, v1 buffer, v0 buffer) (newV1 buf
 }
 
 func (u *upgrader) upgradeArc(pen *[2]float32, rx, ry, xAxisRotation float32, largeArc, sweep bool, finalX, finalY float32) {
-	// We follow the "Conversion from endpoint to center parameterization"
-	// algorithm as per
-	// https://www.w3.org/TR/SVG/implnote.html#ArcConversionEndpointToCenter
-
-	// There seems to be a bug in the spec's "implementation notes".
-	//
-	// Actual implementations, such as
-	//	- https://git.gnome.org/browse/librsvg/tree/rsvg-path.c
-	//	- http://svn.apache.org/repos/asf/xmlgraphics/batik/branches/svg11/sources/org/apache/batik/ext/awt/geom/ExtendedGeneralPath.java
-	//	- https://java.net/projects/svgsalamander/sources/svn/content/trunk/svg-core/src/main/java/com/kitfox/svg/pathcmd/Arc.java
-	//	- https://github.com/millermedeiros/SVGParser/blob/master/com/millermedeiros/geom/SVGArc.as
-	// do something slightly different (marked with a †).
-
-	// (†) The Abs isn't part of the spec. Neither is checking that Rx and Ry
-	// are non-zero (and non-NaN).
-	Rx := math.Abs(float64(rx))
-	Ry := math.Abs(float64(ry))
-	if !(Rx > 0 && Ry > 0) {
+	var buf [4][6]float32
+	segs := appendArcBeziers(buf[:0], pen[0], pen[1], rx, ry, xAxisRotation, largeArc, sweep, finalX, finalY)
+	if len(segs) == 0 {
 		u.verbs = append(u.verbs, upgradeVerbLineTo)
 		u.args = append(u.args, [2]float32{finalX, finalY})
 		return
 	}
-
-	x1 := float64(pen[0])
-	y1 := float64(pen[1])
-	x2 := float64(finalX)
-	y2 := float64(finalY)
-
-	phi := 2 * math.Pi * float64(xAxisRotation)
-
-	// Step 1: Compute (x1′, y1′)
-	halfDx := (x1 - x2) / 2
-	halfDy := (y1 - y2) / 2
-	cosPhi := math.Cos(phi)
-	sinPhi := math.Sin(phi)
-	x1Prime := +cosPhi*halfDx + sinPhi*halfDy
-	y1Prime := -sinPhi*halfDx + cosPhi*halfDy
-
-	// Step 2: Compute (cx′, cy′)
-	rxSq := Rx * Rx
-	rySq := Ry * Ry
-	x1PrimeSq := x1Prime * x1Prime
-	y1PrimeSq := y1Prime * y1Prime
-
-	// (†) Check that the radii are large enough.
-	radiiCheck := x1PrimeSq/rxSq + y1PrimeSq/rySq
-	if radiiCheck > 1 {
-		c := math.Sqrt(radiiCheck)
-		Rx *= c
-		Ry *= c
-		rxSq = Rx * Rx
-		rySq = Ry * Ry
-	}
-
-	denom := rxSq*y1PrimeSq + rySq*x1PrimeSq
-	step2 := 0.0
-	if a := rxSq*rySq/denom - 1; a > 0 {
-		step2 = math.Sqrt(a)
-	}
-	if largeArc == sweep {
-		step2 = -step2
-	}
-	cxPrime := +step2 * Rx * y1Prime / Ry
-	cyPrime := -step2 * Ry * x1Prime / Rx
-
-	// Step 3: Compute (cx, cy) from (cx′, cy′)
-	cx := +cosPhi*cxPrime - sinPhi*cyPrime + (x1+x2)/2
-	cy := +sinPhi*cxPrime + cosPhi*cyPrime + (y1+y2)/2
-
-	// Step 4: Compute θ1 and Δθ
-	ax := (+x1Prime - cxPrime) / Rx
-	ay := (+y1Prime - cyPrime) / Ry
-	bx := (-x1Prime - cxPrime) / Rx
-	by := (-y1Prime - cyPrime) / Ry
-	theta1 := angle(1, 0, ax, ay)
-	deltaTheta := angle(ax, ay, bx, by)
-	if sweep {
-		if deltaTheta < 0 {
-			deltaTheta += 2 * math.Pi
-		}
-	} else {
-		if deltaTheta > 0 {
-			deltaTheta -= 2 * math.Pi
-		}
-	}
-
-	// This ends the
-	// https://www.w3.org/TR/SVG/implnote.html#ArcConversionEndpointToCenter
-	// algorithm. What follows below is specific to this implementation.
-
-	// We approximate an arc by one or more cubic Bézier curves.
-	n := int(math.Ceil(math.Abs(deltaTheta) / (math.Pi/2 + 0.001)))
-	for i := 0; i < n; i++ {
-		u.arcSegmentTo(cx, cy,
-			theta1+deltaTheta*float64(i+0)/float64(n),
-			theta1+deltaTheta*float64(i+1)/float64(n),
-			Rx, Ry, cosPhi, sinPhi,
+	highResolutionCoordinates := u.opts.ArcsExpandWithHighResolutionCoordinates
+	for _, c := range segs {
+		u.verbs = append(u.verbs, upgradeVerbCubeTo)
+		u.args = append(u.args,
+			[2]float32{
+				quantize(c[0], highResolutionCoordinates),
+				quantize(c[1], highResolutionCoordinates),
+			},
+			[2]float32{
+				quantize(c[2], highResolutionCoordinates),
+				quantize(c[3], highResolutionCoordinates),
+			},
+			[2]float32{
+				quantize(c[4], highResolutionCoordinates),
+				quantize(c[5], highResolutionCoordinates),
+			},
 		)
 	}
 }
 
-// arcSegmentTo approximates an arc by a cubic Bézier curve. The mathematical
-// formulae for the control points are the same as that used by librsvg.
-func (u *upgrader) arcSegmentTo(cx, cy, theta1, theta2, rx, ry, cosPhi, sinPhi float64) {
-	halfDeltaTheta := (theta2 - theta1) * 0.5
-	q := math.Sin(halfDeltaTheta * 0.5)
-	t := (8 * q * q) / (3 * math.Sin(halfDeltaTheta))
-	cos1 := math.Cos(theta1)
-	sin1 := math.Sin(theta1)
-	cos2 := math.Cos(theta2)
-	sin2 := math.Sin(theta2)
-	x1 := rx * (+cos1 - t*sin1)
-	y1 := ry * (+sin1 + t*cos1)
-	x2 := rx * (+cos2 + t*sin2)
-	y2 := ry * (+sin2 - t*cos2)
-	x3 := rx * (+cos2)
-	y3 := ry * (+sin2)
-	highResolutionCoordinates := u.opts.ArcsExpandWithHighResolutionCoordinates
-	u.verbs = append(u.verbs, upgradeVerbCubeTo)
-	u.args = append(u.args,
-		[2]float32{
-			quantize(float32(cx+cosPhi*x1-sinPhi*y1), highResolutionCoordinates),
-			quantize(float32(cy+sinPhi*x1+cosPhi*y1), highResolutionCoordinates),
-		},
-		[2]float32{
-			quantize(float32(cx+cosPhi*x2-sinPhi*y2), highResolutionCoordinates),
-			quantize(float32(cy+sinPhi*x2+cosPhi*y2), highResolutionCoordinates),
-		},
-		[2]float32{
-			quantize(float32(cx+cosPhi*x3-sinPhi*y3), highResolutionCoordinates),
-			quantize(float32(cy+sinPhi*x3+cosPhi*y3), highResolutionCoordinates),
-		},
-	)
-}
-
 func countFFV1Instructions(src buffer) (ret uint64) {
 	for len(src) > 0 {
 		ret++
@@ -19,6 +19,13 @@ func TestUpgradeToFileFormatVersion1(t *testing.T) {
 		}
 
 		upgraded, err := UpgradeToFileFormatVersion1(original, nil)
+		if tc.filename == "testdata/clip" {
+			// FFV1 has no equivalent of clip paths.
+			if err != errUnsupportedUpgrade {
+				t.Errorf("%s: Upgrade: got %v, want %v", tc.filename, err, errUnsupportedUpgrade)
+			}
+			continue
+		}
 		if err != nil {
 			t.Errorf("%s: Upgrade: %v", tc.filename, err)
 			continue
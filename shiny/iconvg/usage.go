@@ -0,0 +1,101 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+// PaletteUsage returns which entries of the custom palette the IconVG graphic
+// src refers to, without rendering it. An application that lets users theme
+// icons can use this to show only the relevant palette entries.
+//
+// An entry is used if a styling opcode sets a color register to that entry of
+// the custom palette, either directly or as one side of a blend. An entry is
+// also used if a color register is read, by a blend or by a path, before it
+// is set, since the color registers start out holding the custom palette. The
+// color registers read as gradient stops, or by clip paths, are not
+// considered.
+func PaletteUsage(src []byte) (used [64]bool, err error) {
+	u := paletteUser{}
+	if err := Decode(&u, src, nil); err != nil {
+		return [64]bool{}, err
+	}
+	return u.used, nil
+}
+
+// paletteUser is a Destination that records the custom palette entries that
+// are referred to.
+type paletteUser struct {
+	used     [64]bool
+	cSel     uint8
+	cRegOK   [64]bool // whether the color register has been set
+	clipping bool     // whether the current path is a clip path
+}
+
+func (u *paletteUser) Reset(m Metadata) {
+	*u = paletteUser{}
+}
+
+// useColor records the palette entries that c refers to.
+func (u *paletteUser) useColor(c Color) {
+	switch c.typ {
+	case ColorTypePaletteIndex:
+		u.used[c.paletteIndex()&0x3f] = true
+	case ColorTypeCReg:
+		u.useCReg(c.cReg() & 0x3f)
+	case ColorTypeBlend:
+		_, c0, c1 := c.blend()
+		u.useColor(decodeColor1(c0))
+		u.useColor(decodeColor1(c1))
+	}
+}
+
+// useCReg records that the color register with the given index is read.
+func (u *paletteUser) useCReg(i uint8) {
+	if !u.cRegOK[i] {
+		u.used[i] = true
+	}
+}
+
+func (u *paletteUser) SetCSel(cSel uint8) { u.cSel = cSel & 0x3f }
+func (u *paletteUser) SetNSel(nSel uint8) {}
+
+func (u *paletteUser) SetCReg(adj uint8, incr bool, c Color) {
+	u.useColor(c)
+	u.cRegOK[(u.cSel-adj)&0x3f] = true
+	if incr {
+		u.cSel++
+	}
+}
+
+func (u *paletteUser) SetNReg(adj uint8, incr bool, f float32) {}
+func (u *paletteUser) SetLOD(lod0, lod1 float32)               {}
+func (u *paletteUser) PushClip()                               { u.clipping = true }
+func (u *paletteUser) PopClip()                                {}
+
+func (u *paletteUser) StartPath(adj uint8, x, y float32) {
+	if !u.clipping {
+		u.useCReg((u.cSel - adj) & 0x3f)
+	}
+}
+
+func (u *paletteUser) ClosePathEndPath()               { u.clipping = false }
+func (u *paletteUser) ClosePathAbsMoveTo(x, y float32) {}
+func (u *paletteUser) ClosePathRelMoveTo(x, y float32) {}
+
+func (u *paletteUser) AbsHLineTo(x float32)                   {}
+func (u *paletteUser) RelHLineTo(x float32)                   {}
+func (u *paletteUser) AbsVLineTo(y float32)                   {}
+func (u *paletteUser) RelVLineTo(y float32)                   {}
+func (u *paletteUser) AbsLineTo(x, y float32)                 {}
+func (u *paletteUser) RelLineTo(x, y float32)                 {}
+func (u *paletteUser) AbsSmoothQuadTo(x, y float32)           {}
+func (u *paletteUser) RelSmoothQuadTo(x, y float32)           {}
+func (u *paletteUser) AbsQuadTo(x1, y1, x, y float32)         {}
+func (u *paletteUser) RelQuadTo(x1, y1, x, y float32)         {}
+func (u *paletteUser) AbsSmoothCubeTo(x2, y2, x, y float32)   {}
+func (u *paletteUser) RelSmoothCubeTo(x2, y2, x, y float32)   {}
+func (u *paletteUser) AbsCubeTo(x1, y1, x2, y2, x, y float32) {}
+func (u *paletteUser) RelCubeTo(x1, y1, x2, y2, x, y float32) {}
+
+func (u *paletteUser) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {}
+func (u *paletteUser) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {}
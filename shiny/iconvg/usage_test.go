@@ -0,0 +1,72 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// usedIndexes returns the indexes of the true elements of used.
+func usedIndexes(used [64]bool) []int {
+	var s []int
+	for i, u := range used {
+		if u {
+			s = append(s, i)
+		}
+	}
+	return s
+}
+
+func TestPaletteUsageFavicon(t *testing.T) {
+	ivgData, err := os.ReadFile(filepath.FromSlash("testdata/favicon.ivg"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	used, err := PaletteUsage(ivgData)
+	if err != nil {
+		t.Fatalf("PaletteUsage: %v", err)
+	}
+	if got, want := usedIndexes(used), []int{0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPaletteUsage(t *testing.T) {
+	var e Encoder
+	e.Reset(Metadata{ViewBox: DefaultViewBox, Palette: DefaultPalette})
+	// Set CREG[0] to the custom palette's 5th entry, and CREG[1] to a blend of
+	// the 3rd entry and CREG[2], which still holds the 2nd entry.
+	e.SetCReg(0, true, PaletteIndexColor(5))
+	e.SetCReg(0, true, BlendColor(0x80, 0x83, 0xc2))
+	// Set CREG[2] to a direct color, then blend with it. That blend does not
+	// use the custom palette.
+	e.SetCReg(0, true, RGBAColor(color.RGBA{0x12, 0x34, 0x56, 0xff}))
+	e.SetCReg(0, false, BlendColor(0x80, 0x7f, 0xc2))
+	// Fill paths with CREG[1], which was set, and CREG[10], which was not.
+	e.SetCSel(2)
+	e.AppendRect(1, -8, -8, 8, 8)
+	e.SetCSel(12)
+	e.AppendRect(2, -8, -8, 8, 8)
+	ivgData, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	used, err := PaletteUsage(ivgData)
+	if err != nil {
+		t.Fatalf("PaletteUsage: %v", err)
+	}
+	if got, want := usedIndexes(used), []int{2, 3, 5, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := PaletteUsage(ivgData[:3]); err == nil {
+		t.Error("truncated magic: got nil error")
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+)
+
+var (
+	errInvalidGradientStopColor  = errors.New("iconvg: invalid gradient stop color")
+	errInvalidGradientStopOffset = errors.New("iconvg: invalid gradient stop offset")
+	errInvalidPathColor          = errors.New("iconvg: invalid path color")
+	errPopClipWithoutPushClip    = errors.New("iconvg: pop clip without push clip")
+	errUnterminatedPath          = errors.New("iconvg: unterminated path")
+)
+
+// offsetError is an error found at an offset of an IconVG graphic.
+type offsetError struct {
+	err    error
+	offset int
+}
+
+func (e *offsetError) Error() string { return fmt.Sprintf("%v at offset %d", e.err, e.offset) }
+func (e *offsetError) Unwrap() error { return e.err }
+
+// Validate checks that src is a valid IconVG graphic, without rendering it.
+//
+// Besides the errors that Decode reports, such as reserved opcodes and
+// truncated data, Validate checks the colors that paths are filled with. A
+// path's color must either be a valid alpha-premultiplied color or describe
+// a gradient. A gradient must not have too many stops to fit in the
+// registers, must not use its own color register as a stop, and its stops
+// must have valid alpha-premultiplied colors and strictly increasing offsets
+// between 0 and 1. A clip path's color is ignored. Each pop clip must match
+// an earlier push clip, and a graphic must not end in the middle of a path.
+//
+// Validate returns the first violation found. Violations after the metadata
+// are reported with the offset of the opcode at fault.
+func Validate(src []byte) error {
+	var v validator
+	err := Decode(&v, src, nil)
+	switch {
+	case v.err != nil:
+		return v.err
+	case err != nil:
+		if _, ok := err.(*reservedOpcodeError); ok || !v.started {
+			return err
+		}
+		return &offsetError{err, v.offset}
+	case v.inPath:
+		return &offsetError{errUnterminatedPath, len(src)}
+	}
+	return nil
+}
+
+// validator is a Destination that tracks the color and number registers
+// and records the first invalid use of them.
+type validator struct {
+	// offset is the offset of the opcode being decoded. It is set by
+	// startOpcode.
+	offset int
+
+	err       error
+	started   bool
+	inPath    bool
+	clipping  bool
+	clipDepth int
+
+	palette Palette
+	cSel    uint8
+	nSel    uint8
+	cReg    [64]color.RGBA
+	nReg    [64]float32
+}
+
+func (v *validator) Reset(m Metadata) {
+	*v = validator{
+		offset:  v.offset,
+		started: true,
+		palette: m.Palette,
+		cReg:    m.Palette,
+	}
+}
+
+func (v *validator) startOpcode(offset int) { v.offset = offset }
+func (v *validator) endOpcode() error       { return nil }
+
+func (v *validator) SetCSel(cSel uint8) { v.cSel = cSel & 0x3f }
+func (v *validator) SetNSel(nSel uint8) { v.nSel = nSel & 0x3f }
+
+func (v *validator) SetCReg(adj uint8, incr bool, c Color) {
+	v.cReg[(v.cSel-adj)&0x3f] = c.Resolve(&v.palette, &v.cReg)
+	if incr {
+		v.cSel++
+	}
+}
+
+func (v *validator) SetNReg(adj uint8, incr bool, f float32) {
+	v.nReg[(v.nSel-adj)&0x3f] = f
+	if incr {
+		v.nSel++
+	}
+}
+
+func (v *validator) SetLOD(lod0, lod1 float32) {}
+
+func (v *validator) PushClip() {
+	v.clipDepth++
+	v.clipping = true
+}
+
+func (v *validator) PopClip() {
+	if v.clipDepth == 0 {
+		v.fail(errPopClipWithoutPushClip)
+		return
+	}
+	v.clipDepth--
+}
+
+func (v *validator) fail(err error) {
+	if v.err == nil {
+		v.err = &offsetError{err, v.offset}
+	}
+}
+
+func (v *validator) StartPath(adj uint8, x, y float32) {
+	v.inPath = true
+	if v.clipping {
+		return
+	}
+	reg := (v.cSel - adj) & 0x3f
+	c := v.cReg[reg]
+	if validAlphaPremulColor(c) {
+		return
+	}
+	if c.A != 0x00 || c.B&0x80 == 0 {
+		v.fail(errInvalidPathColor)
+		return
+	}
+
+	// The color describes a gradient. See Rasterizer.initGradient.
+	nStops := c.R & 0x3f
+	cBase := c.G & 0x3f
+	nBase := c.B & 0x3f
+	if int(nStops) > 64-6 {
+		v.fail(errTooManyGradientStops)
+		return
+	}
+	prevN := negativeInfinity
+	for i := uint8(0); i < nStops; i++ {
+		if (cBase+i)&0x3f == reg {
+			v.fail(errCSELUsedAsBothGradientAndStop)
+			return
+		}
+		if !validAlphaPremulColor(v.cReg[(cBase+i)&0x3f]) {
+			v.fail(errInvalidGradientStopColor)
+			return
+		}
+		n := v.nReg[(nBase+i)&0x3f]
+		if !(0 <= n && n <= 1) || !(n > prevN) {
+			v.fail(errInvalidGradientStopOffset)
+			return
+		}
+		prevN = n
+	}
+}
+
+func (v *validator) ClosePathEndPath() {
+	v.inPath = false
+	v.clipping = false
+}
+
+func (v *validator) ClosePathAbsMoveTo(x, y float32) {}
+func (v *validator) ClosePathRelMoveTo(x, y float32) {}
+
+func (v *validator) AbsHLineTo(x float32)                   {}
+func (v *validator) RelHLineTo(x float32)                   {}
+func (v *validator) AbsVLineTo(y float32)                   {}
+func (v *validator) RelVLineTo(y float32)                   {}
+func (v *validator) AbsLineTo(x, y float32)                 {}
+func (v *validator) RelLineTo(x, y float32)                 {}
+func (v *validator) AbsSmoothQuadTo(x, y float32)           {}
+func (v *validator) RelSmoothQuadTo(x, y float32)           {}
+func (v *validator) AbsQuadTo(x1, y1, x, y float32)         {}
+func (v *validator) RelQuadTo(x1, y1, x, y float32)         {}
+func (v *validator) AbsSmoothCubeTo(x2, y2, x, y float32)   {}
+func (v *validator) RelSmoothCubeTo(x2, y2, x, y float32)   {}
+func (v *validator) AbsCubeTo(x1, y1, x2, y2, x, y float32) {}
+func (v *validator) RelCubeTo(x1, y1, x2, y2, x, y float32) {}
+
+func (v *validator) AbsArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {}
+func (v *validator) RelArcTo(rx, ry, xAxisRotation float32, largeArc, sweep bool, x, y float32) {}
@@ -0,0 +1,197 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package iconvg
+
+import (
+	"errors"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateTestdata(t *testing.T) {
+	for _, tc := range testdataTestCases {
+		ivgData, err := os.ReadFile(filepath.FromSlash(tc.filename) + ".ivg")
+		if err != nil {
+			t.Errorf("%s: ReadFile: %v", tc.filename, err)
+			continue
+		}
+		if err := Validate(ivgData); err != nil {
+			t.Errorf("%s: Validate: %v", tc.filename, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	stops := []GradientStop{
+		{Offset: 0.0, Color: color.RGBA{0xff, 0x00, 0x00, 0xff}},
+		{Offset: 1.0, Color: color.RGBA{0x00, 0x00, 0xff, 0xff}},
+	}
+
+	testCases := []struct {
+		desc string
+		// style sets up the color of a square path.
+		style   func(e *Encoder)
+		wantErr error
+	}{{
+		desc: "solid",
+		style: func(e *Encoder) {
+			e.SetCReg(0, false, RGBAColor(color.RGBA{0x80, 0x00, 0x00, 0x80}))
+		},
+	}, {
+		desc: "gradient",
+		style: func(e *Encoder) {
+			e.SetLinearGradient(10, 10, -8, 0, +8, 0, GradientSpreadNone, stops)
+		},
+	}, {
+		desc: "not alpha-premultiplied",
+		style: func(e *Encoder) {
+			e.SetCReg(0, false, RGBAColor(color.RGBA{0xff, 0x00, 0x00, 0x80}))
+		},
+		wantErr: errInvalidPathColor,
+	}, {
+		desc: "stop offsets out of order",
+		style: func(e *Encoder) {
+			e.SetLinearGradient(10, 10, -8, 0, +8, 0, GradientSpreadNone, []GradientStop{stops[1], stops[0]})
+		},
+		wantErr: errInvalidGradientStopOffset,
+	}, {
+		desc: "stop offset out of range",
+		style: func(e *Encoder) {
+			e.SetLinearGradient(10, 10, -8, 0, +8, 0, GradientSpreadNone, []GradientStop{
+				{Offset: 0.0, Color: color.RGBA{0xff, 0x00, 0x00, 0xff}},
+				{Offset: 1.5, Color: color.RGBA{0x00, 0x00, 0xff, 0xff}},
+			})
+		},
+		wantErr: errInvalidGradientStopOffset,
+	}, {
+		desc: "stop color not alpha-premultiplied",
+		style: func(e *Encoder) {
+			e.SetLinearGradient(10, 10, -8, 0, +8, 0, GradientSpreadNone, []GradientStop{
+				{Offset: 0.0, Color: color.RGBA{0xff, 0x00, 0x00, 0x80}},
+				{Offset: 1.0, Color: color.RGBA{0x00, 0x00, 0xff, 0xff}},
+			})
+		},
+		wantErr: errInvalidGradientStopColor,
+	}, {
+		desc: "gradient is its own stop",
+		style: func(e *Encoder) {
+			// One stop, at CREG[0], and offsets from NREG[10].
+			e.SetCReg(0, false, RGBAColor(color.RGBA{0x01, 0x00, 0x8a, 0x00}))
+		},
+		wantErr: errCSELUsedAsBothGradientAndStop,
+	}, {
+		desc: "too many stops",
+		style: func(e *Encoder) {
+			e.SetCReg(0, false, RGBAColor(color.RGBA{0x3f, 0x01, 0x8a, 0x00}))
+		},
+		wantErr: errTooManyGradientStops,
+	}, {
+		desc: "clip path color is ignored",
+		style: func(e *Encoder) {
+			e.WriteVersion = true
+			e.SetCReg(0, false, RGBAColor(color.RGBA{0xff, 0x00, 0x00, 0x80}))
+			e.PushClip()
+		},
+	}}
+
+	for _, tc := range testCases {
+		var e Encoder
+		tc.style(&e)
+		prefix, err := e.Bytes()
+		if err != nil {
+			t.Errorf("%s: encoding: %v", tc.desc, err)
+			continue
+		}
+		// The path starts after the styling opcodes.
+		wantOffset := len(prefix)
+		e.AppendRect(0, -8, -8, +8, +8)
+		src, err := e.Bytes()
+		if err != nil {
+			t.Errorf("%s: encoding: %v", tc.desc, err)
+			continue
+		}
+
+		err = Validate(src)
+		if tc.wantErr == nil {
+			if err != nil {
+				t.Errorf("%s: Validate: %v", tc.desc, err)
+			}
+			continue
+		}
+		oe, ok := err.(*offsetError)
+		if !ok || !errors.Is(err, tc.wantErr) || oe.offset != wantOffset {
+			t.Errorf("%s: got %v, want %v at offset %d", tc.desc, err, tc.wantErr, wantOffset)
+		}
+	}
+}
+
+func TestValidatePopClip(t *testing.T) {
+	e := Encoder{WriteVersion: true}
+	e.PushClip()
+	e.AppendRect(0, -8, -8, +8, +8)
+	e.PopClip()
+	wantOffset := len(e.buf)
+	e.PopClip()
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	err = Validate(src)
+	if oe, ok := err.(*offsetError); !ok || !errors.Is(err, errPopClipWithoutPushClip) || oe.offset != wantOffset {
+		t.Errorf("got %v, want %v at offset %d", err, errPopClipWithoutPushClip, wantOffset)
+	}
+}
+
+func TestValidateWithinLimiter(t *testing.T) {
+	e := Encoder{WriteVersion: true}
+	e.PushClip()
+	e.AppendRect(0, -8, -8, +8, +8)
+	e.PopClip()
+	wantOffset := len(e.buf)
+	e.PopClip()
+	src, err := e.Bytes()
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+	// The limiter passes the opcode offsets on to the validator that it
+	// wraps.
+	var v validator
+	if err := Decode(&v, src, &DecodeOptions{MaxPaths: 10}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if oe, ok := v.err.(*offsetError); !ok || !errors.Is(v.err, errPopClipWithoutPushClip) || oe.offset != wantOffset {
+		t.Errorf("got %v, want %v at offset %d", v.err, errPopClipWithoutPushClip, wantOffset)
+	}
+}
+
+func TestValidateTruncated(t *testing.T) {
+	src, err := os.ReadFile(filepath.FromSlash("testdata/action-info.lores.ivg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cut the graphic short, in the middle of the arguments of the first
+	// "C (absolute cubeTo)" opcode, which starts at offset 14.
+	err = Validate(src[:16])
+	if !errors.Is(err, errInvalidNumber) {
+		t.Fatalf("got %v, want %v", err, errInvalidNumber)
+	}
+	if want := "iconvg: invalid number at offset 14"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+
+	// A graphic must not end in the middle of a path.
+	n := len(src) - 1
+	err = Validate(src[:n])
+	if oe, ok := err.(*offsetError); !ok || oe.err != errUnterminatedPath || oe.offset != n {
+		t.Errorf("got %v, want %v at offset %d", err, errUnterminatedPath, n)
+	}
+
+	// Errors in the metadata have no offset.
+	if err := Validate(src[:6]); err != errInvalidMetadataIdentifier {
+		t.Errorf("truncated metadata: got %v, want %v", err, errInvalidMetadataIdentifier)
+	}
+}
@@ -7,6 +7,10 @@ package imageutil
 
 import (
 	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/iconvg"
 )
 
 // TODO: move Border into the standard library's package image?
@@ -99,3 +103,15 @@ func Border(r image.Rectangle, inset int) [4]image.Rectangle {
 		},
 	}}
 }
+
+// DrawIconVG decodes the IconVG graphic src and composites it onto dst, using
+// the Over operator. The graphic is scaled to fit the rectangle r.
+//
+// The palette is optional. If nil, the graphic's suggested palette is used.
+func DrawIconVG(dst *image.RGBA, r image.Rectangle, src []byte, palette *[64]color.RGBA) error {
+	var z iconvg.Rasterizer
+	z.SetDstImage(dst, r, draw.Over)
+	return iconvg.Decode(&z, src, &iconvg.DecodeOptions{
+		Palette: (*iconvg.Palette)(palette),
+	})
+}
@@ -6,7 +6,12 @@ package imageutil
 
 import (
 	"image"
+	"image/color"
+	"image/draw"
 	"testing"
+
+	"golang.org/x/exp/shiny/iconvg"
+	"golang.org/x/exp/shiny/materialdesign/icons"
 )
 
 func area(r image.Rectangle) int {
@@ -72,3 +77,42 @@ func TestBorder(t *testing.T) {
 		}
 	}
 }
+
+func TestDrawIconVG(t *testing.T) {
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	black := color.RGBA{0x00, 0x00, 0x00, 0xff}
+
+	redPalette := [64]color.RGBA(iconvg.DefaultPalette)
+	redPalette[0] = red
+
+	for _, palette := range []*[64]color.RGBA{nil, &redPalette} {
+		dst := image.NewRGBA(image.Rect(0, 0, 64, 64))
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(white), image.Point{}, draw.Src)
+
+		// The info icon is a filled circle with an "i" cut out of it.
+		r := image.Rect(8, 8, 56, 56)
+		if err := DrawIconVG(dst, r, icons.ActionInfo, palette); err != nil {
+			t.Fatalf("DrawIconVG: %v", err)
+		}
+
+		fill := black
+		if palette != nil {
+			fill = red
+		}
+		testCases := []struct {
+			p    image.Point
+			want color.RGBA
+		}{
+			{image.Pt(4, 4), white},   // Outside r.
+			{image.Pt(8, 8), white},   // The icon's transparent corner.
+			{image.Pt(18, 32), fill},  // The circle.
+			{image.Pt(32, 32), white}, // The "i".
+		}
+		for _, tc := range testCases {
+			if got := dst.RGBAAt(tc.p.X, tc.p.Y); got != tc.want {
+				t.Errorf("palette=%t: at %v: got %v, want %v", palette != nil, tc.p, got, tc.want)
+			}
+		}
+	}
+}
@@ -55,9 +55,11 @@
 package screen // import "golang.org/x/exp/shiny/screen"
 
 import (
+	"errors"
 	"image"
 	"image/color"
 	"image/draw"
+	"time"
 	"unicode/utf8"
 
 	"golang.org/x/image/math/f64"
@@ -170,6 +172,29 @@ type Texture interface {
 	// interfaces??
 }
 
+// NewTextureFromImage returns a new Texture for the Screen s, the same size as
+// src and holding a copy of its pixels. It is equivalent to creating a Buffer,
+// drawing src onto it, uploading it to a new Texture and releasing the Buffer.
+//
+// As with any Texture, the caller is responsible for releasing it. Later
+// changes to src do not affect the Texture.
+func NewTextureFromImage(s Screen, src image.Image) (Texture, error) {
+	size := src.Bounds().Size()
+	b, err := s.NewBuffer(size)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Release()
+	draw.Draw(b.RGBA(), b.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	t, err := s.NewTexture(size)
+	if err != nil {
+		return nil, err
+	}
+	t.Upload(image.Point{}, b, b.Bounds())
+	return t, nil
+}
+
 // EventDeque is an infinitely buffered double-ended queue of events.
 type EventDeque interface {
 	// Send adds an event to the end of the deque. They are returned by
@@ -217,13 +242,63 @@ type Window interface {
 	// Publish flushes any pending Upload and Draw calls to the window, and
 	// swaps the back buffer to the front.
 	Publish() PublishResult
+
+	// Screenshot returns a copy of the window's contents as of the most
+	// recent Publish. It returns an error wrapping ErrUnsupported if the
+	// driver cannot read back the window's contents.
+	//
+	// Depending on the driver, the copy may also include drawing done since
+	// the most recent Publish, and the parts of the window that are obscured
+	// by other windows or are off screen may be undefined.
+	Screenshot() (*image.RGBA, error)
+
+	// SetCursor sets the mouse cursor that is shown when the pointer is over
+	// the window. A driver that cannot show a particular cursor shows
+	// CursorDefault instead, and a driver that cannot change the cursor at
+	// all ignores the call.
+	SetCursor(c Cursor)
 }
 
+// Cursor is a standard mouse cursor. The exact appearance of each cursor
+// depends on the platform.
+type Cursor uint8
+
+const (
+	// CursorDefault is the platform's default cursor, typically an arrow.
+	CursorDefault Cursor = iota
+	// CursorPointer is a pointing hand, typically used over links and
+	// buttons.
+	CursorPointer
+	// CursorText is an I-beam, typically used over text that can be
+	// selected or edited.
+	CursorText
+	// CursorCrosshair is a crosshair, typically used for precise selection,
+	// such as when drawing.
+	CursorCrosshair
+	// CursorNone hides the cursor while the pointer is over the window.
+	CursorNone
+)
+
+// ErrUnsupported is returned, possibly wrapped, by optional operations that
+// a driver does not support.
+var ErrUnsupported = errors.New("screen: unsupported operation")
+
 // PublishResult is the result of an Window.Publish call.
 type PublishResult struct {
 	// BackBufferPreserved is whether the contents of the back buffer was
 	// preserved. If false, the contents are undefined.
 	BackBufferPreserved bool
+
+	// PresentTime is when the published frame was shown on the screen or,
+	// if the driver cannot tell, its estimate of the next vertical sync after
+	// which the frame will be shown. Animation loops can use it to pace
+	// themselves, computing each frame's contents from the time it will be
+	// seen instead of from how many frames have been drawn.
+	//
+	// It is best effort: the accuracy depends on the driver, the graphics
+	// hardware and whether the window is visible. It is the zero Time if the
+	// driver cannot provide it.
+	PresentTime time.Time
 }
 
 // NewWindowOptions are optional arguments to NewWindow.
@@ -236,7 +311,7 @@ type NewWindowOptions struct {
 	// Title specifies the window title.
 	Title string
 
-	// TODO: fullscreen, icon, cursorHidden?
+	// TODO: fullscreen, icon?
 }
 
 // GetTitle returns a sanitized form of o.Title. In particular, its length will
@@ -5,6 +5,11 @@
 package screen
 
 import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
 	"testing"
 )
 
@@ -51,3 +56,102 @@ func TestSanitizeUTF8(t *testing.T) {
 		}
 	}
 }
+
+// testScreen is a Screen whose Buffers and Textures are *image.RGBA values in
+// memory. It does not support Windows.
+type testScreen struct {
+	nBuffers int // the number of unreleased Buffers
+}
+
+func (s *testScreen) NewBuffer(size image.Point) (Buffer, error) {
+	s.nBuffers++
+	return &testBuffer{s, image.NewRGBA(image.Rectangle{Max: size})}, nil
+}
+
+func (s *testScreen) NewTexture(size image.Point) (Texture, error) {
+	return &testTexture{image.NewRGBA(image.Rectangle{Max: size})}, nil
+}
+
+func (s *testScreen) NewWindow(opts *NewWindowOptions) (Window, error) {
+	return nil, errors.New("testScreen: NewWindow is not supported")
+}
+
+type testBuffer struct {
+	s *testScreen
+	m *image.RGBA
+}
+
+func (b *testBuffer) Release()                { b.s.nBuffers-- }
+func (b *testBuffer) Size() image.Point       { return b.m.Rect.Size() }
+func (b *testBuffer) Bounds() image.Rectangle { return b.m.Rect }
+func (b *testBuffer) RGBA() *image.RGBA       { return b.m }
+
+type testTexture struct {
+	m *image.RGBA
+}
+
+func (t *testTexture) Release()                { t.m = nil }
+func (t *testTexture) Size() image.Point       { return t.m.Rect.Size() }
+func (t *testTexture) Bounds() image.Rectangle { return t.m.Rect }
+
+func (t *testTexture) Upload(dp image.Point, src Buffer, sr image.Rectangle) {
+	draw.Draw(t.m, sr.Sub(sr.Min).Add(dp), src.RGBA(), sr.Min, draw.Src)
+}
+
+func (t *testTexture) Fill(dr image.Rectangle, src color.Color, op draw.Op) {
+	draw.Draw(t.m, dr, image.NewUniform(src), image.Point{}, op)
+}
+
+func TestNewTextureFromImage(t *testing.T) {
+	// The source image has a non-zero origin.
+	src := image.NewNRGBA(image.Rect(10, 20, 13, 22))
+	for y := src.Rect.Min.Y; y < src.Rect.Max.Y; y++ {
+		for x := src.Rect.Min.X; x < src.Rect.Max.X; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 0x80, 0xff})
+		}
+	}
+
+	s := &testScreen{}
+	tex, err := NewTextureFromImage(s, src)
+	if err != nil {
+		t.Fatalf("NewTextureFromImage: %v", err)
+	}
+	defer tex.Release()
+	if s.nBuffers != 0 {
+		t.Errorf("got %d unreleased Buffers, want 0", s.nBuffers)
+	}
+	if got, want := tex.Size(), (image.Point{3, 2}); got != want {
+		t.Fatalf("Size: got %v, want %v", got, want)
+	}
+	m := tex.(*testTexture).m
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 3; x++ {
+			got := m.RGBAAt(x, y)
+			want := color.RGBA{uint8(10 + x), uint8(20 + y), 0x80, 0xff}
+			if got != want {
+				t.Errorf("(%d, %d): got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+// unsupportedWindow is a Window that cannot read back its contents. Its
+// methods other than Screenshot are unimplemented.
+type unsupportedWindow struct {
+	Window
+}
+
+func (unsupportedWindow) Screenshot() (*image.RGBA, error) {
+	return nil, fmt.Errorf("testdriver: screenshot: %w", ErrUnsupported)
+}
+
+func TestScreenshotUnsupported(t *testing.T) {
+	var w Window = unsupportedWindow{}
+	m, err := w.Screenshot()
+	if m != nil {
+		t.Errorf("got image %v, want nil", m.Rect)
+	}
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("got error %v, want one wrapping ErrUnsupported", err)
+	}
+}
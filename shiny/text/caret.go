@@ -14,6 +14,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -521,20 +522,8 @@ func breakLine(f *Frame, l, b, k int32) {
 		if k == bb.i {
 			panic("TODO: degenerate split left, possibly adjusting the Line's firstB??")
 		}
-		newB, realloc := f.newBox()
-		if realloc {
-			bb = &f.boxes[b]
-		}
-		nextB := bb.next
-		if nextB != 0 {
-			f.boxes[nextB].prev = newB
-		}
-		f.boxes[newB].next = nextB
-		f.boxes[newB].prev = b
-		f.boxes[newB].i = k
-		f.boxes[newB].j = bb.j
-		bb.next = newB
-		bb.j = k
+		f.splitBox(b, k)
+		bb = &f.boxes[b]
 	}
 
 	// Assert that the break point isn't already at the start or end of the Line.
@@ -577,42 +566,122 @@ func breakLine(f *Frame, l, b, k int32) {
 // onto the next line, which will also be laid out, and so on recursively.
 func layout(f *Frame, l int32) {
 	if f.maxWidth <= 0 || f.face == nil {
+		splitFaces(f, l)
 		return
 	}
 	f.seqNum++
 
 	for ; l != 0; l = f.lines[l].next {
+		splitFaces(f, l)
 		var (
 			firstB     = f.lines[l].firstB
 			reader     = f.lineReader(firstB, f.boxes[firstB].i)
 			breakPoint bAndK
-			prevR      rune
-			prevRValid bool
-			advance    fixed.Int26_6
+			m          = measurer{f: f}
 		)
 		for {
 			r, _, err := reader.ReadRune()
 			if err != nil || r == '\n' {
 				return
 			}
-			if prevRValid {
-				advance += f.face.Kern(prevR, r)
-			}
-			// TODO: match all whitespace, not just ' '?
-			if r == ' ' {
+			// TODO: match all whitespace, not just ' ' and '\t'?
+			space := r == ' ' || r == '\t'
+			if space {
 				breakPoint = reader.bAndK()
 			}
-			a, _ := f.face.GlyphAdvance(r)
-			advance += a
-			if r != ' ' && advance > f.maxWidth && breakPoint.b != 0 {
+			m.add(r)
+			if !space && m.advance > f.maxWidth && breakPoint.b != 0 {
 				breakLine(f, l, breakPoint.b, breakPoint.k)
 				break
 			}
-			prevR, prevRValid = r, true
 		}
 	}
 }
 
+// splitFaces splits the Boxes of the Line l wherever the font face chosen by
+// the Frame's face selector changes, and records each Box's face. A rune that
+// straddles two or more Boxes uses the face of the Box that it starts in.
+func splitFaces(f *Frame, l int32) {
+	if f.faceSelector == nil {
+		for b := f.lines[l].firstB; b != 0; b = f.boxes[b].next {
+			f.boxes[b].face = nil
+		}
+		return
+	}
+	for b := f.lines[l].firstB; b != 0; b = f.boxes[b].next {
+		bb := &f.boxes[b]
+		started := false
+		for k := bb.i; k < bb.j; {
+			r, _, newB, newK := f.readRune(b, k)
+			face := f.faceFor(r)
+			if !started {
+				bb.face, started = face, true
+			} else if face != bb.face {
+				splitB := f.splitBox(b, k)
+				if newB == b {
+					newB = splitB
+				}
+				b, bb = splitB, &f.boxes[splitB]
+				bb.face = face
+				f.seqNum++
+			}
+			for b != newB {
+				b = bb.next
+				bb = &f.boxes[b]
+				bb.face = face
+			}
+			k = newK
+		}
+	}
+}
+
+// measurer accumulates the width of a Line's runes, in order, as they are laid
+// out.
+type measurer struct {
+	f          *Frame
+	prevFace   font.Face
+	prevR      rune
+	prevRValid bool
+	advance    fixed.Int26_6
+}
+
+func (m *measurer) add(r rune) {
+	if r == '\t' && m.f.tabWidth > 0 {
+		// A tab advances to the next tab stop. There is no kerning either
+		// side of it.
+		m.advance = (m.advance/m.f.tabWidth + 1) * m.f.tabWidth
+		m.prevRValid = false
+		return
+	}
+	// There is no kerning between runes of different faces.
+	face := m.f.faceFor(r)
+	if m.prevRValid && face == m.prevFace {
+		m.advance += face.Kern(m.prevR, r)
+	}
+	a, _ := face.GlyphAdvance(r)
+	m.advance += a
+	m.prevFace, m.prevR, m.prevRValid = face, r, true
+}
+
+// measureLine returns the width of the first n bytes of the Line indexed by l.
+func (f *Frame) measureLine(l int32, n int) fixed.Int26_6 {
+	if f.face == nil {
+		return 0
+	}
+	firstB := f.lines[l].firstB
+	reader := f.lineReader(firstB, f.boxes[firstB].i)
+	m := measurer{f: f}
+	for n > 0 {
+		r, size, err := reader.ReadRune()
+		if err != nil || r == '\n' {
+			break
+		}
+		m.add(r)
+		n -= size
+	}
+	return m.advance
+}
+
 // Delete deletes nBytes bytes in the specified direction from the Caret's
 // location. It returns the number of bytes deleted, which can be fewer than
 // that requested if it hits the beginning or end of the Frame.
@@ -772,21 +841,6 @@ func (c *Caret) splitBox(force bool) bool {
 	if !force && (c.k == bb.i || c.k == bb.j) {
 		return false
 	}
-	newB, realloc := c.f.newBox()
-	if realloc {
-		bb = &c.f.boxes[c.b]
-	}
-	nextB := bb.next
-	if nextB != 0 {
-		c.f.boxes[nextB].prev = newB
-	}
-	c.f.boxes[newB] = Box{
-		next: nextB,
-		prev: c.b,
-		i:    c.k,
-		j:    bb.j,
-	}
-	bb.next = newB
-	bb.j = c.k
+	c.f.splitBox(c.b, c.k)
 	return true
 }
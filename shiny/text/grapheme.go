@@ -0,0 +1,171 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package text
+
+import (
+	"unicode"
+)
+
+// graphemeSegmenter finds the boundaries between grapheme clusters, which are
+// what a user thinks of as a single character. It implements a subset of the
+// extended grapheme cluster rules of Unicode Standard Annex #29: CR LF pairs,
+// combining marks and other extending characters, emoji ZWJ sequences and
+// regional indicator (flag) pairs. It does not handle Hangul syllables made of
+// conjoining jamo, or prepended concatenation marks.
+//
+// The zero value is ready to use, positioned at the start of the text.
+type graphemeSegmenter struct {
+	prev    rune
+	started bool
+
+	// nRI is the number of consecutive regional indicators ending at prev.
+	nRI int
+	// afterPict is whether prev follows an extended pictographic character,
+	// possibly with extending characters and a ZWJ in between.
+	afterPict bool
+}
+
+// zwj is the zero width joiner, used to form emoji sequences.
+const zwj = '\u200d'
+
+// breakBefore returns whether there is a grapheme cluster boundary before r,
+// the next rune in the text, and advances past r.
+func (g *graphemeSegmenter) breakBefore(r rune) bool {
+	brk := true
+	switch {
+	case !g.started:
+		// The start of the text is always a boundary.
+	case g.prev == '\r' && r == '\n':
+		brk = false
+	case isGraphemeControl(g.prev) || isGraphemeControl(r):
+		// Controls are always clusters on their own.
+	case r == zwj || isGraphemeExtend(r):
+		brk = false
+	case g.prev == zwj && g.afterPict && isExtendedPictographic(r):
+		brk = false
+	case isRegionalIndicator(r) && g.nRI%2 == 1:
+		brk = false
+	}
+
+	switch {
+	case isExtendedPictographic(r):
+		g.afterPict = true
+	case r == zwj || isGraphemeExtend(r):
+		// No change.
+	default:
+		g.afterPict = false
+	}
+	if isRegionalIndicator(r) {
+		g.nRI++
+	} else {
+		g.nRI = 0
+	}
+	g.prev, g.started = r, true
+	return brk
+}
+
+func isGraphemeControl(r rune) bool {
+	return unicode.IsControl(r) || r == '\u2028' || r == '\u2029'
+}
+
+// isGraphemeExtend returns whether r extends the grapheme cluster before it:
+// a combining mark (including spacing marks), a variation selector, an emoji
+// skin tone modifier or an emoji tag character.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Variation_Selector) ||
+		(0x1f3fb <= r && r <= 0x1f3ff) ||
+		(0xe0020 <= r && r <= 0xe007f)
+}
+
+// isExtendedPictographic approximates the Extended_Pictographic property,
+// which is not in the unicode package, by the blocks that contain emoji.
+func isExtendedPictographic(r rune) bool {
+	switch {
+	case r == 0x00a9, r == 0x00ae, r == 0x203c, r == 0x2049, r == 0x2122, r == 0x2139:
+		return true
+	case 0x2190 <= r && r <= 0x21ff, // Arrows.
+		0x2300 <= r && r <= 0x23ff,   // Miscellaneous Technical.
+		0x2600 <= r && r <= 0x27bf,   // Miscellaneous Symbols, Dingbats.
+		0x2b00 <= r && r <= 0x2bff,   // Miscellaneous Symbols and Arrows.
+		0x1f000 <= r && r <= 0x1f1e5, // Mahjong, Domino and Playing Cards.
+		0x1f200 <= r && r <= 0x1f3fa, // Up to the skin tone modifiers.
+		0x1f400 <= r && r <= 0x1faff: // Emoticons and later pictographs.
+		return true
+	}
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return 0x1f1e6 <= r && r <= 0x1f1ff
+}
+
+// maxGraphemeLookBehind is the maximum number of runes that MoveLeft examines
+// to find the start of the previous grapheme cluster.
+const maxGraphemeLookBehind = 64
+
+// MoveRight moves the Caret forwards past the next grapheme cluster, which
+// may be more than one rune, such as a letter followed by combining accents
+// or an emoji ZWJ sequence. It is a no-op at the end of the Frame's text.
+func (c *Caret) MoveRight() {
+	c.calculatePLBK()
+	d := *c
+	g := graphemeSegmenter{}
+	n := 0
+	for {
+		r, size, b, k := d.f.readRune(d.b, d.k)
+		if size == 0 {
+			if d.leanForwards() != leanOK {
+				break
+			}
+			continue
+		}
+		if g.breakBefore(r) && n > 0 {
+			break
+		}
+		d.b, d.k = b, k
+		n += size
+	}
+	c.seek(c.pos + int32(n))
+}
+
+// MoveLeft moves the Caret backwards past the previous grapheme cluster, as
+// per MoveRight. It is a no-op at the start of the Frame's text.
+func (c *Caret) MoveLeft() {
+	c.calculatePLBK()
+	d := *c
+	// Collect, in reverse order, the runes before the Caret that could be
+	// part of the previous grapheme cluster.
+	var runes [maxGraphemeLookBehind]rune
+	var sizes [maxGraphemeLookBehind]int
+	nRunes := 0
+	for nRunes < maxGraphemeLookBehind {
+		r, size, b, k := d.f.readLastRune(d.b, d.k)
+		if size == 0 {
+			if d.leanBackwards() != leanOK {
+				break
+			}
+			continue
+		}
+		d.b, d.k = b, k
+		runes[nRunes], sizes[nRunes] = r, size
+		nRunes++
+		if !isGraphemeExtend(r) && r != zwj && r != '\n' &&
+			!isExtendedPictographic(r) && !isRegionalIndicator(r) {
+			// The previous grapheme cluster can't start before r.
+			break
+		}
+	}
+
+	// Segment those runes forwards, finding the size of the last cluster.
+	g := graphemeSegmenter{}
+	n := 0
+	for i := nRunes - 1; i >= 0; i-- {
+		if g.breakBefore(runes[i]) {
+			n = 0
+		}
+		n += sizes[i]
+	}
+	c.seek(c.pos - int32(n))
+}
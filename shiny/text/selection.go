@@ -0,0 +1,83 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package text
+
+import "image"
+
+// SetSelection sets the Frame's selection to the text between the byte
+// offsets start and end, in layout order. The offsets are clamped to the range
+// [0, f.Len()], and swapped if start is greater than end.
+//
+// The selection does not affect the Frame's text or layout. It is not adjusted
+// when the text is edited, other than being clamped to the new length.
+func (f *Frame) SetSelection(start, end int64) {
+	start, end = f.clampSelection(start, end)
+	f.selStart, f.selEnd = int32(start), int32(end)
+}
+
+// Selection returns the byte offsets of the start and end of the Frame's
+// selection, in layout order. The start is less than or equal to the end, and
+// they are equal if nothing is selected.
+func (f *Frame) Selection() (start, end int64) {
+	return f.clampSelection(int64(f.selStart), int64(f.selEnd))
+}
+
+func (f *Frame) clampSelection(start, end int64) (int64, int64) {
+	if start > end {
+		start, end = end, start
+	}
+	if start < 0 {
+		start = 0
+	}
+	if end > int64(f.len) {
+		end = int64(f.len)
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// SelectionRects returns the rectangles, in pixels and relative to the
+// Frame's top-left corner, that cover the selected text: one for each Line
+// that contains some of it, in layout order. The rectangles span the height of
+// their Line, and a Line's trailing '\n' has no width.
+//
+// It returns nil if nothing is selected.
+func (f *Frame) SelectionRects() []image.Rectangle {
+	start, end := f.Selection()
+	if start == end || f.face == nil {
+		return nil
+	}
+	var (
+		rects []image.Rectangle
+		pos   int64 // the offset of the start of the Line, in layout order
+		y     int   // the top of the Line, in pixels
+	)
+	for p := f.firstP; p != 0 && pos < end; p = f.paragraphs[p].next {
+		for l := f.paragraphs[p].firstL; l != 0 && pos < end; l = f.lines[l].next {
+			n := int64(0)
+			for b := f.lines[l].firstB; b != 0; b = f.boxes[b].next {
+				n += int64(f.boxes[b].j - f.boxes[b].i)
+			}
+			h := f.lines[l].Height(f)
+			if i, j := start-pos, end-pos; i < n && j > 0 {
+				if i < 0 {
+					i = 0
+				}
+				if j > n {
+					j = n
+				}
+				rects = append(rects, image.Rect(
+					f.measureLine(l, int(i)).Floor(), y,
+					f.measureLine(l, int(j)).Ceil(), y+h,
+				))
+			}
+			pos += n
+			y += h
+		}
+	}
+	return rects
+}
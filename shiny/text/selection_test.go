@@ -0,0 +1,76 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package text
+
+import (
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestSelection(t *testing.T) {
+	f := iRobotFrame(10)
+	n := int64(len(iRobot))
+	testCases := []struct {
+		start, end         int64
+		wantStart, wantEnd int64
+		wantRects          []image.Rectangle
+	}{{
+		start: 0, end: 0,
+		wantStart: 0, wantEnd: 0,
+	}, {
+		// The first Line is `"I, Robot" `, so the selection "Robot\" in R"
+		// spans a wrap boundary.
+		start: 4, end: 15,
+		wantStart: 4, wantEnd: 15,
+		wantRects: []image.Rectangle{
+			image.Rect(4, 0, 11, 3),
+			image.Rect(0, 3, 4, 6),
+		},
+	}, {
+		start: 15, end: 4,
+		wantStart: 4, wantEnd: 15,
+		wantRects: []image.Rectangle{
+			image.Rect(4, 0, 11, 3),
+			image.Rect(0, 3, 4, 6),
+		},
+	}, {
+		// Select "It's" at the start of the second Paragraph, and the '\n'
+		// before it, which has no width.
+		start: n - 20, end: n - 15,
+		wantStart: n - 20, wantEnd: n - 15,
+		wantRects: []image.Rectangle{
+			image.Rect(7, 9, 7, 12),
+			image.Rect(0, 12, 4, 15),
+		},
+	}, {
+		// Select everything. The Lines are `"I, Robot" `, `in Russian `,
+		// `is "Я, `, `робот".\n`, `It's about ` and `robots.\n`, and each
+		// rune is 1 pixel wide. The final Line is empty, so it has no
+		// rectangle.
+		start: n + 10, end: -5,
+		wantStart: 0, wantEnd: n,
+		wantRects: []image.Rectangle{
+			image.Rect(0, 0, 11, 3),
+			image.Rect(0, 3, 11, 6),
+			image.Rect(0, 6, 7, 9),
+			image.Rect(0, 9, 7, 12),
+			image.Rect(0, 12, 11, 15),
+			image.Rect(0, 15, 7, 18),
+		},
+	}}
+	for _, tc := range testCases {
+		f.SetSelection(tc.start, tc.end)
+		gotStart, gotEnd := f.Selection()
+		if gotStart != tc.wantStart || gotEnd != tc.wantEnd {
+			t.Errorf("SetSelection(%d, %d): Selection: got %d, %d, want %d, %d",
+				tc.start, tc.end, gotStart, gotEnd, tc.wantStart, tc.wantEnd)
+			continue
+		}
+		if got := f.SelectionRects(); !reflect.DeepEqual(got, tc.wantRects) {
+			t.Errorf("SetSelection(%d, %d): SelectionRects:\ngot  %v\nwant %v", tc.start, tc.end, got, tc.wantRects)
+		}
+	}
+}
@@ -38,6 +38,7 @@ package text // import "golang.org/x/exp/shiny/text"
 
 import (
 	"io"
+	"math"
 	"unicode/utf8"
 
 	"golang.org/x/image/font"
@@ -110,9 +111,15 @@ type Frame struct {
 	firstP int32
 
 	maxWidth fixed.Int26_6
+	tabWidth fixed.Int26_6
 
-	faceHeight int32
-	face       font.Face
+	// faceHeight is the height of each Line, including any extra line
+	// spacing. lineSpacing is the multiplier set by SetLineSpacing, where
+	// zero means 1.
+	faceHeight   int32
+	lineSpacing  float64
+	face         font.Face
+	faceSelector func(r rune) font.Face
 
 	// len is the total length of the Frame's current textual content, in
 	// bytes. It can be smaller then len(text), since that []byte can contain
@@ -125,6 +132,10 @@ type Frame struct {
 	len  int
 	text []byte
 
+	// selStart and selEnd are the byte offsets, in layout order, of the
+	// selection. See SetSelection.
+	selStart, selEnd int32
+
 	// seqNum is a sequence number that is incremented every time the Frame's
 	// text or layout is modified. It lets us detect whether a Caret's cached
 	// p, l, b and k fields are stale.
@@ -145,29 +156,84 @@ func (f *Frame) SetFace(face font.Face) {
 		f.initialize()
 	}
 	f.face = face
-	if face == nil {
+	f.updateFaceHeight()
+	if f.len != 0 {
+		f.relayout()
+	}
+}
+
+// SetLineSpacing sets the vertical advance between Lines, as a multiple of
+// the font face's line height, such as 1.5 for one and a half line spacing.
+// The resultant height of each Line is rounded to the nearest whole pixel.
+//
+// A non-positive argument is treated as 1, which is the default.
+func (f *Frame) SetLineSpacing(multiplier float64) {
+	if !f.initialized() {
+		f.initialize()
+	}
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	if f.lineSpacing == multiplier {
+		return
+	}
+	f.lineSpacing = multiplier
+	f.updateFaceHeight()
+	// Even an empty Frame has a Line, whose cached height is now stale.
+	f.relayout()
+}
+
+// updateFaceHeight sets f.faceHeight, the height of each Line, from the font
+// face's metrics and the line spacing.
+func (f *Frame) updateFaceHeight() {
+	if f.face == nil {
 		f.faceHeight = 0
-	} else {
-		// We round up the ascent and descent separately, instead of asking for
-		// the metrics' height, since we quantize the baseline to the integer
-		// pixel grid. For example, if ascent and descent were both 3.2 pixels,
-		// then the naive height would be 6.4, which rounds up to 7, but we
-		// should really provide 8 pixels (= ceil(3.2) + ceil(3.2)) between
-		// each line to avoid overlap.
-		//
-		// TODO: is a font.Metrics.Height actually useful in practice??
-		//
-		// TODO: is it the font face's responsibility to track line spacing, as
-		// in "double line spacing", or does that belong somewhere else, since
-		// it doesn't affect the face's glyph masks?
-		m := face.Metrics()
-		f.faceHeight = int32(m.Ascent.Ceil() + m.Descent.Ceil())
+		return
+	}
+	// We round up the ascent and descent separately, instead of asking for
+	// the metrics' height, since we quantize the baseline to the integer pixel
+	// grid. For example, if ascent and descent were both 3.2 pixels, then the
+	// naive height would be 6.4, which rounds up to 7, but we should really
+	// provide 8 pixels (= ceil(3.2) + ceil(3.2)) between each line to avoid
+	// overlap.
+	//
+	// TODO: is a font.Metrics.Height actually useful in practice??
+	m := f.face.Metrics()
+	h := m.Ascent.Ceil() + m.Descent.Ceil()
+	if f.lineSpacing > 0 && f.lineSpacing != 1 {
+		h = int(math.Round(float64(h) * f.lineSpacing))
+	}
+	f.faceHeight = int32(h)
+}
+
+// SetFaceSelector sets a function that chooses the font face for each rune,
+// such as to fall back to other fonts for scripts or emoji that the face
+// passed to SetFace lacks. A nil selector, or a nil face returned by it, means
+// that face, which also still determines the height of each Line. The faces
+// are compared with ==.
+//
+// Boxes are split wherever the face changes, so that all of a Box's text uses
+// the same face, given by Box.Face.
+func (f *Frame) SetFaceSelector(selector func(r rune) font.Face) {
+	if !f.initialized() {
+		f.initialize()
 	}
+	f.faceSelector = selector
 	if f.len != 0 {
 		f.relayout()
 	}
 }
 
+// faceFor returns the font face for the rune r.
+func (f *Frame) faceFor(r rune) font.Face {
+	if f.faceSelector != nil {
+		if face := f.faceSelector(r); face != nil {
+			return face
+		}
+	}
+	return f.face
+}
+
 // TODO: should SetMaxWidth take an int number of pixels instead of a
 // fixed.Int26_6 number of sub-pixels? Height returns an int, since it assumes
 // that the text baselines are quantized to the integer pixel grid.
@@ -193,6 +259,31 @@ func (f *Frame) SetMaxWidth(m fixed.Int26_6) {
 	}
 }
 
+// SetTabWidth sets the distance between tab stops, as a fixed-point
+// fractional number of pixels. A '\t' advances to the next multiple of the
+// tab width from the start of its Line, instead of being measured as a glyph
+// of the font face. Like a ' ', a '\t' is a point at which a Line may be
+// broken.
+//
+// A non-positive argument means that a '\t' is measured like any other rune.
+func (f *Frame) SetTabWidth(w fixed.Int26_6) {
+	if !f.initialized() {
+		f.initialize()
+	}
+	if f.tabWidth == w {
+		return
+	}
+	f.tabWidth = w
+	if f.len != 0 {
+		f.relayout()
+	}
+}
+
+// TabWidth returns the distance between tab stops, as set by SetTabWidth.
+func (f *Frame) TabWidth() fixed.Int26_6 {
+	return f.tabWidth
+}
+
 func (f *Frame) relayout() {
 	for p := f.firstP; p != 0; p = f.paragraphs[p].next {
 		l := f.mergeIntoOneLine(p)
@@ -244,11 +335,14 @@ func (f *Frame) mergeIntoOneLine(p int32) (l int32) {
 // equals the Box.i field of the second, or at least one of them is empty. It
 // returns whether they were joined. If they were joined, the second of the two
 // Boxes is freed.
+//
+// It does not check that the two Boxes use the same font face. Laying out the
+// Line again splits the Boxes at any face boundaries.
 func (f *Frame) joinBoxes(b0, b1 int32, bb0, bb1 *Box) bool {
 	switch {
 	case bb0.i == bb0.j:
 		// The first Box is empty. Replace its i/j with the second one's.
-		bb0.i, bb0.j = bb1.i, bb1.j
+		bb0.i, bb0.j, bb0.face = bb1.i, bb1.j, bb1.face
 	case bb1.i == bb1.j:
 		// The second box is empty. Drop it.
 	case bb0.j == bb1.i:
@@ -313,6 +407,28 @@ func (f *Frame) newLine() (l int32, realloc bool) {
 	return int32(len(f.lines) - 1), realloc
 }
 
+// splitBox splits the Box b into two at the text index k, which must be within
+// the Box's text, and returns the index of the new second Box. Any existing
+// *Box pointers can become invalid.
+func (f *Frame) splitBox(b, k int32) (newB int32) {
+	newB, _ = f.newBox()
+	bb := &f.boxes[b]
+	nextB := bb.next
+	if nextB != 0 {
+		f.boxes[nextB].prev = newB
+	}
+	f.boxes[newB] = Box{
+		next: nextB,
+		prev: b,
+		i:    k,
+		j:    bb.j,
+		face: bb.face,
+	}
+	bb.next = newB
+	bb.j = k
+	return newB
+}
+
 // newBox returns the index of an empty Box, and whether or not the underlying
 // memory has been re-allocated. Re-allocation means that any existing *Box
 // pointers become invalid.
@@ -372,6 +488,12 @@ func (f *Frame) invalidateCaches() {
 }
 
 // Height returns the height in pixels of this Frame.
+//
+// Since each Line's baseline is quantized to the integer pixel grid, this is
+// the LineCount multiplied by the font face's rounded-up line height, scaled
+// by the line spacing. Together with LineCount, it can be used to size a
+// scroll bar. Both are updated by edits and by calls to SetFace,
+// SetLineSpacing and SetMaxWidth.
 func (f *Frame) Height() int {
 	if !f.initialized() {
 		f.initialize()
@@ -460,6 +582,7 @@ func (f *Frame) compactText() {
 			ll.firstB, _ = f.newBox()
 			bb := &f.boxes[ll.firstB]
 			bb.i, bb.j = i, j
+			splitFaces(f, l)
 
 			l = ll.next
 		}
@@ -565,7 +688,7 @@ func (f *Frame) readLastRune(b, k int32) (r rune, size int, newB, newK int32) {
 	}
 	n := int32(utf8.UTFMax)
 	for {
-		if k < bb.j {
+		if bb.i < k {
 			nCopied := k - bb.i
 			if nCopied > n {
 				nCopied = n
@@ -754,6 +877,9 @@ type Box struct {
 	next, prev int32
 	// Frame.text[i:j] holds this Box's text.
 	i, j int32
+	// face is the font face chosen by the Frame's face selector for this
+	// Box's text, or nil if there is no selector.
+	face font.Face
 }
 
 // Next returns the next Box after this one in the Line.
@@ -766,6 +892,16 @@ func (b *Box) Next(f *Frame) *Box {
 	return &f.boxes[b.next]
 }
 
+// Face returns the font face for rendering the Box's text.
+//
+// f is the Frame that contains the Box.
+func (b *Box) Face(f *Frame) font.Face {
+	if b.face != nil {
+		return b.face
+	}
+	return f.face
+}
+
 // Text returns the Box's text.
 //
 // f is the Frame that contains the Box.
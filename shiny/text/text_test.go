@@ -458,6 +458,35 @@ func TestLineCount(t *testing.T) {
 	}
 }
 
+func TestLineCountAfterEdits(t *testing.T) {
+	f := iRobotFrame(10)
+	check := func(desc string, wantLineCount int) {
+		t.Helper()
+		// Ask twice, to check the cached values.
+		for i := 0; i < 2; i++ {
+			if got := f.LineCount(); got != wantLineCount {
+				t.Errorf("%s: LineCount: got %d, want %d", desc, got, wantLineCount)
+			}
+			if got, want := f.Height(), toyFaceLineHeight*wantLineCount; got != want {
+				t.Errorf("%s: Height: got %d, want %d", desc, got, want)
+			}
+		}
+	}
+	check("initial", 7)
+
+	c := f.NewCaret()
+	defer c.Close()
+	c.Seek(0, SeekEnd)
+	c.WriteString(iRobot)
+	check("after write", 13)
+	c.Delete(Backwards, len(iRobot))
+	check("after delete", 7)
+	f.SetMaxWidth(fixed.I(0))
+	check("after SetMaxWidth(0)", 3)
+	f.SetMaxWidth(fixed.I(10))
+	check("after SetMaxWidth(10)", 7)
+}
+
 func TestSetMaxWidth(t *testing.T) {
 	f := new(Frame)
 	f.SetFace(toyFace{})
@@ -496,6 +525,246 @@ func TestSetMaxWidth(t *testing.T) {
 	}
 }
 
+func TestSetLineSpacing(t *testing.T) {
+	f := iRobotFrame(10)
+	const wantLineCount = 7
+	testCases := []struct {
+		multiplier float64
+		wantHeight int // of each Line
+	}{
+		{1, toyFaceLineHeight},
+		{2, 2 * toyFaceLineHeight},
+		{1.5, 5}, // 4.5 pixels, rounded.
+		{0, toyFaceLineHeight},
+		{3, 3 * toyFaceLineHeight},
+	}
+	for _, tc := range testCases {
+		f.SetLineSpacing(tc.multiplier)
+		if err := checkInvariants(f); err != nil {
+			t.Fatalf("multiplier=%v: %v", tc.multiplier, err)
+		}
+		if got := f.LineCount(); got != wantLineCount {
+			t.Errorf("multiplier=%v: LineCount: got %d, want %d", tc.multiplier, got, wantLineCount)
+		}
+		if got, want := f.Height(), tc.wantHeight*wantLineCount; got != want {
+			t.Errorf("multiplier=%v: Height: got %d, want %d", tc.multiplier, got, want)
+		}
+
+		// Selecting everything gives one rectangle per non-empty Line, whose
+		// tops are at multiples of the Line height.
+		f.SetSelection(0, int64(f.Len()))
+		rects := f.SelectionRects()
+		if got, want := len(rects), wantLineCount-1; got != want {
+			t.Errorf("multiplier=%v: len(rects): got %d, want %d", tc.multiplier, got, want)
+			continue
+		}
+		for i, r := range rects {
+			if got, want := r.Min.Y, i*tc.wantHeight; got != want {
+				t.Errorf("multiplier=%v: rects[%d].Min.Y: got %d, want %d", tc.multiplier, i, got, want)
+			}
+			if got, want := r.Dy(), tc.wantHeight; got != want {
+				t.Errorf("multiplier=%v: rects[%d].Dy(): got %d, want %d", tc.multiplier, i, got, want)
+			}
+		}
+	}
+
+	// The line spacing applies to an empty Frame, and to a Frame whose face
+	// is set after the line spacing.
+	g := new(Frame)
+	g.SetLineSpacing(2)
+	g.SetFace(toyFace{})
+	if got, want := g.Height(), 2*toyFaceLineHeight; got != want {
+		t.Errorf("empty Frame: Height: got %d, want %d", got, want)
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	f := new(Frame)
+	f.SetFace(toyFace{})
+	f.SetTabWidth(fixed.I(4))
+	c := f.NewCaret()
+	c.WriteString("a\tbc\td\nabcde\tf\n\tgh\tij\n")
+	c.Close()
+
+	// wantXs holds the x position, in pixels, of the rune after each tab. It
+	// is found by selecting that rune.
+	wantXs := [][]int{{4, 8}, {8}, {4, 8}, nil}
+	gotXs := [][]int{}
+	pos := int64(0)
+	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
+		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
+			var line []byte
+			for b := l.FirstBox(f); b != nil; b = b.Next(f) {
+				line = append(line, b.Text(f)...)
+			}
+			xs := []int(nil)
+			for i, x := range line {
+				if x == '\t' {
+					f.SetSelection(pos+int64(i)+1, pos+int64(i)+2)
+					xs = append(xs, f.SelectionRects()[0].Min.X)
+				}
+			}
+			gotXs = append(gotXs, xs)
+			pos += int64(len(line))
+		}
+	}
+	f.SetSelection(0, 0)
+	if !reflect.DeepEqual(gotXs, wantXs) {
+		t.Errorf("x positions after tabs: got %v, want %v", gotXs, wantXs)
+	}
+
+	// Lines break after a tab, like after a space.
+	f.SetMaxWidth(fixed.I(10))
+	c = f.NewCaret()
+	c.Seek(0, SeekEnd)
+	c.WriteString("abc\tdefg\thi\n")
+	c.Close()
+	if err := checkInvariants(f); err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{}
+	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
+		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
+			var line []byte
+			for b := l.FirstBox(f); b != nil; b = b.Next(f) {
+				line = append(line, b.TrimmedText(f)...)
+			}
+			lines = append(lines, string(line))
+		}
+	}
+	wantLines := []string{"a\tbc\td", "abcde\tf", "\tgh\tij", "abc\tdefg", "hi", ""}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("lines: got %q, want %q", lines, wantLines)
+	}
+
+	// A non-positive tab width measures a tab as a glyph.
+	f.SetTabWidth(0)
+	f.SetSelection(0, int64(len("a\tbc\td")))
+	if got, want := f.SelectionRects()[0].Max.X, 6; got != want {
+		t.Errorf("width with no tab stops: got %d, want %d", got, want)
+	}
+}
+
+// wideFace is like toyFace but measures every rune's width as 2 pixels.
+type wideFace struct{ toyFace }
+
+func (wideFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return fixed.I(2), true
+}
+
+func TestFaceSelector(t *testing.T) {
+	f := new(Frame)
+	f.SetFace(toyFace{})
+	f.SetFaceSelector(func(r rune) font.Face {
+		if r >= utf8.RuneSelf {
+			return wideFace{}
+		}
+		return nil
+	})
+	c := f.NewCaret()
+	c.WriteString("I, Robot is Я, робот.\n")
+
+	type box struct {
+		text string
+		face font.Face
+	}
+	boxes := func() (got [][]box) {
+		for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
+			for l := p.FirstLine(f); l != nil; l = l.Next(f) {
+				var line []box
+				for b := l.FirstBox(f); b != nil; b = b.Next(f) {
+					if len(b.Text(f)) > 0 {
+						line = append(line, box{string(b.Text(f)), b.Face(f)})
+					}
+				}
+				got = append(got, line)
+			}
+		}
+		return got
+	}
+
+	toy, wide := toyFace{}, wideFace{}
+	want := [][]box{{
+		{"I, Robot is ", toy},
+		{"Я", wide},
+		{", ", toy},
+		{"робот", wide},
+		{".\n", toy},
+	}, nil}
+	if err := checkInvariants(f); err != nil {
+		t.Fatal(err)
+	}
+	if got := boxes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("boxes:\ngot  %v\nwant %v", got, want)
+	}
+	if got, want := f.measureLine(f.paragraphs[f.firstP].firstL, len("I, Robot is Я")), fixed.I(14); got != want {
+		t.Errorf("width: got %v, want %v", got, want)
+	}
+
+	// Writing inside a run of one face splits its Box.
+	c.Seek(int64(len("I, Robot is Я, ро")), SeekSet)
+	c.WriteString("bo")
+	want[0] = []box{
+		{"I, Robot is ", toy},
+		{"Я", wide},
+		{", ", toy},
+		{"ро", wide},
+		{"bo", toy},
+		{"бот", wide},
+		{".\n", toy},
+	}
+	if err := checkInvariants(f); err != nil {
+		t.Fatal(err)
+	}
+	if got := boxes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after write:\ngot  %v\nwant %v", got, want)
+	}
+
+	// Deleting text can join Boxes of the same face.
+	c.Delete(Backwards, len("bo"))
+	c.Close()
+	want[0] = []box{
+		{"I, Robot is ", toy},
+		{"Я", wide},
+		{", ", toy},
+		{"робот", wide},
+		{".\n", toy},
+	}
+	if err := checkInvariants(f); err != nil {
+		t.Fatal(err)
+	}
+	if got := boxes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("after delete:\ngot  %v\nwant %v", got, want)
+	}
+
+	// Lines are broken according to each rune's face: "I, Robot is " is 12
+	// pixels wide, but "Я, робот" is 14.
+	f.SetMaxWidth(fixed.I(12))
+	want = [][]box{{
+		{"I, Robot is ", toy},
+	}, {
+		{"Я", wide},
+		{", ", toy},
+	}, {
+		{"робот", wide},
+		{".\n", toy},
+	}, nil}
+	if err := checkInvariants(f); err != nil {
+		t.Fatal(err)
+	}
+	if got := boxes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("with max width:\ngot  %v\nwant %v", got, want)
+	}
+
+	// Without a selector, every Box uses the Frame's face.
+	f.SetMaxWidth(0)
+	f.SetFaceSelector(nil)
+	want = [][]box{{{"I, Robot is Я, робот.\n", toy}}, nil}
+	if got := boxes(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("without selector:\ngot  %v\nwant %v", got, want)
+	}
+}
+
 func TestReadRuneAcrossBoxes(t *testing.T) {
 	rng := rand.New(rand.NewSource(1))
 	for i := 0; i < 6; i++ {
@@ -562,7 +831,95 @@ func TestReadRuneAcrossBoxes(t *testing.T) {
 						i, j, gotRunes, wantRunes)
 				}
 			}
+
+			// Test Frame.readLastRune, reading backwards from the end of the
+			// sole Line, which may cross Box boundaries mid-rune.
+			{
+				l := f.paragraphs[f.firstP].firstL
+				b := f.lines[l].lastBox(f)
+				k := f.boxes[b].j
+				gotRunes := gotRunesBuf[:0]
+				for n := 0; n < len(text); {
+					r, size, newB, newK := f.readLastRune(b, k)
+					if size == 0 {
+						t.Fatalf("i=%d, j=%d: readLastRune: no rune after %d bytes", i, j, n)
+					}
+					gotRunes = append(gotRunes, r)
+					n += size
+					b, k = newB, newK
+				}
+				for x, y := 0, len(gotRunes)-1; x < y; x, y = x+1, y-1 {
+					gotRunes[x], gotRunes[y] = gotRunes[y], gotRunes[x]
+				}
+				if !runesEqual(gotRunes, wantRunes) {
+					t.Fatalf("i=%d, j=%d: readLastRune:\ngot  %#x\nwant %#x",
+						i, j, gotRunes, wantRunes)
+				}
+			}
+		}
+	}
+}
+
+func TestMoveByGrapheme(t *testing.T) {
+	clusters := []string{
+		"a",
+		"e\u0301", // e with a combining acute accent.
+		"\U0001f469\u200d\U0001f469\u200d\U0001f467", // Family: woman, woman, girl.
+		"\U0001f44d\U0001f3fd",                       // Thumbs up with a skin tone.
+		"\u2764\ufe0f",                               // Heart with an emoji variation selector.
+		"\U0001f1ef\U0001f1f5",                       // Flag: Japan.
+		"\U0001f1eb\U0001f1f7",                       // Flag: France.
+		"\r\n",
+		"z",
+		"\n",
+	}
+	text := strings.Join(clusters, "")
+	wantPositions := []int{0}
+	for _, cl := range clusters {
+		wantPositions = append(wantPositions, wantPositions[len(wantPositions)-1]+len(cl))
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		f := new(Frame)
+		c := f.NewCaret()
+		c.WriteString(text)
+		// Split the text into multiple Boxes at random byte offsets, possibly
+		// cutting across grapheme clusters and multi-byte UTF-8 encoded runes.
+		for j := 0; j < i; j++ {
+			c.Seek(int64(rng.Intn(len(text)+1)), SeekSet)
+			c.splitBox(false)
+		}
+
+		c.Seek(0, SeekSet)
+		for j := 1; j < len(wantPositions); j++ {
+			c.MoveRight()
+			if got, want := c.pos, int32(wantPositions[j]); got != want {
+				t.Fatalf("i=%d: MoveRight #%d: got position %d, want %d", i, j, got, want)
+			}
+			if err := checkInvariants(f); err != nil {
+				t.Fatalf("i=%d: MoveRight #%d: %v", i, j, err)
+			}
+		}
+		c.MoveRight()
+		if got, want := c.pos, int32(len(text)); got != want {
+			t.Fatalf("i=%d: MoveRight at end: got position %d, want %d", i, got, want)
+		}
+
+		for j := len(wantPositions) - 2; j >= 0; j-- {
+			c.MoveLeft()
+			if got, want := c.pos, int32(wantPositions[j]); got != want {
+				t.Fatalf("i=%d: MoveLeft to #%d: got position %d, want %d", i, j, got, want)
+			}
+			if err := checkInvariants(f); err != nil {
+				t.Fatalf("i=%d: MoveLeft to #%d: %v", i, j, err)
+			}
+		}
+		c.MoveLeft()
+		if got := c.pos; got != 0 {
+			t.Fatalf("i=%d: MoveLeft at start: got position %d, want 0", i, got)
 		}
+		c.Close()
 	}
 }
 
@@ -6,18 +6,48 @@ package widget
 
 import (
 	"image"
+	"strings"
+	"unicode/utf8"
 
+	"golang.org/x/exp/shiny/text"
 	"golang.org/x/exp/shiny/widget/node"
 	"golang.org/x/exp/shiny/widget/theme"
 	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 )
 
+// Overflow is how a Label shows text that does not fit within its Rect.
+type Overflow uint8
+
+const (
+	// Clip cuts the text off at the edges of the Label.
+	Clip Overflow = iota
+	// Ellipsis shortens the last line of text that fits, if any text does not
+	// fit, and ends it with "…".
+	Ellipsis
+)
+
+// ellipsis is the text that ends a line shortened by the Ellipsis Overflow.
+const ellipsis = "…"
+
 // Label is a leaf widget that holds a text label.
 type Label struct {
 	node.LeafEmbed
 	Text       string
 	ThemeColor theme.Color
+
+	// Wrap is whether to break the text into lines to fit the Label's width.
+	// If false, the text is a single line.
+	Wrap bool
+
+	// Overflow is how to show text that does not fit within the Label.
+	Overflow Overflow
+
+	// frame holds the text to wrap, which is frameText, laid out using
+	// frameFace.
+	frame     text.Frame
+	frameText string
+	frameFace font.Face
 }
 
 // NewLabel returns a new Label widget.
@@ -29,6 +59,84 @@ func NewLabel(text string) *Label {
 	return w
 }
 
+// NewWrappingLabel returns a new Label widget whose text wraps to fit its
+// width.
+func NewWrappingLabel(text string) *Label {
+	w := NewLabel(text)
+	w.Wrap = true
+	return w
+}
+
+// lines returns the Label's text broken into lines no wider than maxWidth
+// pixels, if w.Wrap is set. A non-positive maxWidth means no limit.
+func (w *Label) lines(face font.Face, maxWidth int) []string {
+	if !w.Wrap {
+		return []string{w.Text}
+	}
+	if w.frameText != w.Text {
+		w.frameText = w.Text
+		c := w.frame.NewCaret()
+		c.Delete(text.Forwards, w.frame.Len())
+		c.WriteString(w.Text)
+		c.Close()
+	}
+	if w.frameFace != face {
+		w.frameFace = face
+		w.frame.SetFace(face)
+	}
+	if maxWidth < 0 {
+		maxWidth = 0
+	}
+	w.frame.SetMaxWidth(fixed.I(maxWidth))
+
+	var lines []string
+	f := &w.frame
+	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
+		if p.Next(f) == nil && strings.HasSuffix(w.Text, "\n") {
+			// Don't count the empty Paragraph after a trailing "\n".
+			break
+		}
+		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
+			var line []byte
+			for b := l.FirstBox(f); b != nil; b = b.Next(f) {
+				line = append(line, b.TrimmedText(f)...)
+			}
+			lines = append(lines, string(line))
+		}
+	}
+	return lines
+}
+
+// visibleLines returns the lines of text to paint in a Label of the given
+// size, shortened according to w.Overflow.
+func (w *Label) visibleLines(face font.Face, size image.Point) []string {
+	lines := w.lines(face, size.X)
+	m := face.Metrics()
+	lineHeight := m.Ascent.Ceil() + m.Descent.Ceil()
+	n := len(lines)
+	if lineHeight > 0 && n > size.Y/lineHeight {
+		n = size.Y / lineHeight
+		if n == 0 {
+			// Show a partial line rather than nothing.
+			n = 1
+		}
+	}
+	if w.Overflow != Ellipsis {
+		return lines[:n]
+	}
+	last := lines[n-1]
+	if n == len(lines) && font.MeasureString(face, last).Ceil() <= size.X {
+		return lines[:n]
+	}
+	// Remove runes until the shortened line and the ellipsis fit.
+	for last != "" && font.MeasureString(face, last+ellipsis).Ceil() > size.X {
+		_, runeSize := utf8.DecodeLastRuneInString(last)
+		last = last[:len(last)-runeSize]
+	}
+	lines = append(lines[:n-1:n-1], last+ellipsis)
+	return lines
+}
+
 func (w *Label) Measure(t *theme.Theme, widthHint, heightHint int) {
 	face := t.AcquireFontFace(theme.FontFaceOptions{})
 	defer t.ReleaseFontFace(theme.FontFaceOptions{}, face)
@@ -36,8 +144,14 @@ func (w *Label) Measure(t *theme.Theme, widthHint, heightHint int) {
 
 	// TODO: padding, to match a Text widget?
 
-	w.MeasuredSize.X = font.MeasureString(face, w.Text).Ceil()
-	w.MeasuredSize.Y = m.Ascent.Ceil() + m.Descent.Ceil()
+	lines := w.lines(face, widthHint)
+	w.MeasuredSize.X = 0
+	for _, line := range lines {
+		if x := font.MeasureString(face, line).Ceil(); w.MeasuredSize.X < x {
+			w.MeasuredSize.X = x
+		}
+	}
+	w.MeasuredSize.Y = len(lines) * (m.Ascent.Ceil() + m.Descent.Ceil())
 }
 
 func (w *Label) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
@@ -51,6 +165,7 @@ func (w *Label) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error
 	defer ctx.Theme.ReleaseFontFace(theme.FontFaceOptions{}, face)
 	m := face.Metrics()
 	ascent := m.Ascent.Ceil()
+	lineHeight := ascent + m.Descent.Ceil()
 
 	tc := w.ThemeColor
 	if tc == nil {
@@ -61,11 +176,13 @@ func (w *Label) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error
 		Dst:  dst,
 		Src:  tc.Uniform(ctx.Theme),
 		Face: face,
-		Dot: fixed.Point26_6{
+	}
+	for i, line := range w.visibleLines(face, w.Rect.Size()) {
+		d.Dot = fixed.Point26_6{
 			X: fixed.I(origin.X + w.Rect.Min.X),
-			Y: fixed.I(origin.Y + w.Rect.Min.Y + ascent),
-		},
+			Y: fixed.I(origin.Y + w.Rect.Min.Y + ascent + i*lineHeight),
+		}
+		d.DrawString(line)
 	}
-	d.DrawString(w.Text)
 	return nil
 }
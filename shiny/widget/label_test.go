@@ -0,0 +1,109 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+func TestLabel(t *testing.T) {
+	face := theme.Default.AcquireFontFace(theme.FontFaceOptions{})
+	defer theme.Default.ReleaseFontFace(theme.FontFaceOptions{}, face)
+	// The default font face is fixed-width.
+	advance, _ := face.GlyphAdvance('x')
+	a := advance.Ceil()
+	m := face.Metrics()
+	lh := m.Ascent.Ceil() + m.Descent.Ceil()
+
+	testCases := []struct {
+		desc     string
+		w        *Label
+		size     image.Point
+		wantSize image.Point
+		want     []string
+	}{{
+		desc:     "no wrap",
+		w:        NewLabel("the quick brown fox"),
+		size:     image.Point{10 * a, lh},
+		wantSize: image.Point{19 * a, lh},
+		want:     []string{"the quick brown fox"},
+	}, {
+		desc:     "no wrap, ellipsis",
+		w:        &Label{Text: "the quick brown fox", Overflow: Ellipsis},
+		size:     image.Point{10 * a, lh},
+		wantSize: image.Point{19 * a, lh},
+		want:     []string{"the quick…"},
+	}, {
+		desc:     "wrap",
+		w:        NewWrappingLabel("the quick brown fox\n"),
+		size:     image.Point{10 * a, 2 * lh},
+		wantSize: image.Point{9 * a, 2 * lh},
+		want:     []string{"the quick", "brown fox"},
+	}, {
+		desc:     "wrap, clip",
+		w:        NewWrappingLabel("the quick brown fox jumps"),
+		size:     image.Point{10 * a, 2 * lh},
+		wantSize: image.Point{9 * a, 3 * lh},
+		want:     []string{"the quick", "brown fox"},
+	}, {
+		desc:     "wrap, ellipsis",
+		w:        &Label{Text: "the quick brown fox jumps", Wrap: true, Overflow: Ellipsis},
+		size:     image.Point{10 * a, 2 * lh},
+		wantSize: image.Point{9 * a, 3 * lh},
+		want:     []string{"the quick", "brown fox…"},
+	}, {
+		desc:     "wrap, ellipsis, narrow",
+		w:        &Label{Text: "the quick brown fox jumps", Wrap: true, Overflow: Ellipsis},
+		size:     image.Point{6 * a, lh + lh/2},
+		wantSize: image.Point{5 * a, 5 * lh},
+		want:     []string{"the…"},
+	}}
+	for _, tc := range testCases {
+		tc.w.Measure(theme.Default, tc.size.X, node.NoHint)
+		if got := tc.w.MeasuredSize; got != tc.wantSize {
+			t.Errorf("%s: MeasuredSize: got %v, want %v", tc.desc, got, tc.wantSize)
+		}
+		if got := tc.w.visibleLines(face, tc.size); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: visible lines: got %q, want %q", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestLabelPaintBase(t *testing.T) {
+	w := &Label{
+		Text:       "the quick brown fox jumps over the lazy dog",
+		ThemeColor: theme.StaticColor(color.RGBA{0xff, 0x00, 0x00, 0xff}),
+		Wrap:       true,
+		Overflow:   Ellipsis,
+	}
+	w.Rect = image.Rect(10, 10, 90, 50)
+	dst := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	ctx := &node.PaintBaseContext{Theme: theme.Default, Dst: dst}
+	if err := w.PaintBase(ctx, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	painted := false
+	b := dst.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if dst.RGBAAt(x, y) == (color.RGBA{}) {
+				continue
+			}
+			if !(image.Point{x, y}).In(w.Rect) {
+				t.Fatalf("painted outside of the Label, at (%d, %d)", x, y)
+			}
+			painted = true
+		}
+	}
+	if !painted {
+		t.Error("painted nothing")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+
+	"golang.org/x/mobile/event/key"
+)
+
+// Focuser is implemented by nodes that can hold the keyboard focus.
+type Focuser interface {
+	// AcceptsFocus returns whether the node can currently hold the keyboard
+	// focus.
+	AcceptsFocus() bool
+}
+
+// FocusScoper is implemented by nodes, such as modal dialogs, that can confine
+// the keyboard focus to one of their subtrees.
+type FocusScoper interface {
+	// FocusScope returns the subtree that the focus is confined to, or nil if
+	// the focus is not currently confined.
+	FocusScope() Node
+}
+
+// FocusManager tracks which node of a widget tree holds the keyboard focus,
+// and routes key events to that node.
+//
+// Pressing Tab moves the focus to the next node, in tree order, that accepts
+// the focus, and Shift-Tab moves it to the previous one. Both wrap around at
+// the ends of the tree. Nodes that do not implement Focuser, or whose
+// AcceptsFocus method returns false, are skipped.
+//
+// While a FocusScoper confines the focus, both focus traversal and key events
+// are limited to its scope. If several nodes confine the focus, the scope is
+// that of the last one in tree order, which is painted on top, or of one
+// nested within that scope.
+//
+// The zero value is not usable. Root must be set before calling any methods.
+type FocusManager struct {
+	// Root is the root of the widget tree.
+	Root Node
+
+	focus Node
+}
+
+// Focus returns the node that holds the focus, or nil if there is none.
+func (m *FocusManager) Focus() Node {
+	return m.focus
+}
+
+// SetFocus gives the focus to n, which should be a node of the tree or nil.
+func (m *FocusManager) SetFocus(n Node) {
+	m.focus = n
+}
+
+// OnKeyEvent handles a key event. Tab and Shift-Tab presses move the focus.
+// Other events are passed to the OnInputEvent method of the node that holds
+// the focus, if any. While the focus is confined to a scope that does not
+// contain that node, they are consumed instead.
+func (m *FocusManager) OnKeyEvent(e key.Event) EventHandled {
+	scope := m.scope()
+	if e.Code == key.CodeTab && e.Modifiers&^key.ModShift == 0 {
+		if e.Direction != key.DirRelease {
+			m.move(scope, e.Modifiers&key.ModShift != 0)
+		}
+		return Handled
+	}
+	if m.focus == nil {
+		if scope != m.Root {
+			return Handled
+		}
+		return NotHandled
+	}
+	if !contains(scope, m.focus) {
+		return Handled
+	}
+	// The origin is that of the focused node's parent.
+	origin := image.Point{}
+	for p := m.focus.Wrappee().Parent; p != nil; p = p.Parent {
+		origin = origin.Add(p.Rect.Min)
+	}
+	return m.focus.OnInputEvent(e, origin)
+}
+
+// scope returns the subtree that the focus is confined to, which is m.Root if
+// it is not confined. A FocusScoper's scope must be one of its descendants.
+func (m *FocusManager) scope() Node {
+	scope := m.Root
+	for {
+		var inner Node
+		Walk(scope, func(n Node) bool {
+			if s, ok := n.(FocusScoper); ok {
+				if ss := s.FocusScope(); ss != nil {
+					inner = ss
+					return false
+				}
+			}
+			return true
+		}, nil)
+		if inner == nil {
+			return scope
+		}
+		scope = inner
+	}
+}
+
+// contains returns whether n is root or one of its descendants.
+func contains(root, n Node) bool {
+	r := root.Wrappee()
+	for e := n.Wrappee(); e != nil; e = e.Parent {
+		if e == r {
+			return true
+		}
+	}
+	return false
+}
+
+// move moves the focus to the next, or if backwards is true the previous,
+// node within scope that accepts the focus.
+func (m *FocusManager) move(scope Node, backwards bool) {
+	var nodes []Node
+	i := -1
+	Walk(scope, func(n Node) bool {
+		if n == m.focus {
+			i = len(nodes)
+		}
+		if f, ok := n.(Focuser); (ok && f.AcceptsFocus()) || n == m.focus {
+			nodes = append(nodes, n)
+		}
+		return true
+	}, nil)
+
+	switch {
+	case len(nodes) == 0:
+		m.focus = nil
+		return
+	case i < 0 && backwards:
+		i = 0
+	}
+	for range nodes {
+		if backwards {
+			i = (i + len(nodes) - 1) % len(nodes)
+		} else {
+			i = (i + 1) % len(nodes)
+		}
+		if f, ok := nodes[i].(Focuser); ok && f.AcceptsFocus() {
+			m.focus = nodes[i]
+			return
+		}
+	}
+	m.focus = nil
+}
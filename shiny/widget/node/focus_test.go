@@ -0,0 +1,119 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/mobile/event/key"
+)
+
+type testContainer struct {
+	ContainerEmbed
+}
+
+func newTestContainer(children ...Node) *testContainer {
+	w := &testContainer{}
+	w.Wrapper = w
+	for _, c := range children {
+		w.Insert(c, nil)
+	}
+	return w
+}
+
+type testLeaf struct {
+	LeafEmbed
+	name      string
+	focusable bool
+	runes     []rune
+}
+
+func newTestLeaf(name string, focusable bool) *testLeaf {
+	w := &testLeaf{name: name, focusable: focusable}
+	w.Wrapper = w
+	return w
+}
+
+func (w *testLeaf) AcceptsFocus() bool { return w.focusable }
+
+func (w *testLeaf) OnInputEvent(e interface{}, origin image.Point) EventHandled {
+	if e, ok := e.(key.Event); ok {
+		w.runes = append(w.runes, e.Rune)
+		return Handled
+	}
+	return NotHandled
+}
+
+func TestFocusManager(t *testing.T) {
+	a := newTestLeaf("a", true)
+	b := newTestLeaf("b", false)
+	c := newTestLeaf("c", true)
+	d := newTestLeaf("d", true)
+	root := newTestContainer(a, b, newTestContainer(c, newTestLeaf("x", false)), d)
+	m := &FocusManager{Root: root}
+
+	focusName := func() string {
+		if l, ok := m.Focus().(*testLeaf); ok {
+			return l.name
+		}
+		return "<nil>"
+	}
+	tab := key.Event{Code: key.CodeTab, Direction: key.DirPress}
+	shiftTab := key.Event{Code: key.CodeTab, Modifiers: key.ModShift, Direction: key.DirPress}
+
+	if got := m.OnKeyEvent(key.Event{Rune: 'q', Direction: key.DirPress}); got != NotHandled {
+		t.Errorf("key with no focus: got %v, want NotHandled", got)
+	}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		m.OnKeyEvent(tab)
+		got = append(got, focusName())
+	}
+	for i := 0; i < 4; i++ {
+		m.OnKeyEvent(shiftTab)
+		got = append(got, focusName())
+	}
+	want := []string{"a", "c", "d", "a", "d", "c", "a", "d"}
+	if len(got) != len(want) {
+		t.Fatalf("focus order: got %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("focus order: got %q, want %q", got, want)
+		}
+	}
+
+	// Releasing Tab does not move the focus, and other keys go to the
+	// focused node.
+	m.OnKeyEvent(key.Event{Code: key.CodeTab, Direction: key.DirRelease})
+	m.OnKeyEvent(key.Event{Rune: 'z', Direction: key.DirPress})
+	if got := focusName(); got != "d" {
+		t.Errorf("after releasing Tab: focus is %q, want %q", got, "d")
+	}
+	if string(d.runes) != "z" || len(a.runes) != 0 {
+		t.Errorf("key events: a got %q, d got %q, want \"\" and \"z\"", string(a.runes), string(d.runes))
+	}
+
+	// A node that no longer accepts the focus is skipped, but the focus
+	// still moves on from it.
+	c.focusable = false
+	m.SetFocus(a)
+	m.OnKeyEvent(tab)
+	if got := focusName(); got != "d" {
+		t.Errorf("skipping c: focus is %q, want %q", got, "d")
+	}
+	d.focusable = false
+	m.OnKeyEvent(shiftTab)
+	if got := focusName(); got != "a" {
+		t.Errorf("from unfocusable d: focus is %q, want %q", got, "a")
+	}
+	a.focusable = false
+	m.OnKeyEvent(tab)
+	if got := focusName(); got != "<nil>" {
+		t.Errorf("nothing focusable: focus is %q, want <nil>", got)
+	}
+}
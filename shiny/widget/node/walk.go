@@ -0,0 +1,34 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+// Walk traverses the tree rooted at root in depth-first order.
+//
+// pre is called for each node before its children are visited. If it returns
+// false, the node's children are skipped, and post is not called for that
+// node. post is called for each node after its children are visited. If it
+// returns false, the traversal stops: no further nodes are visited.
+//
+// Either pre or post may be nil, in which case it is treated as always
+// returning true.
+//
+// The tree should not be modified during the traversal.
+func Walk(root Node, pre, post func(Node) bool) {
+	walk(root.Wrappee(), pre, post)
+}
+
+// walk walks the tree rooted at n. It returns false if the traversal has been
+// stopped.
+func walk(n *Embed, pre, post func(Node) bool) bool {
+	if pre != nil && !pre(n.Wrapper) {
+		return true
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if !walk(c, pre, post) {
+			return false
+		}
+	}
+	return post == nil || post(n.Wrapper)
+}
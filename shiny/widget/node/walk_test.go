@@ -0,0 +1,100 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package node
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	// The tree is:
+	//
+	//	root
+	//	├── a
+	//	├── p
+	//	│   ├── b
+	//	│   └── c
+	//	└── q
+	//	    └── d
+	names := map[Node]string{}
+	leaf := func(name string) Node {
+		n := newTestLeaf(name, false)
+		names[n] = name
+		return n
+	}
+	container := func(name string, children ...Node) Node {
+		n := newTestContainer(children...)
+		names[n] = name
+		return n
+	}
+	root := container("root",
+		leaf("a"),
+		container("p", leaf("b"), leaf("c")),
+		container("q", leaf("d")),
+	)
+
+	testCases := []struct {
+		desc     string
+		skip     string // the node for which pre returns false
+		stop     string // the node for which post returns false
+		nilPre   bool
+		nilPost  bool
+		wantPre  string
+		wantPost string
+	}{{
+		desc:     "all",
+		wantPre:  "root a p b c q d",
+		wantPost: "a b c p d q root",
+	}, {
+		desc:     "skip subtree",
+		skip:     "p",
+		wantPre:  "root a p q d",
+		wantPost: "a d q root",
+	}, {
+		desc:     "skip root",
+		skip:     "root",
+		wantPre:  "root",
+		wantPost: "",
+	}, {
+		desc:     "stop",
+		stop:     "b",
+		wantPre:  "root a p b",
+		wantPost: "a b",
+	}, {
+		desc:     "nil pre",
+		nilPre:   true,
+		wantPost: "a b c p d q root",
+	}, {
+		desc:    "nil post",
+		nilPost: true,
+		wantPre: "root a p b c q d",
+	}}
+
+	for _, tc := range testCases {
+		var gotPre, gotPost []string
+		pre := func(n Node) bool {
+			gotPre = append(gotPre, names[n])
+			return names[n] != tc.skip
+		}
+		post := func(n Node) bool {
+			gotPost = append(gotPost, names[n])
+			return names[n] != tc.stop
+		}
+		if tc.nilPre {
+			pre = nil
+		}
+		if tc.nilPost {
+			post = nil
+		}
+		Walk(root, pre, post)
+		if got := strings.Join(gotPre, " "); got != tc.wantPre {
+			t.Errorf("%s: pre: got %q, want %q", tc.desc, got, tc.wantPre)
+		}
+		if got := strings.Join(gotPost, " "); got != tc.wantPost {
+			t.Errorf("%s: post: got %q, want %q", tc.desc, got, tc.wantPost)
+		}
+	}
+}
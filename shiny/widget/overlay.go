@@ -0,0 +1,195 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// defaultScrimColor is the ScrimColor used when an Overlay's is nil.
+var defaultScrimColor = theme.StaticColor(color.RGBA{0x00, 0x00, 0x00, 0x80})
+
+// Overlay is a container widget for modal dialogs. It paints its base widget,
+// then a semi-transparent scrim over the base widget, then its overlay widget,
+// centered at its measured size.
+//
+// While an overlay widget is present, all input events are routed to it. Those
+// that miss it, such as a click on the scrim, are consumed, and a mouse press
+// on the scrim calls OnDismiss, if non-nil. The rest of that press, up to its
+// mouse release and the end of its gesture, is consumed too, even if OnDismiss
+// removed the overlay widget, so that it does not click through to the base
+// widget.
+//
+// Overlay implements node.FocusScoper, so that a node.FocusManager also keeps
+// the keyboard focus, and key events, within the overlay widget while it is
+// present.
+//
+// The overlay widget is wrapped in a Sheet, so that its base pass is painted
+// above the scrim, which is painted in the effects pass.
+type Overlay struct {
+	node.ContainerEmbed
+	ScrimColor theme.Color
+	OnDismiss  func()
+
+	base  node.Node
+	sheet *Sheet
+
+	// swallowMouse and swallowGesture are set by a press that calls
+	// OnDismiss, until that press's mouse release and gesture end.
+	swallowMouse   bool
+	swallowGesture bool
+}
+
+// NewOverlay returns a new Overlay widget. The overlay widget may be nil, in
+// which case the Overlay paints and handles input just like its base widget.
+func NewOverlay(base, overlay node.Node) *Overlay {
+	w := &Overlay{
+		base:  base,
+		sheet: NewSheet(overlay),
+	}
+	w.Wrapper = w
+	w.Insert(base, nil)
+	w.Insert(w.sheet, nil)
+	return w
+}
+
+// Overlay returns the overlay widget, or nil if there is none.
+func (w *Overlay) Overlay() node.Node {
+	if c := w.sheet.FirstChild; c != nil {
+		return c.Wrapper
+	}
+	return nil
+}
+
+// FocusScope implements node.FocusScoper. It returns the overlay widget, or
+// nil if there is none.
+func (w *Overlay) FocusScope() node.Node {
+	return w.Overlay()
+}
+
+// SetOverlay replaces the overlay widget, and marks the Overlay as needing
+// measure, layout and paint. A nil overlay dismisses the current one.
+func (w *Overlay) SetOverlay(overlay node.Node) {
+	if c := w.sheet.FirstChild; c != nil {
+		w.sheet.Remove(c.Wrapper)
+	}
+	if overlay != nil {
+		w.sheet.Insert(overlay, nil)
+	}
+	w.Mark(node.MarkNeedsMeasureLayout | node.MarkNeedsPaint)
+}
+
+func (w *Overlay) Layout(t *theme.Theme) {
+	r := w.Rect.Sub(w.Rect.Min)
+	b := w.base.Wrappee()
+	b.Rect = r
+	b.Wrapper.Layout(t)
+
+	s := w.sheet
+	if s.FirstChild == nil {
+		s.Rect = image.Rectangle{}
+		return
+	}
+	size := s.MeasuredSize
+	if size.X > r.Dx() {
+		size.X = r.Dx()
+	}
+	if size.Y > r.Dy() {
+		size.Y = r.Dy()
+	}
+	p := r.Min.Add(r.Size().Sub(size).Div(2))
+	s.Rect = image.Rectangle{Min: p, Max: p.Add(size)}
+	s.Layout(t)
+}
+
+func (w *Overlay) Paint(ctx *node.PaintContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaint()
+	origin = origin.Add(w.Rect.Min)
+	if err := w.base.Paint(ctx, origin); err != nil {
+		return err
+	}
+	if w.sheet.FirstChild == nil {
+		return nil
+	}
+
+	c := w.ScrimColor
+	if c == nil {
+		c = defaultScrimColor
+	}
+	src2dst := ctx.Src2Dst
+	translate(&src2dst, float64(origin.X), float64(origin.Y))
+	ctx.Drawer.DrawUniform(src2dst, c.Color(ctx.Theme), image.Rectangle{Max: w.Rect.Size()}, screen.Over, nil)
+
+	return w.sheet.Paint(ctx, origin)
+}
+
+func (w *Overlay) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	// Only the base widget is painted onto our ancestor's buffer. The overlay
+	// widget is painted onto the Sheet's buffer by Overlay.Paint.
+	return w.base.PaintBase(ctx, origin.Add(w.Rect.Min))
+}
+
+func (w *Overlay) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	origin = origin.Add(w.Rect.Min)
+	if w.swallow(e) {
+		return node.Handled
+	}
+	s := w.sheet
+	if s.FirstChild == nil {
+		return w.base.OnInputEvent(e, origin)
+	}
+
+	onScrim, dismiss := false, false
+	switch e := e.(type) {
+	case gesture.Event:
+		p := image.Point{
+			X: int(e.CurrentPos.X) - origin.X,
+			Y: int(e.CurrentPos.Y) - origin.Y,
+		}
+		onScrim = !p.In(s.Rect)
+	case mouse.Event:
+		p := image.Point{
+			X: int(e.X) - origin.X,
+			Y: int(e.Y) - origin.Y,
+		}
+		onScrim = !p.In(s.Rect)
+		dismiss = e.Direction == mouse.DirPress
+	}
+
+	if !onScrim {
+		s.OnInputEvent(e, origin)
+	} else if dismiss && w.OnDismiss != nil {
+		w.swallowMouse, w.swallowGesture = true, true
+		w.OnDismiss()
+	}
+	// The overlay is modal: no event reaches the base widget.
+	return node.Handled
+}
+
+// swallow returns whether e is part of a press that called OnDismiss, and so
+// should be consumed.
+func (w *Overlay) swallow(e interface{}) bool {
+	switch e := e.(type) {
+	case gesture.Event:
+		if w.swallowGesture {
+			w.swallowGesture = e.Type != gesture.TypeEnd
+			return true
+		}
+	case mouse.Event:
+		if w.swallowMouse {
+			w.swallowMouse = e.Direction != mouse.DirRelease
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/screen"
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+	"golang.org/x/image/math/f64"
+	"golang.org/x/mobile/event/key"
+	"golang.org/x/mobile/event/mouse"
+)
+
+// memScreen is a screen.Screen whose buffers and textures are in memory. It
+// cannot create windows.
+type memScreen struct{}
+
+func (memScreen) NewBuffer(size image.Point) (screen.Buffer, error) {
+	return memBuffer{image.NewRGBA(image.Rectangle{Max: size})}, nil
+}
+
+func (memScreen) NewTexture(size image.Point) (screen.Texture, error) {
+	return memBuffer{image.NewRGBA(image.Rectangle{Max: size})}, nil
+}
+
+func (memScreen) NewWindow(opts *screen.NewWindowOptions) (screen.Window, error) {
+	panic("unimplemented")
+}
+
+// memBuffer is both a screen.Buffer and a screen.Texture.
+type memBuffer struct{ m *image.RGBA }
+
+func (b memBuffer) Release()                {}
+func (b memBuffer) Size() image.Point       { return b.m.Rect.Size() }
+func (b memBuffer) Bounds() image.Rectangle { return b.m.Rect }
+func (b memBuffer) RGBA() *image.RGBA       { return b.m }
+
+func (b memBuffer) Upload(dp image.Point, src screen.Buffer, sr image.Rectangle) {
+	draw.Draw(b.m, sr.Sub(sr.Min).Add(dp), src.RGBA(), sr.Min, draw.Src)
+}
+
+func (b memBuffer) Fill(dr image.Rectangle, src color.Color, op draw.Op) {
+	draw.Draw(b.m, dr, image.NewUniform(src), image.Point{}, op)
+}
+
+// memDrawer is a screen.Drawer that draws onto an image. It only supports
+// translations.
+type memDrawer struct{ dst *image.RGBA }
+
+func (d memDrawer) dr(src2dst f64.Aff3, sr image.Rectangle) image.Rectangle {
+	if src2dst[0] != 1 || src2dst[1] != 0 || src2dst[3] != 0 || src2dst[4] != 1 {
+		panic("unimplemented")
+	}
+	return sr.Add(image.Point{int(src2dst[2]), int(src2dst[5])})
+}
+
+func (d memDrawer) Draw(src2dst f64.Aff3, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	draw.Draw(d.dst, d.dr(src2dst, sr), src.(memBuffer).m, sr.Min, op)
+}
+
+func (d memDrawer) DrawUniform(src2dst f64.Aff3, src color.Color, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	draw.Draw(d.dst, d.dr(src2dst, sr), image.NewUniform(src), image.Point{}, op)
+}
+
+func (d memDrawer) Copy(dp image.Point, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	panic("unimplemented")
+}
+
+func (d memDrawer) Scale(dr image.Rectangle, src screen.Texture, sr image.Rectangle, op draw.Op, opts *screen.DrawOptions) {
+	panic("unimplemented")
+}
+
+func TestOverlayPaint(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	scrim := color.RGBA{0x00, 0x00, 0x00, 0x80}
+	dimmed := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	dimmed.SetRGBA(0, 0, blue)
+	draw.Draw(dimmed, dimmed.Bounds(), image.NewUniform(scrim), image.Point{}, draw.Over)
+	dimmedBlue := dimmed.RGBAAt(0, 0)
+
+	w := NewOverlay(
+		NewUniform(theme.StaticColor(blue), nil),
+		NewSizer(unit.Pixels(6), unit.Pixels(4), NewUniform(theme.StaticColor(red), nil)),
+	)
+	w.ScrimColor = theme.StaticColor(scrim)
+	w.Measure(theme.Default, node.NoHint, node.NoHint)
+	w.Rect = image.Rect(0, 0, 20, 10)
+	w.Layout(theme.Default)
+
+	// The overlay is centered within the Overlay.
+	wantOverlay := image.Rect(7, 3, 13, 7)
+
+	// Paint the base pass and then the effects pass, as a Sheet ancestor
+	// would.
+	dst := image.NewRGBA(image.Rect(0, 0, 30, 20))
+	origin := image.Point{4, 5}
+	if err := w.PaintBase(&node.PaintBaseContext{Theme: theme.Default, Dst: dst}, origin); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Paint(&node.PaintContext{
+		Theme:   theme.Default,
+		Screen:  memScreen{},
+		Drawer:  memDrawer{dst},
+		Src2Dst: f64.Aff3{1, 0, 0, 0, 1, 0},
+	}, origin); err != nil {
+		t.Fatal(err)
+	}
+
+	b := dst.Bounds()
+loop:
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			want := color.RGBA{}
+			if p := (image.Point{x, y}).Sub(origin); p.In(wantOverlay) {
+				want = red
+			} else if p.In(w.Rect) {
+				want = dimmedBlue
+			}
+			if got := dst.RGBAAt(x, y); got != want {
+				t.Errorf("(%d, %d): got %v, want %v", x, y, got, want)
+				break loop
+			}
+		}
+	}
+}
+
+// inputRecorder is a leaf widget that accepts the focus and records the input
+// events it receives.
+type inputRecorder struct {
+	node.LeafEmbed
+	events int
+}
+
+func newInputRecorder() *inputRecorder {
+	w := &inputRecorder{}
+	w.Wrapper = w
+	return w
+}
+
+func (w *inputRecorder) AcceptsFocus() bool { return true }
+
+func (w *inputRecorder) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	w.events++
+	return node.Handled
+}
+
+func TestOverlayInput(t *testing.T) {
+	base, overlay := newInputRecorder(), newInputRecorder()
+	w := NewOverlay(base, NewSizer(unit.Pixels(6), unit.Pixels(4), overlay))
+	dismissed := 0
+	w.OnDismiss = func() {
+		dismissed++
+		w.SetOverlay(nil)
+	}
+	w.Measure(theme.Default, node.NoHint, node.NoHint)
+	w.Rect = image.Rect(0, 0, 20, 10)
+	w.Layout(theme.Default)
+
+	press := func(x, y float32) node.EventHandled {
+		return w.OnInputEvent(mouse.Event{X: x, Y: y, Direction: mouse.DirPress}, image.Point{})
+	}
+
+	// A press on the overlay goes to the overlay.
+	if got := press(10, 5); got != node.Handled {
+		t.Errorf("press on overlay: got %v, want Handled", got)
+	}
+	if base.events != 0 || overlay.events != 1 || dismissed != 0 {
+		t.Fatalf("press on overlay: base, overlay, dismissed = %d, %d, %d, want 0, 1, 0",
+			base.events, overlay.events, dismissed)
+	}
+
+	// A press on the scrim is consumed and dismisses the overlay.
+	if got := press(1, 1); got != node.Handled {
+		t.Errorf("press on scrim: got %v, want Handled", got)
+	}
+	if base.events != 0 || overlay.events != 1 || dismissed != 1 {
+		t.Fatalf("press on scrim: base, overlay, dismissed = %d, %d, %d, want 0, 1, 1",
+			base.events, overlay.events, dismissed)
+	}
+	if w.Overlay() != nil {
+		t.Fatalf("Overlay: got non-nil, want nil")
+	}
+
+	// With no overlay, input goes to the base widget, once the dismissing
+	// press is released.
+	w.Layout(theme.Default)
+	w.OnInputEvent(mouse.Event{X: 1, Y: 1, Direction: mouse.DirRelease}, image.Point{})
+	press(10, 5)
+	if base.events != 1 || overlay.events != 1 || dismissed != 1 {
+		t.Fatalf("no overlay: base, overlay, dismissed = %d, %d, %d, want 1, 1, 1",
+			base.events, overlay.events, dismissed)
+	}
+}
+
+func TestOverlayDismissClick(t *testing.T) {
+	base, overlay := newInputRecorder(), newInputRecorder()
+	w := NewOverlay(base, NewSizer(unit.Pixels(6), unit.Pixels(4), overlay))
+	dismissed := 0
+	w.OnDismiss = func() {
+		dismissed++
+		w.SetOverlay(nil)
+	}
+	w.Measure(theme.Default, node.NoHint, node.NoHint)
+	w.Rect = image.Rect(0, 0, 20, 10)
+	w.Layout(theme.Default)
+
+	// RunWindow delivers a click on the scrim as both mouse events and the
+	// gesture events built from them. The click dismisses the overlay once,
+	// and none of it reaches the base widget.
+	pos := gesture.Point{X: 1, Y: 1}
+	click := []interface{}{
+		mouse.Event{X: 1, Y: 1, Direction: mouse.DirPress},
+		gesture.Event{Type: gesture.TypeStart, CurrentPos: pos},
+		mouse.Event{X: 1, Y: 1, Direction: mouse.DirRelease},
+		gesture.Event{Type: gesture.TypeTap, CurrentPos: pos},
+		gesture.Event{Type: gesture.TypeEnd, CurrentPos: pos},
+	}
+	for i, e := range click {
+		if got := w.OnInputEvent(e, image.Point{}); got != node.Handled {
+			t.Errorf("event #%d: got %v, want Handled", i, got)
+		}
+	}
+	if base.events != 0 || dismissed != 1 {
+		t.Fatalf("click on scrim: base, dismissed = %d, %d, want 0, 1", base.events, dismissed)
+	}
+
+	// The next click goes to the base widget.
+	for _, e := range click {
+		w.OnInputEvent(e, image.Point{})
+	}
+	if base.events != len(click) || dismissed != 1 {
+		t.Errorf("click after dismissal: base, dismissed = %d, %d, want %d, 1", base.events, dismissed, len(click))
+	}
+}
+
+func TestOverlayFocus(t *testing.T) {
+	base, overlay := newInputRecorder(), newInputRecorder()
+	w := NewOverlay(base, NewSizer(unit.Pixels(6), unit.Pixels(4), overlay))
+	m := &node.FocusManager{Root: w}
+	tab := key.Event{Code: key.CodeTab, Direction: key.DirPress}
+	keyQ := key.Event{Rune: 'q', Direction: key.DirPress}
+
+	// The base widget was focused before the overlay opened. Key events no
+	// longer reach it.
+	m.SetFocus(base)
+	if got := m.OnKeyEvent(keyQ); got != node.Handled {
+		t.Errorf("key under overlay: got %v, want Handled", got)
+	}
+	if base.events != 0 {
+		t.Errorf("key under overlay: base got %d events, want 0", base.events)
+	}
+
+	// Tab cannot reach the base widget while the overlay is open.
+	for i := 0; i < 3; i++ {
+		m.OnKeyEvent(tab)
+		if got := m.Focus(); got != node.Node(overlay) {
+			t.Fatalf("Tab #%d: focus is %v, want the overlay widget", i, got)
+		}
+	}
+	m.OnKeyEvent(keyQ)
+	if base.events != 0 || overlay.events != 1 {
+		t.Errorf("key on overlay: base, overlay = %d, %d, want 0, 1", base.events, overlay.events)
+	}
+
+	// Once the overlay is dismissed, Tab reaches the base widget again.
+	w.SetOverlay(nil)
+	m.OnKeyEvent(tab)
+	if got := m.Focus(); got != node.Node(base) {
+		t.Fatalf("Tab after dismissal: focus is %v, want the base widget", got)
+	}
+	m.OnKeyEvent(keyQ)
+	if base.events != 1 || overlay.events != 1 {
+		t.Errorf("key after dismissal: base, overlay = %d, %d, want 1, 1", base.events, overlay.events)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+const (
+	// barWidth is the natural width, in DIPs, of a ProgressBar or Slider.
+	barWidth = 128
+	// barThickness is the natural height, in DIPs, of a ProgressBar and the
+	// height of a Slider's track.
+	barThickness = 4
+	// thumbSize is the natural height, in DIPs, of a Slider and the width and
+	// height of its thumb.
+	thumbSize = 16
+)
+
+// clamp01 returns x clamped to the range [0, 1]. NaN is treated as 0.
+func clamp01(x float64) float64 {
+	if !(x > 0) {
+		return 0
+	}
+	if x > 1 {
+		return 1
+	}
+	return x
+}
+
+// paintBar paints a horizontal bar into r: a track spanning r and, on top of
+// it, a fill spanning the leftmost fraction v of r.
+func paintBar(dst *image.RGBA, t *theme.Theme, r image.Rectangle, v float64, track, fill theme.Color) {
+	if track == nil {
+		track = theme.Neutral
+	}
+	if fill == nil {
+		fill = theme.Accent
+	}
+	draw.Draw(dst, r, track.Uniform(t), image.Point{}, draw.Src)
+	fr := r
+	fr.Max.X = fr.Min.X + int(clamp01(v)*float64(r.Dx())+0.5)
+	draw.Draw(dst, fr, fill.Uniform(t), image.Point{}, draw.Src)
+}
+
+// ProgressBar is a leaf widget that shows how far an operation has
+// progressed, as a horizontal track that is filled from the left.
+//
+// Value is the fraction of the track to fill, from 0 to 1. Values outside of
+// that range are clamped. Nil TrackColor and FillColor default to
+// theme.Neutral and theme.Accent.
+type ProgressBar struct {
+	node.LeafEmbed
+	Value      float64
+	TrackColor theme.Color
+	FillColor  theme.Color
+}
+
+// NewProgressBar returns a new ProgressBar widget showing the given value.
+func NewProgressBar(value float64) *ProgressBar {
+	w := &ProgressBar{
+		Value: value,
+	}
+	w.Wrapper = w
+	return w
+}
+
+// SetValue sets the ProgressBar's value and marks it as needing paint.
+func (w *ProgressBar) SetValue(value float64) {
+	if w.Value == value {
+		return
+	}
+	w.Value = value
+	w.Mark(node.MarkNeedsPaintBase)
+}
+
+func (w *ProgressBar) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = image.Point{
+		X: dipsAtLeast1(t, barWidth),
+		Y: dipsAtLeast1(t, barThickness),
+	}
+}
+
+func (w *ProgressBar) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	paintBar(ctx.Dst, ctx.Theme, w.Rect.Add(origin), w.Value, w.TrackColor, w.FillColor)
+	return nil
+}
+
+// Slider is a leaf widget that lets the user pick a value from 0 to 1 by
+// tapping or dragging a thumb along a horizontal track.
+//
+// OnChange, if non-nil, is called with the new value whenever a tap or drag
+// gesture changes it. Nil TrackColor, FillColor and ThumbColor default to
+// theme.Neutral, theme.Accent and theme.Foreground.
+type Slider struct {
+	node.LeafEmbed
+	Value      float64
+	OnChange   func(value float64)
+	TrackColor theme.Color
+	FillColor  theme.Color
+	ThumbColor theme.Color
+
+	// thumb is the thumb size, in pixels, as of the most recent Measure or
+	// PaintBase call.
+	thumb int
+}
+
+// NewSlider returns a new Slider widget with the given initial value and
+// change callback.
+func NewSlider(value float64, onChange func(float64)) *Slider {
+	w := &Slider{
+		Value:    clamp01(value),
+		OnChange: onChange,
+	}
+	w.Wrapper = w
+	return w
+}
+
+func (w *Slider) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.thumb = dipsAtLeast1(t, thumbSize)
+	w.MeasuredSize = image.Point{
+		X: dipsAtLeast1(t, barWidth),
+		Y: w.thumb,
+	}
+}
+
+// travel returns the horizontal range, in the same coordinate space as r,
+// that the thumb's center can move along. The thumb stays within r.
+func (w *Slider) travel(r image.Rectangle) (x0, x1 int) {
+	half := w.thumb / 2
+	x0, x1 = r.Min.X+half, r.Max.X-(w.thumb-half)
+	if x1 < x0 {
+		x1 = x0
+	}
+	return x0, x1
+}
+
+func (w *Slider) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	w.thumb = dipsAtLeast1(ctx.Theme, thumbSize)
+	r := w.Rect.Add(origin)
+
+	x0, x1 := w.travel(r)
+	track := image.Rect(x0, r.Min.Y, x1, r.Max.Y)
+	if n := dipsAtLeast1(ctx.Theme, barThickness); track.Dy() > n {
+		track.Min.Y += (track.Dy() - n) / 2
+		track.Max.Y = track.Min.Y + n
+	}
+	paintBar(ctx.Dst, ctx.Theme, track, w.Value, w.TrackColor, w.FillColor)
+
+	tc := w.ThumbColor
+	if tc == nil {
+		tc = theme.Foreground
+	}
+	cx := x0 + int(clamp01(w.Value)*float64(x1-x0)+0.5)
+	cy := r.Min.Y + r.Dy()/2
+	thumb := image.Rect(0, 0, w.thumb, w.thumb).Add(image.Point{cx - w.thumb/2, cy - w.thumb/2})
+	draw.Draw(ctx.Dst, thumb.Intersect(r), tc.Uniform(ctx.Theme), image.Point{}, draw.Src)
+	return nil
+}
+
+func (w *Slider) OnInputEvent(e interface{}, origin image.Point) node.EventHandled {
+	ge, ok := e.(gesture.Event)
+	if !ok {
+		return node.NotHandled
+	}
+	switch ge.Type {
+	case gesture.TypeTap, gesture.TypeIsDrag, gesture.TypeDrag:
+	default:
+		return node.NotHandled
+	}
+
+	x0, x1 := w.travel(w.Rect.Add(origin))
+	v := 0.0
+	if x1 > x0 {
+		v = clamp01((float64(ge.CurrentPos.X) - float64(x0)) / float64(x1-x0))
+	}
+	if v != w.Value {
+		w.Value = v
+		w.Mark(node.MarkNeedsPaintBase)
+		if w.OnChange != nil {
+			w.OnChange(v)
+		}
+	}
+	return node.Handled
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/gesture"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+func TestProgressBarMeasure(t *testing.T) {
+	testCases := []struct {
+		dpi  float64
+		want image.Point
+	}{
+		{160, image.Point{128, 4}},
+		{320, image.Point{256, 8}},
+	}
+	for _, tc := range testCases {
+		w := NewProgressBar(0.5)
+		w.Measure(&theme.Theme{DPI: tc.dpi}, node.NoHint, node.NoHint)
+		if got := w.MeasuredSize; got != tc.want {
+			t.Errorf("dpi=%v: got %v, want %v", tc.dpi, got, tc.want)
+		}
+	}
+}
+
+func TestProgressBarPaint(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xff, 0xff}
+	testCases := []struct {
+		value float64
+		// wantFill is the number of filled columns.
+		wantFill int
+	}{
+		{-1, 0},
+		{0, 0},
+		{0.25, 5},
+		{0.5, 10},
+		{0.99, 20},
+		{1, 20},
+		{2, 20},
+	}
+	for _, tc := range testCases {
+		w := NewProgressBar(tc.value)
+		w.TrackColor = theme.StaticColor(blue)
+		w.FillColor = theme.StaticColor(red)
+		w.Rect = image.Rect(0, 0, 20, 3)
+		dst := image.NewRGBA(image.Rect(0, 0, 30, 10))
+		ctx := &node.PaintBaseContext{Theme: theme.Default, Dst: dst}
+		origin := image.Point{4, 5}
+		if err := w.PaintBase(ctx, origin); err != nil {
+			t.Fatal(err)
+		}
+		b := dst.Bounds()
+	loop:
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				want := color.RGBA{}
+				if p := (image.Point{x, y}).Sub(origin); p.In(w.Rect) {
+					want = blue
+					if p.X < tc.wantFill {
+						want = red
+					}
+				}
+				if got := dst.RGBAAt(x, y); got != want {
+					t.Errorf("value=%v: (%d, %d): got %v, want %v", tc.value, x, y, got, want)
+					break loop
+				}
+			}
+		}
+	}
+}
+
+func TestSliderDrag(t *testing.T) {
+	var changes []float64
+	w := NewSlider(0.5, func(v float64) { changes = append(changes, v) })
+	w.Measure(&theme.Theme{DPI: 160}, node.NoHint, node.NoHint)
+	// With a 16 pixel thumb, the thumb's center travels from x=8 to x=108.
+	w.Rect = image.Rect(0, 0, 116, 16)
+	origin := image.Point{100, 50}
+
+	events := []struct {
+		typ  gesture.Type
+		x    float32
+		want float64
+	}{
+		{gesture.TypeIsDrag, 100 + 8 + 25, 0.25},
+		{gesture.TypeDrag, 100 + 8 + 75, 0.75},
+		{gesture.TypeDrag, 100 + 8 + 75, 0.75},
+		{gesture.TypeDrag, 0, 0},
+		{gesture.TypeDrag, 1000, 1},
+		{gesture.TypeEnd, 100 + 8 + 50, 1},
+	}
+	for i, ev := range events {
+		e := gesture.Event{
+			Type:       ev.typ,
+			Drag:       true,
+			CurrentPos: gesture.Point{X: ev.x, Y: 58},
+		}
+		w.OnInputEvent(e, origin)
+		if w.Value != ev.want {
+			t.Errorf("event #%d: Value: got %v, want %v", i, w.Value, ev.want)
+		}
+	}
+	if got, want := changes, []float64{0.25, 0.75, 0, 1}; len(got) != len(want) {
+		t.Fatalf("changes: got %v, want %v", got, want)
+	} else {
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("changes: got %v, want %v", got, want)
+			}
+		}
+	}
+	if !w.Marks.NeedsPaintBase() {
+		t.Errorf("NeedsPaintBase: got false, want true")
+	}
+}
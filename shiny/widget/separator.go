@@ -0,0 +1,95 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/draw"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+// Separator is a leaf widget that paints a thin line, such as a divider
+// between the items of a menu or the sections of a form.
+//
+// An AxisHorizontal Separator is a horizontal line that spans the width of
+// its Rect, and an AxisVertical Separator is a vertical line that spans its
+// height. The line is one density independent pixel thick, but never thinner
+// than one physical pixel.
+type Separator struct {
+	node.LeafEmbed
+	Axis       Axis
+	ThemeColor theme.Color
+}
+
+// NewSeparator returns a new Separator widget along the given axis.
+func NewSeparator(a Axis) *Separator {
+	w := &Separator{
+		Axis: a,
+	}
+	w.Wrapper = w
+	return w
+}
+
+func (w *Separator) Measure(t *theme.Theme, widthHint, heightHint int) {
+	w.MeasuredSize = image.Point{}
+	switch w.Axis {
+	case AxisHorizontal:
+		w.MeasuredSize.Y = dipsAtLeast1(t, 1)
+	case AxisVertical:
+		w.MeasuredSize.X = dipsAtLeast1(t, 1)
+	}
+}
+
+func (w *Separator) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
+	w.Marks.UnmarkNeedsPaintBase()
+	r := w.Rect
+	n := dipsAtLeast1(ctx.Theme, 1)
+	switch w.Axis {
+	case AxisHorizontal:
+		if r.Dy() > n {
+			r.Min.Y += (r.Dy() - n) / 2
+			r.Max.Y = r.Min.Y + n
+		}
+	case AxisVertical:
+		if r.Dx() > n {
+			r.Min.X += (r.Dx() - n) / 2
+			r.Max.X = r.Min.X + n
+		}
+	default:
+		return nil
+	}
+
+	tc := w.ThemeColor
+	if tc == nil {
+		tc = theme.Neutral
+	}
+	draw.Draw(ctx.Dst, r.Add(origin), tc.Uniform(ctx.Theme), image.Point{}, draw.Src)
+	return nil
+}
+
+// Spacer is a leaf widget that occupies a fixed amount of empty space. Unlike
+// a Space, whose natural size is zero, a Spacer's natural width and height are
+// both Size, converted to pixels by the theme.
+type Spacer struct {
+	node.LeafEmbed
+	Size unit.Value
+}
+
+// NewSpacer returns a new Spacer widget of the given size.
+func NewSpacer(size unit.Value) *Spacer {
+	w := &Spacer{
+		Size: size,
+	}
+	w.Wrapper = w
+	return w
+}
+
+func (w *Spacer) Measure(t *theme.Theme, widthHint, heightHint int) {
+	n := t.Pixels(w.Size).Round()
+	w.MeasuredSize = image.Point{n, n}
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/exp/shiny/unit"
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+func TestSeparatorMeasure(t *testing.T) {
+	testCases := []struct {
+		dpi  float64
+		axis Axis
+		want image.Point
+	}{
+		{0, AxisHorizontal, image.Point{0, 1}},
+		{0, AxisVertical, image.Point{1, 0}},
+		{320, AxisHorizontal, image.Point{0, 2}},
+		{320, AxisVertical, image.Point{2, 0}},
+		{320, AxisNone, image.Point{0, 0}},
+	}
+	for _, tc := range testCases {
+		w := NewSeparator(tc.axis)
+		w.Measure(&theme.Theme{DPI: tc.dpi}, node.NoHint, node.NoHint)
+		if got := w.MeasuredSize; got != tc.want {
+			t.Errorf("dpi=%v, axis=%v: got %v, want %v", tc.dpi, tc.axis, got, tc.want)
+		}
+	}
+}
+
+func TestSpacerMeasure(t *testing.T) {
+	w := NewSpacer(unit.Points(9))
+	w.Measure(&theme.Theme{DPI: 144}, node.NoHint, node.NoHint)
+	if got, want := w.MeasuredSize, (image.Point{18, 18}); got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// TestSeparatorPaint lays out a vertical Flow of a Spacer, an expanding
+// Separator and another Spacer, paints it and checks which pixels are set.
+func TestSeparatorPaint(t *testing.T) {
+	red := color.RGBA{0xff, 0x00, 0x00, 0xff}
+	th := &theme.Theme{DPI: 320}
+	sep := NewSeparator(AxisHorizontal)
+	sep.ThemeColor = theme.StaticColor(red)
+	sep.LayoutData = FlowLayoutData{ExpandAcross: true}
+	w := NewFlow(AxisVertical,
+		NewSpacer(unit.Pixels(5)),
+		sep,
+		NewSpacer(unit.Pixels(5)),
+	)
+	w.Measure(th, node.NoHint, node.NoHint)
+	if got, want := w.MeasuredSize, (image.Point{5, 12}); got != want {
+		t.Fatalf("MeasuredSize: got %v, want %v", got, want)
+	}
+	w.Rect = image.Rect(0, 0, 20, 12)
+	w.Layout(th)
+
+	dst := image.NewRGBA(image.Rect(0, 0, 30, 30))
+	ctx := &node.PaintBaseContext{Theme: th, Dst: dst}
+	origin := image.Point{3, 4}
+	if err := w.PaintBase(ctx, origin); err != nil {
+		t.Fatal(err)
+	}
+	want := image.Rect(0, 5, 20, 7).Add(origin)
+	b := dst.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			got := dst.RGBAAt(x, y)
+			if (image.Point{x, y}).In(want) {
+				if got != red {
+					t.Fatalf("(%d, %d): got %v, want %v", x, y, got, red)
+				}
+			} else if got != (color.RGBA{}) {
+				t.Fatalf("(%d, %d): got %v, want transparent", x, y, got)
+			}
+		}
+	}
+}
+
+func TestSeparatorDefaultColor(t *testing.T) {
+	w := NewSeparator(AxisVertical)
+	w.Rect = image.Rect(0, 0, 5, 4)
+	dst := image.NewRGBA(image.Rect(0, 0, 5, 4))
+	ctx := &node.PaintBaseContext{Theme: theme.Default, Dst: dst}
+	if err := w.PaintBase(ctx, image.Point{}); err != nil {
+		t.Fatal(err)
+	}
+	want := color.RGBAModel.Convert(theme.Neutral.Color(theme.Default)).(color.RGBA)
+	for y := 0; y < 4; y++ {
+		if got := dst.RGBAAt(2, y); got != want {
+			t.Errorf("(2, %d): got %v, want %v", y, got, want)
+		}
+		if got := dst.RGBAAt(1, y); got != (color.RGBA{}) {
+			t.Errorf("(1, %d): got %v, want transparent", y, got)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package widget
 
 import (
+	"bytes"
 	"image"
 	"image/draw"
 
@@ -50,6 +51,10 @@ func (w *Text) setFace(t *theme.Theme) {
 		// physical font.Face values (as each Face may have its own caches)?
 		face := t.AcquireFontFace(theme.FontFaceOptions{})
 		w.frame.SetFace(face)
+		// Tab stops are every eight spaces, as in a terminal.
+		if adv, ok := face.GlyphAdvance(' '); ok {
+			w.frame.SetTabWidth(8 * adv)
+		}
 	}
 }
 
@@ -127,6 +132,7 @@ func (w *Text) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
 		},
 	}
 	f := &w.frame
+	tabWidth := f.TabWidth()
 	for p := f.FirstParagraph(); p != nil; p = p.Next(f) {
 		for l := p.FirstLine(f); l != nil; l = l.Next(f) {
 			if d.Dot.Y > minDotY {
@@ -134,7 +140,19 @@ func (w *Text) PaintBase(ctx *node.PaintBaseContext, origin image.Point) error {
 					return nil
 				}
 				for b := l.FirstBox(f); b != nil; b = b.Next(f) {
-					d.DrawBytes(b.TrimmedText(f))
+					s := b.TrimmedText(f)
+					// A tab is not drawn as a glyph. It advances the dot to
+					// the next tab stop, as the Frame measures it.
+					for tabWidth > 0 {
+						i := bytes.IndexByte(s, '\t')
+						if i < 0 {
+							break
+						}
+						d.DrawBytes(s[:i])
+						d.Dot.X = x0 + ((d.Dot.X-x0)/tabWidth+1)*tabWidth
+						s = s[i+1:]
+					}
+					d.DrawBytes(s)
 					// TODO: adjust d.Dot.X for any ligatures?
 				}
 				d.Dot.X = x0
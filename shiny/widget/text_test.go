@@ -0,0 +1,49 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package widget
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/shiny/widget/node"
+	"golang.org/x/exp/shiny/widget/theme"
+)
+
+func TestTextTabs(t *testing.T) {
+	paint := func(s string) *image.RGBA {
+		w := NewText(s)
+		w.Measure(theme.Default, 400, node.NoHint)
+		w.Rect = image.Rectangle{Max: w.MeasuredSize}
+		w.Layout(theme.Default)
+		dst := image.NewRGBA(w.Rect)
+		if err := w.PaintBase(&node.PaintBaseContext{Theme: theme.Default, Dst: dst}, image.Point{}); err != nil {
+			t.Fatal(err)
+		}
+		return dst
+	}
+
+	// Tab stops are every eight spaces, so a tab paints the same as the
+	// spaces up to the next tab stop.
+	testCases := []struct {
+		tabs, spaces string
+	}{
+		{"\tx", strings.Repeat(" ", 8) + "x"},
+		{"ab\tx", "ab" + strings.Repeat(" ", 6) + "x"},
+		{"abcdefgh\tx", "abcdefgh" + strings.Repeat(" ", 8) + "x"},
+		{"a\tb\tc\nd\te", "a       b       c\nd       e"},
+	}
+	for _, tc := range testCases {
+		got, want := paint(tc.tabs), paint(tc.spaces)
+		if got.Rect != want.Rect {
+			t.Errorf("%q: bounds: got %v, want %v", tc.tabs, got.Rect, want.Rect)
+			continue
+		}
+		if string(got.Pix) != string(want.Pix) {
+			t.Errorf("%q: pixels differ from %q", tc.tabs, tc.spaces)
+		}
+	}
+}
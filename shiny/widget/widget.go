@@ -16,6 +16,7 @@ import (
 	"golang.org/x/exp/shiny/widget/node"
 	"golang.org/x/exp/shiny/widget/theme"
 	"golang.org/x/image/math/f64"
+	"golang.org/x/mobile/event/key"
 	"golang.org/x/mobile/event/lifecycle"
 	"golang.org/x/mobile/event/mouse"
 	"golang.org/x/mobile/event/paint"
@@ -36,6 +37,15 @@ const (
 func (a Axis) Horizontal() bool { return a&AxisHorizontal != 0 }
 func (a Axis) Vertical() bool   { return a&AxisVertical != 0 }
 
+// dipsAtLeast1 returns n density independent pixels converted to physical
+// pixels, but never fewer than one physical pixel.
+func dipsAtLeast1(t *theme.Theme, n float64) int {
+	if px := t.Pixels(unit.DIPs(n)).Round(); px > 1 {
+		return px
+	}
+	return 1
+}
+
 // WithLayoutData returns the given node after setting its embedded LayoutData
 // field.
 func WithLayoutData(n node.Node, layoutData interface{}) node.Node {
@@ -93,6 +103,7 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 	paintPending := false
 
 	gef := gesture.EventFilter{EventDeque: w}
+	fm := node.FocusManager{Root: root}
 	for {
 		e := w.NextEvent()
 
@@ -110,6 +121,9 @@ func RunWindow(s screen.Screen, root node.Node, opts *RunWindowOptions) error {
 		case gesture.Event, mouse.Event:
 			root.OnInputEvent(e, image.Point{})
 
+		case key.Event:
+			fm.OnKeyEvent(e)
+
 		case paint.Event:
 			ctx := &node.PaintContext{
 				Theme:  t,
@@ -6,6 +6,7 @@ package slog
 
 import (
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -57,6 +58,14 @@ func Duration(key string, v time.Duration) Attr {
 	return Attr{key, DurationValue(v)}
 }
 
+// Binary returns an Attr for a byte slice. TextHandler outputs its value in
+// hexadecimal, and JSONHandler outputs it as a standard base64 string, as
+// json.Marshal does for a []byte. HandlerOptions.MaxBinaryLen truncates long
+// values. The caller must not subsequently mutate the argument slice.
+func Binary(key string, b []byte) Attr {
+	return Attr{key, BinaryValue(b)}
+}
+
 // Group returns an Attr for a Group Value.
 // The first argument is the key; the remaining arguments
 // are converted to Attrs as in [Logger.Log].
@@ -68,6 +77,33 @@ func Group(key string, args ...any) Attr {
 	return Attr{key, GroupValue(argsToAttrSlice(args)...)}
 }
 
+// GroupMap returns an Attr for a Group Value made from the entries of m,
+// in order of their keys. Values of type map[string]any become nested
+// groups; other values are converted as in [Any].
+//
+// Use GroupMap for attributes that are only known at run time, such as
+// those decoded from JSON.
+func GroupMap(key string, m map[string]any) Attr {
+	return Attr{key, mapGroupValue(m)}
+}
+
+func mapGroupValue(m map[string]any) Value {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]Attr, len(keys))
+	for i, k := range keys {
+		if sub, ok := m[k].(map[string]any); ok {
+			attrs[i] = Attr{k, mapGroupValue(sub)}
+		} else {
+			attrs[i] = Any(k, m[k])
+		}
+	}
+	return GroupValue(attrs...)
+}
+
 func argsToAttrSlice(args []any) []Attr {
 	var (
 		attr  Attr
@@ -0,0 +1,115 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import "context"
+
+// NewDefaultsHandler returns a Handler that adds each of defaults to the
+// records passed to h, unless the record already has an attribute with the
+// same key.
+//
+// Unlike the attributes added with [Logger.With], a default can be
+// overridden, both by the attributes of a log call and by attributes added
+// later with WithAttrs. Keys are compared at the top level of the record:
+// an attribute inside a group, including a group started with WithGroup,
+// does not override a default with the same key, but the contents of a group
+// with an empty key do. A default that is itself a group is overridden by any
+// attribute with the group's key.
+//
+// Defaults that have not been overridden are added after the record's
+// attributes. Once WithGroup is called, attributes can no longer be added
+// at the top level, so the remaining defaults are added then instead, as if
+// by WithAttrs.
+func NewDefaultsHandler(h Handler, defaults ...Attr) Handler {
+	return &defaultsHandler{h: h, defaults: defaults}
+}
+
+type defaultsHandler struct {
+	h        Handler
+	defaults []Attr // defaults that have not been overridden by WithAttrs
+}
+
+func (h *defaultsHandler) Enabled(ctx context.Context, l Level) bool {
+	return h.h.Enabled(ctx, l)
+}
+
+func (h *defaultsHandler) Handle(ctx context.Context, r Record) error {
+	if len(h.defaults) == 0 {
+		return h.h.Handle(ctx, r)
+	}
+	keys := make(map[string]bool, r.NumAttrs())
+	r.Attrs(func(a Attr) bool {
+		addTopLevelKeys(keys, a)
+		return true
+	})
+	missing := withoutKeys(h.defaults, keys)
+	if len(missing) == 0 {
+		return h.h.Handle(ctx, r)
+	}
+	r = r.Clone()
+	r.AddAttrs(missing...)
+	return h.h.Handle(ctx, r)
+}
+
+func (h *defaultsHandler) WithAttrs(as []Attr) Handler {
+	h2 := *h
+	h2.h = h.h.WithAttrs(as)
+	if len(h.defaults) > 0 {
+		keys := make(map[string]bool, len(as))
+		for _, a := range as {
+			addTopLevelKeys(keys, a)
+		}
+		h2.defaults = withoutKeys(h.defaults, keys)
+	}
+	return &h2
+}
+
+func (h *defaultsHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	if len(h.defaults) > 0 {
+		h2.h = h2.h.WithAttrs(h.defaults)
+		h2.defaults = nil
+	}
+	h2.h = h2.h.WithGroup(name)
+	return &h2
+}
+
+// addTopLevelKeys adds the key of a to keys, or if a is a group with an
+// empty key, whose attributes are inlined, the keys of its attributes.
+func addTopLevelKeys(keys map[string]bool, a Attr) {
+	if a.Key != "" {
+		keys[a.Key] = true
+		return
+	}
+	if v := a.Value.Resolve(); v.Kind() == KindGroup {
+		for _, ga := range v.Group() {
+			addTopLevelKeys(keys, ga)
+		}
+	}
+}
+
+// withoutKeys returns the attributes of as whose keys are not in keys. It
+// returns as itself if none of them are.
+func withoutKeys(as []Attr, keys map[string]bool) []Attr {
+	var res []Attr
+	for i, a := range as {
+		if keys[a.Key] {
+			if res == nil {
+				res = append(make([]Attr, 0, len(as)-1), as[:i]...)
+			}
+			continue
+		}
+		if res != nil {
+			res = append(res, a)
+		}
+	}
+	if res == nil {
+		return as
+	}
+	return res
+}
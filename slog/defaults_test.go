@@ -0,0 +1,77 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDefaultsHandler(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		with func(*Logger) *Logger
+		log  func(*Logger)
+		want string
+	}{
+		{
+			name: "absent",
+			log:  func(l *Logger) { l.Info("m", "a", 1) },
+			want: `msg=m a=1 component=core region=eu`,
+		},
+		{
+			name: "per-call attr wins",
+			log:  func(l *Logger) { l.Info("m", "component", "db", "a", 1) },
+			want: `msg=m component=db a=1 region=eu`,
+		},
+		{
+			name: "with",
+			with: func(l *Logger) *Logger { return l.With("region", "us") },
+			log:  func(l *Logger) { l.Info("m", "component", "db") },
+			want: `msg=m region=us component=db`,
+		},
+		{
+			name: "in group",
+			log:  func(l *Logger) { l.Info("m", Group("g", String("component", "db"))) },
+			want: `msg=m g.component=db component=core region=eu`,
+		},
+		{
+			name: "inline group",
+			log:  func(l *Logger) { l.Info("m", Group("", String("component", "db"))) },
+			want: `msg=m component=db region=eu`,
+		},
+		{
+			name: "with group",
+			with: func(l *Logger) *Logger { return l.WithGroup("g") },
+			log:  func(l *Logger) { l.Info("m", "component", "db") },
+			want: `msg=m component=core region=eu g.component=db`,
+		},
+		{
+			name: "with then with group",
+			with: func(l *Logger) *Logger { return l.With("component", "db").WithGroup("g") },
+			log:  func(l *Logger) { l.Info("m", "a", 1) },
+			want: `msg=m component=db region=eu g.a=1`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			th := NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: func(groups []string, a Attr) Attr {
+				if len(groups) == 0 && (a.Key == TimeKey || a.Key == LevelKey) {
+					return Attr{}
+				}
+				return a
+			}})
+			l := New(NewDefaultsHandler(th, String("component", "core"), String("region", "eu")))
+			if test.with != nil {
+				l = test.with(l)
+			}
+			test.log(l)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != test.want {
+				t.Errorf("\ngot  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package slog
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"reflect"
@@ -128,6 +129,52 @@ func (h *defaultHandler) WithGroup(name string) Handler {
 	return &defaultHandler{h.ch.withGroup(name), h.output}
 }
 
+// withSource returns a Handler like h that outputs the source position of
+// each Record if add is true, and omits it otherwise.
+func withSource(h Handler, add bool) Handler {
+	switch h := h.(type) {
+	case *TextHandler:
+		return &TextHandler{h.commonHandler.withSource(add)}
+	case *JSONHandler:
+		return &JSONHandler{h.commonHandler.withSource(add)}
+	case *sourceHandler:
+		return withSource(h.h, add)
+	}
+	return &sourceHandler{h, add}
+}
+
+// sourceHandler wraps a Handler whose options are unknown, adding the
+// source position to each Record or removing it.
+type sourceHandler struct {
+	h   Handler
+	add bool
+}
+
+func (h *sourceHandler) Enabled(ctx context.Context, l Level) bool {
+	return h.h.Enabled(ctx, l)
+}
+
+func (h *sourceHandler) Handle(ctx context.Context, r Record) error {
+	if !h.add {
+		r.PC = 0
+	} else if r.PC != 0 {
+		r = r.Clone()
+		r.AddAttrs(Any(SourceKey, r.source()))
+	}
+	return h.h.Handle(ctx, r)
+}
+
+func (h *sourceHandler) WithAttrs(as []Attr) Handler {
+	return &sourceHandler{h.h.WithAttrs(as), h.add}
+}
+
+func (h *sourceHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return h
+	}
+	return &sourceHandler{h.h.WithGroup(name), h.add}
+}
+
 // HandlerOptions are options for a TextHandler or JSONHandler.
 // A zero HandlerOptions consists entirely of default values.
 type HandlerOptions struct {
@@ -168,6 +215,46 @@ type HandlerOptions struct {
 	// integer seconds since the Unix epoch), sanitize personal information, or
 	// remove attributes from the output.
 	ReplaceAttr func(groups []string, a Attr) Attr
+
+	// DedupKeys causes the handler to output each fully-qualified key at
+	// most once per record. When a key appears more than once, whether from
+	// calls to WithAttrs or in the Record, the last value wins and is
+	// output in the position of the first. Groups with the same key are
+	// merged. Keys are compared before ReplaceAttr is called.
+	//
+	// With DedupKeys set, the handler no longer pre-formats the attributes
+	// passed to WithAttrs, so each call to Handle does more work.
+	DedupKeys bool
+
+	// FlattenGroups causes a JSONHandler to output the attributes of a
+	// group as top-level keys qualified by the group name and a dot,
+	// instead of as a nested object. For example, the attribute "method" in
+	// the group "request" is output with the key "request.method", as
+	// TextHandler does. The source attribute is output as a single string,
+	// also as TextHandler does.
+	//
+	// FlattenGroups has no effect on a TextHandler.
+	FlattenGroups bool
+
+	// Indent, if non-empty, causes a JSONHandler to output each record as a
+	// multi-line JSON object, with each nesting level indented by another
+	// copy of Indent, such as "  " or "\t". It makes the output easier to
+	// read during development, but the output is no longer line-delimited
+	// JSON.
+	//
+	// Indent has no effect on a TextHandler.
+	Indent string
+
+	// MaxBinaryLen, if positive, is the maximum number of bytes of a
+	// Binary value that are output. A longer value is truncated to its
+	// first MaxBinaryLen bytes, followed by "...(N bytes)", where N is the
+	// value's full length. For example, with a MaxBinaryLen of 2,
+	// Binary("b", []byte{1, 2, 3}) is output by a TextHandler as
+	//
+	//     b="0102...(3 bytes)"
+	//
+	// The truncated JSON output is a string but no longer valid base64.
+	MaxBinaryLen int
 }
 
 // Keys for "built-in" attributes.
@@ -190,9 +277,10 @@ type commonHandler struct {
 	json              bool // true => output JSON; false => output text
 	opts              HandlerOptions
 	preformattedAttrs []byte
-	groupPrefix       string   // for text: prefix of groups opened in preformatting
+	groupPrefix       string   // for text and flattened JSON: prefix of groups opened in preformatting
 	groups            []string // all groups started from WithGroup
 	nOpenGroups       int      // the number of groups opened in preformattedAttrs
+	groupAttrs        [][]Attr // for DedupKeys: attrs from WithAttrs, by number of groups open
 	mu                sync.Mutex
 	w                 io.Writer
 }
@@ -206,6 +294,7 @@ func (h *commonHandler) clone() *commonHandler {
 		groupPrefix:       h.groupPrefix,
 		groups:            slices.Clip(h.groups),
 		nOpenGroups:       h.nOpenGroups,
+		groupAttrs:        slices.Clip(h.groupAttrs),
 		w:                 h.w,
 	}
 }
@@ -222,6 +311,18 @@ func (h *commonHandler) enabled(l Level) bool {
 
 func (h *commonHandler) withAttrs(as []Attr) *commonHandler {
 	h2 := h.clone()
+	if h.opts.DedupKeys {
+		// Keep the attributes, to be deduplicated against later ones
+		// when a Record is handled.
+		n := len(h2.groups)
+		if len(h2.groupAttrs) <= n {
+			h2.groupAttrs = append(h2.groupAttrs, make([][]Attr, n+1-len(h2.groupAttrs))...)
+		} else {
+			h2.groupAttrs = slices.Clone(h2.groupAttrs)
+		}
+		h2.groupAttrs[n] = append(slices.Clip(h2.groupAttrs[n]), as...)
+		return h2
+	}
 	// Pre-format the attributes as an optimization.
 	prefix := buffer.New()
 	defer prefix.Free()
@@ -243,6 +344,12 @@ func (h *commonHandler) withAttrs(as []Attr) *commonHandler {
 	return h2
 }
 
+func (h *commonHandler) withSource(add bool) *commonHandler {
+	h2 := h.clone()
+	h2.opts.AddSource = add
+	return h2
+}
+
 func (h *commonHandler) withGroup(name string) *commonHandler {
 	if name == "" {
 		return h
@@ -298,9 +405,14 @@ func (h *commonHandler) handle(r Record) error {
 	state.appendNonBuiltIns(r)
 	state.buf.WriteByte('\n')
 
+	out := []byte(*state.buf)
+	if h.json && h.opts.Indent != "" {
+		out = indentJSON(out, h.opts.Indent)
+	}
+
 	h.mu.Lock()
 	defer h.mu.Unlock()
-	_, err := h.w.Write(*state.buf)
+	_, err := h.w.Write(out)
 	return err
 }
 
@@ -316,21 +428,94 @@ func (s *handleState) appendNonBuiltIns(r Record) {
 	s.prefix = buffer.New()
 	defer s.prefix.Free()
 	s.prefix.WriteString(s.h.groupPrefix)
-	s.openGroups()
-	r.Attrs(func(a Attr) bool {
-		s.appendAttr(a)
-		return true
-	})
+	if s.h.opts.DedupKeys {
+		s.appendDedupedAttrs(r)
+	} else {
+		s.openGroups()
+		r.Attrs(func(a Attr) bool {
+			s.appendAttr(a)
+			return true
+		})
+	}
 	if s.h.json {
 		// Close all open groups.
-		for range s.h.groups {
-			s.buf.WriteByte('}')
+		if s.h.nestGroups() {
+			for range s.h.groups {
+				s.buf.WriteByte('}')
+			}
 		}
 		// Close the top-level object.
 		s.buf.WriteByte('}')
 	}
 }
 
+// appendDedupedAttrs appends the attrs from WithAttrs and those of r,
+// opening the groups from WithGroup in between, with duplicate keys removed.
+func (s *handleState) appendDedupedAttrs(r Record) {
+	for i := 0; i <= len(s.h.groups); i++ {
+		var as []Attr
+		if i < len(s.h.groupAttrs) {
+			as = s.h.groupAttrs[i]
+		}
+		if i == len(s.h.groups) {
+			as = slices.Clip(as)
+			r.Attrs(func(a Attr) bool {
+				as = append(as, a)
+				return true
+			})
+		}
+		for _, a := range dedupAttrs(as) {
+			s.appendAttr(a)
+		}
+		if i < len(s.h.groups) {
+			s.openGroup(s.h.groups[i])
+		}
+	}
+}
+
+// dedupAttrs returns as with duplicate keys removed.
+// The last value for a key is kept, in the position of the first.
+// Groups with the same key are merged, and groups with an empty key are
+// inlined so that their attributes take part.
+func dedupAttrs(as []Attr) []Attr {
+	var res []Attr
+	index := map[string]int{}
+	var add func([]Attr)
+	add = func(as []Attr) {
+		for _, a := range as {
+			a.Value = a.Value.Resolve()
+			if a.isEmpty() {
+				continue
+			}
+			isGroup := a.Value.Kind() == KindGroup
+			if isGroup && a.Key == "" {
+				add(a.Value.Group())
+				continue
+			}
+			i, ok := index[a.Key]
+			if !ok {
+				index[a.Key] = len(res)
+				res = append(res, Attr{})
+				i = len(res) - 1
+			} else if isGroup && res[i].Value.Kind() == KindGroup {
+				a.Value = GroupValue(append(slices.Clip(res[i].Value.Group()), a.Value.Group()...)...)
+			}
+			if isGroup {
+				a.Value = GroupValue(dedupAttrs(a.Value.Group())...)
+			}
+			res[i] = a
+		}
+	}
+	add(as)
+	return res
+}
+
+// nestGroups reports whether groups are output as nested objects rather
+// than by qualifying keys with the group names.
+func (h *commonHandler) nestGroups() bool {
+	return h.json && !h.opts.FlattenGroups
+}
+
 // attrSep returns the separator between attributes.
 func (h *commonHandler) attrSep() string {
 	if h.json {
@@ -347,7 +532,7 @@ type handleState struct {
 	buf     *buffer.Buffer
 	freeBuf bool           // should buf be freed?
 	sep     string         // separator to write before next key
-	prefix  *buffer.Buffer // for text: key prefix
+	prefix  *buffer.Buffer // for text and flattened JSON: key prefix
 	groups  *[]string      // pool-allocated slice of active groups, for ReplaceAttr
 }
 
@@ -393,7 +578,7 @@ const keyComponentSep = '.'
 // openGroup starts a new group of attributes
 // with the given name.
 func (s *handleState) openGroup(name string) {
-	if s.h.json {
+	if s.h.nestGroups() {
 		s.appendKey(name)
 		s.buf.WriteByte('{')
 		s.sep = ""
@@ -409,7 +594,7 @@ func (s *handleState) openGroup(name string) {
 
 // closeGroup ends the group with the given name.
 func (s *handleState) closeGroup(name string) {
-	if s.h.json {
+	if s.h.nestGroups() {
 		s.buf.WriteByte('}')
 	} else {
 		(*s.prefix) = (*s.prefix)[:len(*s.prefix)-len(name)-1 /* for keyComponentSep */]
@@ -441,7 +626,7 @@ func (s *handleState) appendAttr(a Attr) {
 	// Special case: Source.
 	if v := a.Value; v.Kind() == KindAny {
 		if src, ok := v.Any().(*Source); ok {
-			if s.h.json {
+			if s.h.nestGroups() {
 				a.Value = src.group()
 			} else {
 				a.Value = StringValue(fmt.Sprintf("%s:%d", src.File, src.Line))
@@ -504,6 +689,32 @@ func (s *handleState) appendString(str string) {
 	}
 }
 
+// appendBinary appends b in hexadecimal for text or in base64 for JSON,
+// truncated to MaxBinaryLen bytes.
+func (s *handleState) appendBinary(b []byte) {
+	n := len(b)
+	if limit := s.h.opts.MaxBinaryLen; limit > 0 && n > limit {
+		b = b[:limit]
+	}
+	quote := s.h.json || len(b) < n || n == 0
+	if quote {
+		s.buf.WriteByte('"')
+	}
+	if s.h.json {
+		*s.buf = base64.StdEncoding.AppendEncode(*s.buf, b)
+	} else {
+		*s.buf = appendHex(*s.buf, b)
+	}
+	if len(b) < n {
+		s.buf.WriteString("...(")
+		*s.buf = strconv.AppendInt(*s.buf, int64(n), 10)
+		s.buf.WriteString(" bytes)")
+	}
+	if quote {
+		s.buf.WriteByte('"')
+	}
+}
+
 func (s *handleState) appendValue(v Value) {
 	defer func() {
 		if r := recover(); r != nil {
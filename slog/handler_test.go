@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"path/filepath"
 	"strconv"
@@ -139,6 +140,18 @@ func TestJSONAndTextHandlers(t *testing.T) {
 			wantText: `time=2000-01-02T03:04:05.000Z level=INFO msg=message a=one b=2 ""=v`,
 			wantJSON: `{"time":"2000-01-02T03:04:05Z","level":"INFO","msg":"message","a":"one","b":2,"":"v"}`,
 		},
+		{
+			name: "group map",
+			attrs: []Attr{GroupMap("m", map[string]any{
+				"e": 5,
+				"c": "three",
+				"a": 1,
+				"d": map[string]any{"y": true, "x": 1.5, "z": map[string]any{}},
+				"b": nil,
+			})},
+			wantText: "time=2000-01-02T03:04:05.000Z level=INFO msg=message m.a=1 m.b=<nil> m.c=three m.d.x=1.5 m.d.y=true m.e=5",
+			wantJSON: `{"time":"2000-01-02T03:04:05Z","level":"INFO","msg":"message","m":{"a":1,"b":null,"c":"three","d":{"x":1.5,"y":true},"e":5}}`,
+		},
 		{
 			name:     "cap keys",
 			replace:  upperCaseKey,
@@ -436,6 +449,110 @@ func TestSecondWith(t *testing.T) {
 	}
 }
 
+func TestDedupKeys(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		with func(*Logger) *Logger
+		args []any
+		want string
+	}{
+		{
+			name: "with",
+			with: func(l *Logger) *Logger { return l.With("id", 1).With("id", 2) },
+			want: `{"level":"INFO","msg":"m","id":2}`,
+		},
+		{
+			name: "record",
+			with: func(l *Logger) *Logger { return l.With("id", 1, "a", "x") },
+			args: []any{"b", true, "id", 3},
+			want: `{"level":"INFO","msg":"m","id":3,"a":"x","b":true}`,
+		},
+		{
+			name: "groups",
+			with: func(l *Logger) *Logger {
+				return l.With("id", 1).WithGroup("g").With("id", 2).With("id", 3)
+			},
+			args: []any{"x", 4},
+			want: `{"level":"INFO","msg":"m","id":1,"g":{"id":3,"x":4}}`,
+		},
+		{
+			name: "merge",
+			with: func(l *Logger) *Logger {
+				return l.With(Group("g", "a", 1, "b", 2))
+			},
+			args: []any{Group("g", "b", 3, "c", 4), Group("", "d", 5)},
+			want: `{"level":"INFO","msg":"m","g":{"a":1,"b":3,"c":4},"d":5}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := NewJSONHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey), DedupKeys: true})
+			test.with(New(h)).Info("m", test.args...)
+			got := strings.TrimSpace(buf.String())
+			if got != test.want {
+				t.Errorf("\ngot  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFlattenGroups(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		with func(*Logger) *Logger
+		args []any
+		want string
+	}{
+		{
+			name: "record",
+			args: []any{"a", 1, Group("request", "method", "GET", Group("h", "x", 2)), "b", 3},
+			want: `{"level":"INFO","msg":"m","a":1,"request.method":"GET","request.h.x":2,"b":3}`,
+		},
+		{
+			name: "with",
+			with: func(l *Logger) *Logger {
+				return l.With("a", 1).WithGroup("request").With("method", "GET").WithGroup("h")
+			},
+			args: []any{"x", 2},
+			want: `{"level":"INFO","msg":"m","a":1,"request.method":"GET","request.h.x":2}`,
+		},
+		{
+			name: "empty groups",
+			with: func(l *Logger) *Logger { return l.WithGroup("g") },
+			args: []any{Group("h"), Group("", "c", 4)},
+			want: `{"level":"INFO","msg":"m","g.c":4}`,
+		},
+	} {
+		for _, dedup := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s,dedup=%t", test.name, dedup), func(t *testing.T) {
+				var buf bytes.Buffer
+				opts := &HandlerOptions{ReplaceAttr: removeKeys(TimeKey), FlattenGroups: true, DedupKeys: dedup}
+				l := New(NewJSONHandler(&buf, opts))
+				if test.with != nil {
+					l = test.with(l)
+				}
+				l.Info("m", test.args...)
+				got := strings.TrimSpace(buf.String())
+				if got != test.want {
+					t.Errorf("\ngot  %s\nwant %s", got, test.want)
+				}
+			})
+		}
+	}
+
+	// The source is a string, as in TextHandler.
+	var buf bytes.Buffer
+	opts := &HandlerOptions{AddSource: true, FlattenGroups: true}
+	New(NewJSONHandler(&buf, opts)).Info("m")
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if src, ok := m[SourceKey].(string); !ok || !strings.Contains(src, "handler_test.go:") {
+		t.Errorf("source: got %#v, want a file:line string", m[SourceKey])
+	}
+}
+
 func TestReplaceAttrGroups(t *testing.T) {
 	// Verify that ReplaceAttr is called with the correct groups.
 	type ga struct {
@@ -19,7 +19,8 @@ import (
 )
 
 // JSONHandler is a Handler that writes Records to an io.Writer as
-// line-delimited JSON objects.
+// line-delimited JSON objects, or as indented JSON objects if
+// [HandlerOptions.Indent] is set.
 type JSONHandler struct {
 	*commonHandler
 }
@@ -125,6 +126,8 @@ func appendJSONValue(s *handleState, v Value) error {
 		*s.buf = strconv.AppendInt(*s.buf, int64(v.Duration()), 10)
 	case KindTime:
 		s.appendTime(v.Time())
+	case KindBinary:
+		s.appendBinary(v.binary())
 	case KindAny:
 		a := v.Any()
 		_, jm := a.(json.Marshaler)
@@ -334,3 +337,15 @@ var safeSet = [utf8.RuneSelf]bool{
 	'~':      true,
 	'\u007f': true,
 }
+
+// indentJSON returns line, a JSON object followed by a newline, with the
+// object indented by indent. If line is not valid JSON, which can happen if
+// a value's MarshalJSON method returns invalid output, it is returned
+// unchanged.
+func indentJSON(line []byte, indent string) []byte {
+	var b bytes.Buffer
+	if err := json.Indent(&b, line, "", indent); err != nil {
+		return line
+	}
+	return b.Bytes()
+}
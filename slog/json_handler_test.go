@@ -14,6 +14,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -54,6 +55,57 @@ func TestJSONHandler(t *testing.T) {
 	}
 }
 
+func TestJSONHandlerIndent(t *testing.T) {
+	log := func(opts *HandlerOptions) string {
+		var buf bytes.Buffer
+		l := New(NewJSONHandler(&buf, opts)).With("a", 1).WithGroup("g")
+		l.Info("m", "b", "two", Group("h", "c", []int{3, 4}))
+		l.Warn("n")
+		return buf.String()
+	}
+	compact := log(&HandlerOptions{ReplaceAttr: removeKeys(TimeKey)})
+	indented := log(&HandlerOptions{ReplaceAttr: removeKeys(TimeKey), Indent: "  "})
+
+	if got, want := strings.Count(compact, "\n"), 2; got != want {
+		t.Fatalf("compact output has %d lines, want %d:\n%s", got, want, compact)
+	}
+	wantFirst := `{
+  "level": "INFO",
+  "msg": "m",
+  "a": 1,
+  "g": {
+    "b": "two",
+    "h": {
+      "c": [
+        3,
+        4
+      ]
+    }
+  }
+}
+`
+	if !strings.HasPrefix(indented, wantFirst) {
+		t.Errorf("got\n%s\nwant prefix\n%s", indented, wantFirst)
+	}
+
+	// The indented records parse back to the same values.
+	decode := func(s string) []map[string]any {
+		var ms []map[string]any
+		dec := json.NewDecoder(strings.NewReader(s))
+		for dec.More() {
+			var m map[string]any
+			if err := dec.Decode(&m); err != nil {
+				t.Fatal(err)
+			}
+			ms = append(ms, m)
+		}
+		return ms
+	}
+	if got, want := decode(indented), decode(compact); !reflect.DeepEqual(got, want) {
+		t.Errorf("indented output decodes to\n%v\nwant\n%v", got, want)
+	}
+}
+
 // for testing json.Marshaler
 type jsonMarshaler struct {
 	s string
@@ -103,6 +155,39 @@ func TestAppendJSONValue(t *testing.T) {
 	}
 }
 
+func TestJSONHandlerBinary(t *testing.T) {
+	b := []byte("\x00binary\xff data")
+	for _, test := range []struct {
+		max  int
+		want string
+	}{
+		{0, ""},
+		{len(b), ""},
+		{4, `"AGJpbg==...(13 bytes)"`},
+	} {
+		var buf bytes.Buffer
+		h := NewJSONHandler(&buf, &HandlerOptions{MaxBinaryLen: test.max})
+		r := NewRecord(time.Time{}, LevelInfo, "m", 0)
+		r.AddAttrs(Binary("b", b))
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+		want := test.want
+		if want == "" {
+			// Untruncated, a Binary value is output as json.Marshal outputs
+			// a []byte.
+			var err error
+			if want, err = marshalJSON(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+		want = `{"level":"INFO","msg":"m","b":` + want + `}`
+		if got := strings.TrimSuffix(buf.String(), "\n"); got != want {
+			t.Errorf("MaxBinaryLen=%d:\ngot  %s\nwant %s", test.max, got, want)
+		}
+	}
+}
+
 func marshalJSON(x any) (string, error) {
 	var buf bytes.Buffer
 	enc := json.NewEncoder(&buf)
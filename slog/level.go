@@ -114,6 +114,13 @@ func (l *Level) UnmarshalText(data []byte) error {
 	return l.parse(string(data))
 }
 
+// Set sets the level from a string in the form accepted by
+// [Level.UnmarshalText]. Together with [Level.String], it makes *Level
+// implement [flag.Value], so a Level can be passed to [flag.Var].
+func (l *Level) Set(s string) error {
+	return l.parse(s)
+}
+
 func (l *Level) parse(s string) (err error) {
 	defer func() {
 		if err != nil {
@@ -6,6 +6,7 @@ package slog
 
 import (
 	"flag"
+	"io"
 	"strings"
 	"testing"
 )
@@ -137,6 +138,39 @@ func TestLevelFlag(t *testing.T) {
 	}
 }
 
+func TestLevelFlagValue(t *testing.T) {
+	var _ flag.Value = (*Level)(nil)
+
+	for _, test := range []struct {
+		in   string
+		want Level
+	}{
+		{"error", LevelError},
+		{"INFO+1", LevelInfo + 1},
+	} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		l := LevelDebug
+		fs.Var(&l, "level", "set level")
+		if err := fs.Parse([]string{"-level", test.in}); err != nil {
+			t.Fatalf("%q: %v", test.in, err)
+		}
+		if l != test.want {
+			t.Errorf("%q: got %v, want %v", test.in, l, test.want)
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	l := LevelWarn
+	fs.Var(&l, "level", "set level")
+	if err := fs.Parse([]string{"-level", "garbage"}); err == nil {
+		t.Error("got nil error for garbage level")
+	}
+	if l != LevelWarn {
+		t.Errorf("after error: got %v, want %v", l, LevelWarn)
+	}
+}
+
 func TestLevelVarMarshalText(t *testing.T) {
 	var v LevelVar
 	v.Set(LevelWarn)
@@ -6,6 +6,7 @@ package slog
 
 import (
 	"context"
+	"io"
 	"log"
 	"runtime"
 	"sync/atomic"
@@ -77,7 +78,8 @@ func (w *handlerWriter) Write(buf []byte) (int, error) {
 // To create a new Logger, call [New] or a Logger method
 // that begins "With".
 type Logger struct {
-	handler Handler // for structured logging
+	handler    Handler // for structured logging
+	callerSkip int     // extra stack frames to skip when recording the PC
 }
 
 func (l *Logger) clone() *Logger {
@@ -115,6 +117,46 @@ func (l *Logger) WithGroup(name string) *Logger {
 
 }
 
+// WithSource returns a new Logger whose output includes the source position
+// of each log call if add is true, and omits it if add is false, regardless
+// of how the receiver's handler was configured. It is useful for adding
+// source positions to the output of a single noisy subsystem.
+//
+// For a [TextHandler] or [JSONHandler], WithSource sets
+// [HandlerOptions.AddSource] on a copy of the handler. Other handlers are
+// wrapped in a Handler that adds the source position to each Record as an
+// attribute with the key [SourceKey] and a *[Source] value, or that clears
+// the Record's PC. The source attribute added by the wrapper is qualified by
+// any groups that were opened before the call to WithSource.
+//
+// The source position is the one recorded in [Record.PC] by the Logger output
+// method, which is that of its immediate caller. A function that wraps the
+// Logger's output methods should use [Logger.WithCallerSkip], or create its
+// own Records, so that the source position is that of its caller instead.
+func (l *Logger) WithSource(add bool) *Logger {
+	c := l.clone()
+	c.handler = withSource(l.handler, add)
+	return c
+}
+
+// WithCallerSkip returns a new Logger that skips an additional skip stack
+// frames when recording the program counter of a log call in [Record.PC].
+//
+// By default, the PC is that of the function that called the Logger's
+// output method, such as Info or Log. A logging helper that calls the output
+// method directly can use a Logger with a skip of 1 so that the PC is that
+// of the helper's caller instead. Skips accumulate: calling WithCallerSkip
+// on the result adds to the number of frames skipped. Negative values
+// reduce it, but never below the default.
+func (l *Logger) WithCallerSkip(skip int) *Logger {
+	c := l.clone()
+	c.callerSkip += skip
+	if c.callerSkip < 0 {
+		c.callerSkip = 0
+	}
+	return c
+}
+
 // New creates a new Logger with the given non-nil Handler and a nil context.
 func New(h Handler) *Logger {
 	if h == nil {
@@ -143,6 +185,22 @@ func NewLogLogger(h Handler, level Level) *log.Logger {
 	return log.New(&handlerWriter{h, level, true}, "", 0)
 }
 
+// NewLogWriter returns an io.Writer that logs each call to its Write method as
+// a Record at the given level, using l's handler. The written text, without
+// its final newline, becomes the Record's message.
+//
+// It is meant to be the output of an existing log.Logger, as in
+//
+//	log.New(NewLogWriter(l, LevelInfo), "", 0)
+//
+// so that code using the older log API can be migrated to structured logging.
+// The log.Logger's prefix and the text selected by its flags, such as the
+// date, are part of the message, so they are usually turned off.
+// If the log.Logger has no special needs, [NewLogLogger] is simpler.
+func NewLogWriter(l *Logger, level Level) io.Writer {
+	return &handlerWriter{l.Handler(), level, true}
+}
+
 // Log emits a log record with the current time and the given level and message.
 // The Record's Attrs consist of the Logger's attributes followed by
 // the Attrs specified by args.
@@ -236,12 +294,16 @@ func (l *Logger) log(ctx context.Context, level Level, msg string, args ...any)
 	var pc uintptr
 	if !internal.IgnorePC {
 		var pcs [1]uintptr
-		// skip [runtime.Callers, this function, this function's caller]
-		runtime.Callers(3, pcs[:])
+		// skip [runtime.Callers, this function, this function's caller],
+		// and any frames requested with WithCallerSkip.
+		runtime.Callers(3+l.callerSkip, pcs[:])
 		pc = pcs[0]
 	}
 	r := NewRecord(time.Now(), level, msg, pc)
 	r.Add(args...)
+	if l.callerSkip > 0 {
+		r.setStackSkip(l.callerSkip)
+	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -256,12 +318,16 @@ func (l *Logger) logAttrs(ctx context.Context, level Level, msg string, attrs ..
 	var pc uintptr
 	if !internal.IgnorePC {
 		var pcs [1]uintptr
-		// skip [runtime.Callers, this function, this function's caller]
-		runtime.Callers(3, pcs[:])
+		// skip [runtime.Callers, this function, this function's caller],
+		// and any frames requested with WithCallerSkip.
+		runtime.Callers(3+l.callerSkip, pcs[:])
 		pc = pcs[0]
 	}
 	r := NewRecord(time.Now(), level, msg, pc)
 	r.AddAttrs(attrs...)
+	if l.callerSkip > 0 {
+		r.setStackSkip(l.callerSkip)
+	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -372,6 +372,88 @@ func TestLoggerError(t *testing.T) {
 	checkLogOutput(t, buf.String(), `level=ERROR msg=msg err=EOF !BADKEY=a`)
 }
 
+func TestWithSource(t *testing.T) {
+	var buf bytes.Buffer
+	removeTime := func(_ []string, a Attr) Attr {
+		if a.Key == TimeKey {
+			return Attr{}
+		}
+		return a
+	}
+	l := New(NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeTime}))
+	ls := l.WithSource(true).With("a", 1)
+
+	ls.Info("m")
+	checkLogOutput(t, buf.String(), `level=INFO source=.*logger_test.go:\d+ msg=m a=1`)
+	buf.Reset()
+	l.Info("m")
+	checkLogOutput(t, buf.String(), `level=INFO msg=m`)
+	buf.Reset()
+	ls.WithSource(false).Info("m")
+	checkLogOutput(t, buf.String(), `level=INFO msg=m a=1`)
+	buf.Reset()
+
+	// Other handlers are wrapped.
+	h := &captureHandler{}
+	l = New(h)
+	l.WithSource(true).Info("m")
+	got := attrsSlice(h.r)
+	if len(got) != 1 || got[0].Key != SourceKey {
+		t.Fatalf("got %v, want a source attribute", got)
+	}
+	if src, ok := got[0].Value.Any().(*Source); !ok || filepath.Base(src.File) != "logger_test.go" {
+		t.Errorf("got %v, want the position in logger_test.go", got[0].Value)
+	}
+	l.WithSource(true).WithSource(false).Info("m")
+	if got := attrsSlice(h.r); len(got) != 0 {
+		t.Errorf("got %v, want no attributes", got)
+	}
+	if h.r.PC != 0 {
+		t.Error("got a PC, want zero")
+	}
+}
+
+func TestWithCallerSkip(t *testing.T) {
+	h := &captureHandler{}
+	l := New(h)
+
+	// info is a logging helper. The Logger it uses skips the helper's frame.
+	helper := l.WithCallerSkip(1)
+	info := func(msg string) {
+		helper.Info(msg)
+	}
+
+	checkFunc := func(want string) {
+		t.Helper()
+		if got := h.r.source().Function; got != want {
+			t.Errorf("got function %q, want %q", got, want)
+		}
+	}
+
+	const testFunc = "golang.org/x/exp/slog.TestWithCallerSkip"
+	f, _ := runtime.CallersFrames([]uintptr{callerPC(2)}).Next()
+	info("m")
+	if got, want := h.r.source().Line, f.Line+1; got != want {
+		t.Errorf("got line %d, want %d", got, want)
+	}
+	checkFunc(testFunc)
+
+	l.Info("m")
+	checkFunc(testFunc)
+
+	// Without the skip, the source is the helper.
+	func() { l.Info("m") }()
+	if got := h.r.source().Function; !strings.HasPrefix(got, testFunc+".func") {
+		t.Errorf("got function %q, want a function literal in %s", got, testFunc)
+	}
+
+	// Skips accumulate, and can be undone.
+	helper.WithCallerSkip(-1).Info("m")
+	checkFunc(testFunc)
+	l.WithCallerSkip(-5).Info("m")
+	checkFunc(testFunc)
+}
+
 func TestNewLogLogger(t *testing.T) {
 	var buf bytes.Buffer
 	h := NewTextHandler(&buf, nil)
@@ -380,6 +462,28 @@ func TestNewLogLogger(t *testing.T) {
 	checkLogOutput(t, buf.String(), "time="+textTimeRE+` level=WARN msg=hello`)
 }
 
+func TestNewLogWriter(t *testing.T) {
+	h := &captureHandler{}
+	w := NewLogWriter(New(h), LevelInfo)
+	n, err := io.WriteString(w, "hello\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len("hello\n") {
+		t.Errorf("got %d bytes written, want %d", n, len("hello\n"))
+	}
+	if h.r.Level != LevelInfo || h.r.Message != "hello" {
+		t.Errorf("got level %v, msg %q, want INFO, %q", h.r.Level, h.r.Message, "hello")
+	}
+
+	// As the output of a log.Logger, the source is the caller of log.Print.
+	var buf bytes.Buffer
+	l := New(NewTextHandler(&buf, &HandlerOptions{AddSource: true})).With("a", 1)
+	ll := log.New(NewLogWriter(l, LevelWarn), "", 0)
+	ll.Print("migrated")
+	checkLogOutput(t, buf.String(), "time="+textTimeRE+` level=WARN source=.*logger_test.go:\d+ msg=migrated a=1`)
+}
+
 func checkLogOutput(t *testing.T, got, wantRegexp string) {
 	t.Helper()
 	got = clean(got)
@@ -67,6 +67,8 @@ func NewRecord(t time.Time, level Level, msg string, pc uintptr) Record {
 // Clone returns a copy of the record with no shared state.
 // The original record and the clone can both be modified
 // without interfering with each other.
+// A Handler that retains a Record after Handle returns, for example to
+// process it asynchronously, should retain a clone.
 func (r Record) Clone() Record {
 	r.back = slices.Clip(r.back) // prevent append from mutating shared array
 	return r
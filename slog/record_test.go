@@ -108,6 +108,29 @@ func TestAliasingAndClone(t *testing.T) {
 	check(r2, append(slices.Clip(r1Attrs), Int("p", 2)))
 }
 
+func TestCloneIndependentOfOriginal(t *testing.T) {
+	for _, n := range []int{0, nAttrsInline - 1, nAttrsInline, nAttrsInline + 1, 2 * nAttrsInline} {
+		var as []Attr
+		for i := 0; i < n; i++ {
+			as = append(as, Int("k", i))
+		}
+		r := NewRecord(time.Time{}, 0, "", 0)
+		r.AddAttrs(as...)
+		// Give r.back spare capacity, as append would.
+		r.back = append(make([]Attr, 0, len(r.back)+4), r.back...)
+		c := r.Clone()
+		for i := 0; i < nAttrsInline+2; i++ {
+			r.AddAttrs(String("extra", "x"))
+		}
+		if got := attrsSlice(c); !attrsEqual(got, as) {
+			t.Errorf("%d attrs: clone changed after AddAttrs on original:\ngot  %v\nwant %v", n, got, as)
+		}
+		if got, want := r.NumAttrs(), n+nAttrsInline+2; got != want {
+			t.Errorf("%d attrs: original has %d attrs, want %d", n, got, want)
+		}
+	}
+}
+
 func newRecordWithAttrs(as []Attr) Record {
 	r := NewRecord(time.Now(), LevelInfo, "", 0)
 	r.AddAttrs(as...)
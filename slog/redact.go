@@ -0,0 +1,113 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"context"
+	"regexp"
+)
+
+// NewRedactingHandler returns a Handler that replaces the values of some
+// attributes with mask before passing them on to h.
+//
+// An attribute is redacted if its key, or its key qualified by the names of
+// the groups that contain it and separated by dots, is one of keys. For
+// example, the key "password" matches a "password" attribute wherever it
+// appears, while "db.password" only matches one in the "db" group.
+// Groups, including those started with WithGroup, are searched but not
+// redacted themselves; LogValuers are resolved first so that their values
+// can be searched too.
+//
+// Attributes are redacted both in the Records passed to Handle and when they
+// are added with WithAttrs. The order of attributes is preserved.
+func NewRedactingHandler(h Handler, keys []string, mask string) Handler {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return &redactingHandler{
+		h:     h,
+		match: func(key string) bool { return set[key] },
+		mask:  mask,
+	}
+}
+
+// NewRegexpRedactingHandler is like NewRedactingHandler, except that an
+// attribute is redacted if re matches its key, or its key qualified by the
+// names of the groups that contain it. For example, the expression
+// `(?i)passw(or)?d` redacts a "password" or "db.Passwd" attribute.
+//
+// Like regexp.Regexp.MatchString, re matches anywhere in the key unless it
+// is anchored, so an expression that must match a whole key should start
+// with ^ and end with $.
+func NewRegexpRedactingHandler(h Handler, re *regexp.Regexp, mask string) Handler {
+	return &redactingHandler{
+		h:     h,
+		match: re.MatchString,
+		mask:  mask,
+	}
+}
+
+type redactingHandler struct {
+	h      Handler
+	match  func(key string) bool // reports whether to redact an attribute
+	mask   string
+	prefix string // names of groups from WithGroup, each followed by a dot
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, l Level) bool {
+	return h.h.Enabled(ctx, l)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, r Record) error {
+	r2 := NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a Attr) bool {
+		r2.AddAttrs(h.redact(h.prefix, a))
+		return true
+	})
+	return h.h.Handle(ctx, r2)
+}
+
+func (h *redactingHandler) WithAttrs(as []Attr) Handler {
+	as2 := make([]Attr, len(as))
+	for i, a := range as {
+		as2[i] = h.redact(h.prefix, a)
+	}
+	h2 := *h
+	h2.h = h.h.WithAttrs(as2)
+	return &h2
+}
+
+func (h *redactingHandler) WithGroup(name string) Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.h = h.h.WithGroup(name)
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// redact returns a with the values of matching attributes replaced by the
+// mask. prefix holds the names of the enclosing groups.
+func (h *redactingHandler) redact(prefix string, a Attr) Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != KindGroup {
+		if h.match(a.Key) || h.match(prefix+a.Key) {
+			a.Value = StringValue(h.mask)
+		}
+		return a
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	group := a.Value.Group()
+	as := make([]Attr, len(group))
+	for i, ga := range group {
+		as[i] = h.redact(prefix, ga)
+	}
+	a.Value = GroupValue(as...)
+	return a
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedactingHandler(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		name string
+		with func(*Logger) *Logger
+		log  func(*Logger)
+		want string
+	}{
+		{
+			name: "inline",
+			log: func(l *Logger) {
+				l.Info("m", "user", "al", "password", "hunter2", "n", 1)
+			},
+			want: `msg=m user=al password=XXX n=1`,
+		},
+		{
+			name: "with",
+			with: func(l *Logger) *Logger { return l.With("password", "hunter2", "a", 1) },
+			log:  func(l *Logger) { l.Info("m", "b", 2) },
+			want: `msg=m password=XXX a=1 b=2`,
+		},
+		{
+			name: "in group",
+			log: func(l *Logger) {
+				l.Info("m", Group("db", String("host", "h"), String("password", "p"), String("token", "t")))
+			},
+			want: `msg=m db.host=h db.password=XXX db.token=XXX`,
+		},
+		{
+			name: "qualified key only matches in group",
+			log:  func(l *Logger) { l.Info("m", "token", "t") },
+			want: `msg=m token=t`,
+		},
+		{
+			name: "with group",
+			with: func(l *Logger) *Logger { return l.WithGroup("db").With("token", "t1") },
+			log:  func(l *Logger) { l.Info("m", "token", "t2", "user", "al") },
+			want: `msg=m db.token=XXX db.token=XXX db.user=al`,
+		},
+		{
+			name: "group value not redacted",
+			log:  func(l *Logger) { l.Info("m", Group("password", Int("len", 7))) },
+			want: `msg=m password.len=7`,
+		},
+		{
+			name: "log valuer",
+			log: func(l *Logger) {
+				l.Info("m", "creds", &replace{GroupValue(String("password", "p"), String("user", "u"))})
+			},
+			want: `msg=m creds.password=XXX creds.user=u`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			th := NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: func(groups []string, a Attr) Attr {
+				if len(groups) == 0 && (a.Key == TimeKey || a.Key == LevelKey) {
+					return Attr{}
+				}
+				return a
+			}})
+			l := New(NewRedactingHandler(th, []string{"password", "db.token"}, "XXX"))
+			if test.with != nil {
+				l = test.with(l)
+			}
+			test.log(l)
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != test.want {
+				t.Errorf("\ngot  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+
+	// The caller's group is not modified.
+	g := []Attr{String("password", "p")}
+	h := NewRedactingHandler(NewTextHandler(&bytes.Buffer{}, nil), []string{"password"}, "XXX")
+	r := NewRecord(testTime, LevelInfo, "m", 0)
+	r.AddAttrs(Attr{"g", GroupValue(g...)})
+	if err := h.Handle(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if got := g[0].Value.String(); got != "p" {
+		t.Errorf("group attr modified to %q", got)
+	}
+}
+
+func TestRegexpRedactingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	th := NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: func(groups []string, a Attr) Attr {
+		if len(groups) == 0 && (a.Key == TimeKey || a.Key == LevelKey) {
+			return Attr{}
+		}
+		return a
+	}})
+	re := regexp.MustCompile(`(?i)passw(or)?d|^db\.token$`)
+	l := New(NewRegexpRedactingHandler(th, re, "XXX")).With("Password", "p1")
+	// An unanchored expression also matches the qualified keys of the
+	// attributes within a matching group.
+	l.Info("m", "token", "t1", Group("db",
+		String("passwd", "p2"),
+		String("token", "t2"),
+		Group("password", Int("len", 7)),
+	))
+	want := `msg=m Password=XXX token=t1 db.passwd=XXX db.token=XXX db.password.len=XXX`
+	if got := strings.TrimSuffix(buf.String(), "\n"); got != want {
+		t.Errorf("\ngot  %s\nwant %s", got, want)
+	}
+}
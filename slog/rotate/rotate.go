@@ -0,0 +1,260 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rotate provides a slog.Handler that writes newline-delimited JSON
+// to a sequence of files in a directory, starting a new file when the
+// current one grows too large or too old.
+package rotate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// fileSuffix is the suffix of the names of the files written by a Handler.
+const fileSuffix = ".ndjson"
+
+// timeFormat is the format of the time in the names of the files written
+// by a Handler. Names in this format sort in time order.
+const timeFormat = "20060102T150405.000000000Z"
+
+// RotateOptions are options for a rotating Handler.
+type RotateOptions struct {
+	// HandlerOptions are the options for formatting records, as for
+	// slog.NewJSONHandler. Indent is ignored, so that each record is written
+	// on a single line.
+	HandlerOptions slog.HandlerOptions
+
+	// MaxSize is the maximum size of a file, in bytes. A record that would
+	// make the current file larger is written to a new file instead,
+	// unless the current file is empty. Zero means no limit.
+	MaxSize int64
+
+	// MaxAge is the maximum time to write to one file. The first record
+	// written after the current file is MaxAge old is written to a new
+	// file. Zero means no limit.
+	MaxAge time.Duration
+
+	// MaxFiles is the maximum number of files to keep in the directory,
+	// including the current one. When a new file is started, the oldest
+	// files are removed. Zero means that no files are removed.
+	MaxFiles int
+}
+
+// Handler is a slog.Handler that writes records as newline-delimited JSON,
+// in the format of slog.JSONHandler, to files in a directory.
+//
+// The files are named by their prefix, the time at which they were
+// started and the suffix ".ndjson", such as
+//
+//	app-20240102T150405.000000000Z.ndjson
+//
+// so that sorting their names sorts them by age. Each record is written to
+// a file in full, by a single write.
+//
+// A Handler is safe for concurrent use, and so are the Handlers derived
+// from it by WithAttrs and WithGroup, which share its files.
+type Handler struct {
+	out  *output
+	json slog.Handler
+}
+
+// output is shared by a Handler and the Handlers derived from it.
+type output struct {
+	dir, prefix string
+	opts        RotateOptions
+	now         func() time.Time
+	create      func(name string) (*os.File, error) // creates a new file
+
+	mu      sync.Mutex
+	f       *os.File  // the current file, or nil if closed
+	size    int64     // of the current file
+	started time.Time // when the current file was started
+}
+
+// NewRotatingJSONHandler returns a Handler that writes to files in dir
+// whose names start with prefix and a hyphen, using the given options.
+// It creates dir if it does not exist, and starts the first file.
+//
+// The caller should call Close when done with the Handler.
+func NewRotatingJSONHandler(dir, prefix string, opts RotateOptions) (*Handler, error) {
+	if err := os.MkdirAll(dir, 0o777); err != nil {
+		return nil, err
+	}
+	out := &output{
+		dir:    dir,
+		prefix: prefix,
+		opts:   opts,
+		now:    time.Now,
+		create: func(name string) (*os.File, error) {
+			return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+		},
+	}
+	// Indented records span several lines, which is not NDJSON.
+	out.opts.HandlerOptions.Indent = ""
+	if err := out.rotate(); err != nil {
+		return nil, err
+	}
+	return &Handler{out: out, json: slog.NewJSONHandler(out, &out.opts.HandlerOptions)}, nil
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.json.Enabled(ctx, level)
+}
+
+// Handle writes r to the current file, first starting a new file if
+// the current one is too large or too old.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	return h.json.Handle(ctx, r)
+}
+
+// WithAttrs returns a new Handler whose attributes consist of
+// both the receiver's attributes and the arguments.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{out: h.out, json: h.json.WithAttrs(attrs)}
+}
+
+// WithGroup returns a new Handler with the given group appended to
+// the receiver's existing groups.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{out: h.out, json: h.json.WithGroup(name)}
+}
+
+// Close flushes and closes the current file. After Close, Handle returns
+// an error. Close also closes the files of the Handlers derived from h.
+func (h *Handler) Close() error {
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	return h.out.close()
+}
+
+var errClosed = errors.New("rotate: Handler is closed")
+
+// Write writes a formatted record, starting a new file first if needed.
+// If the new file cannot be started, the record is not written and the
+// current file is kept, so the next Write tries again.
+func (o *output) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.f == nil {
+		return 0, errClosed
+	}
+	tooBig := o.opts.MaxSize > 0 && o.size > 0 && o.size+int64(len(p)) > o.opts.MaxSize
+	tooOld := o.opts.MaxAge > 0 && o.now().Sub(o.started) >= o.opts.MaxAge
+	if tooBig || tooOld {
+		if err := o.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := o.f.Write(p)
+	o.size += int64(n)
+	return n, err
+}
+
+// close flushes and closes the current file. It is called with o.mu held.
+func (o *output) close() error {
+	if o.f == nil {
+		return nil
+	}
+	f := o.f
+	o.f = nil
+	return closeFile(f)
+}
+
+// closeFile flushes and closes f.
+func closeFile(f *os.File) error {
+	err := f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// rotate starts a new file, closes the current one, if any, and removes the
+// oldest files beyond o.opts.MaxFiles. If the new file cannot be started,
+// the current one is kept. It is called with o.mu held, except by
+// NewRotatingJSONHandler.
+func (o *output) rotate() error {
+	t := o.now().UTC()
+	var f *os.File
+	for {
+		name := filepath.Join(o.dir, o.prefix+"-"+t.Format(timeFormat)+fileSuffix)
+		var err error
+		f, err = o.create(name)
+		if os.IsExist(err) {
+			// Another file was started at the same time. Keep the names in
+			// order by using a later time.
+			t = t.Add(time.Nanosecond)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		break
+	}
+	old := o.f
+	o.f, o.size, o.started = f, 0, o.now()
+	var err error
+	if old != nil {
+		err = closeFile(old)
+	}
+	if perr := o.prune(); err == nil {
+		err = perr
+	}
+	return err
+}
+
+// prune removes the oldest files beyond o.opts.MaxFiles.
+func (o *output) prune() error {
+	if o.opts.MaxFiles <= 0 {
+		return nil
+	}
+	names, err := o.files()
+	if err != nil {
+		return err
+	}
+	for len(names) > o.opts.MaxFiles {
+		if err := os.Remove(filepath.Join(o.dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+// files returns the names of the files in o.dir that were written by a
+// Handler with o's prefix, oldest first.
+func (o *output) files() ([]string, error) {
+	entries, err := os.ReadDir(o.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		t, ok := strings.CutPrefix(name, o.prefix+"-")
+		if !ok || !e.Type().IsRegular() {
+			continue
+		}
+		t, ok = strings.CutSuffix(t, fileSuffix)
+		if !ok {
+			continue
+		}
+		if _, err := time.Parse(timeFormat, t); err != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package rotate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// readFiles returns the files written by h, oldest first, checking that each
+// line of each file is a JSON object. For each file, it returns the values
+// of the "msg" keys of the file's records.
+func readFiles(t *testing.T, h *Handler) [][]string {
+	t.Helper()
+	names, err := h.out.files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs [][]string
+	for _, name := range names {
+		f, err := os.Open(filepath.Join(h.out.dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var m []string
+		s := bufio.NewScanner(f)
+		for s.Scan() {
+			var rec map[string]any
+			if err := json.Unmarshal(s.Bytes(), &rec); err != nil {
+				t.Errorf("%s: %q: %v", name, s.Bytes(), err)
+				continue
+			}
+			msg, _ := rec[slog.MessageKey].(string)
+			m = append(m, msg)
+		}
+		if err := s.Err(); err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		msgs = append(msgs, m)
+	}
+	return msgs
+}
+
+// removeTime removes the time, so that records are all the same size.
+func removeTime(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+	return a
+}
+
+func TestRotateBySize(t *testing.T) {
+	// Each record is {"level":"INFO","msg":"m0"} and a newline, which is
+	// 32 bytes, so a file holds two of them.
+	h, err := NewRotatingJSONHandler(t.TempDir(), "app", RotateOptions{
+		HandlerOptions: slog.HandlerOptions{ReplaceAttr: removeTime},
+		MaxSize:        64,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	l := slog.New(h)
+	for _, msg := range []string{"m0", "m1", "m2"} {
+		l.Info(msg)
+	}
+
+	got := readFiles(t, h)
+	if len(got) != 2 {
+		t.Fatalf("got %d files, want 2: %q", len(got), got)
+	}
+	if want := []string{"m0", "m1"}; !slices.Equal(got[0], want) {
+		t.Errorf("first file: got %q, want %q", got[0], want)
+	}
+	if want := []string{"m2"}; !slices.Equal(got[1], want) {
+		t.Errorf("second file: got %q, want %q", got[1], want)
+	}
+}
+
+func TestRotateByAge(t *testing.T) {
+	h, err := NewRotatingJSONHandler(t.TempDir(), "app", RotateOptions{
+		MaxAge: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	now := h.out.started
+	h.out.now = func() time.Time { return now }
+
+	l := slog.New(h)
+	l.Info("m0")
+	now = now.Add(59 * time.Minute)
+	l.Info("m1")
+	now = now.Add(time.Minute)
+	l.Info("m2")
+
+	got := readFiles(t, h)
+	if len(got) != 2 {
+		t.Fatalf("got %d files, want 2: %q", len(got), got)
+	}
+	if want := []string{"m0", "m1"}; !slices.Equal(got[0], want) {
+		t.Errorf("first file: got %q, want %q", got[0], want)
+	}
+	if want := []string{"m2"}; !slices.Equal(got[1], want) {
+		t.Errorf("second file: got %q, want %q", got[1], want)
+	}
+}
+
+func TestMaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	// A file that was not written by the Handler is kept.
+	other := filepath.Join(dir, "app-other"+fileSuffix)
+	if err := os.WriteFile(other, nil, 0o666); err != nil {
+		t.Fatal(err)
+	}
+
+	// With a MaxSize of 1, each record gets its own file.
+	h, err := NewRotatingJSONHandler(dir, "app", RotateOptions{
+		MaxSize:  1,
+		MaxFiles: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	l := slog.New(h)
+	for _, msg := range []string{"m0", "m1", "m2", "m3", "m4"} {
+		l.Info(msg)
+	}
+
+	got := readFiles(t, h)
+	want := [][]string{{"m2"}, {"m3"}, {"m4"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range got {
+		if !slices.Equal(got[i], want[i]) {
+			t.Errorf("got %q, want %q", got, want)
+			break
+		}
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRotateError(t *testing.T) {
+	h, err := NewRotatingJSONHandler(t.TempDir(), "app", RotateOptions{
+		HandlerOptions: slog.HandlerOptions{Indent: "  "},
+		MaxSize:        1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	create := h.out.create
+	errCreate := errors.New("create failed")
+	h.out.create = func(string) (*os.File, error) { return nil, errCreate }
+
+	// The first record goes to the first file, which needs no rotation.
+	l := slog.New(h)
+	l.Info("m0")
+	var r slog.Record
+	if err := h.Handle(context.Background(), r); err != errCreate {
+		t.Errorf("Handle: got %v, want %v", err, errCreate)
+	}
+	// Once files can be created again, rotation succeeds.
+	h.out.create = create
+	l.Info("m1")
+
+	got := readFiles(t, h)
+	want := [][]string{{"m0"}, {"m1"}}
+	if len(got) != len(want) || !slices.Equal(got[0], want[0]) || !slices.Equal(got[1], want[1]) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestConcurrentHandle(t *testing.T) {
+	h, err := NewRotatingJSONHandler(t.TempDir(), "app", RotateOptions{
+		MaxSize: 1000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	const n = 100
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		// Derived Handlers share the files.
+		l := slog.New(h).With("goroutine", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				l.Info("msg", "j", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for _, msgs := range readFiles(t, h) {
+		total += len(msgs)
+	}
+	if want := 4 * n; total != want {
+		t.Errorf("got %d records, want %d", total, want)
+	}
+}
+
+func TestClose(t *testing.T) {
+	h, err := NewRotatingJSONHandler(t.TempDir(), "app", RotateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := slog.New(h)
+	l.Info("m0")
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Close(); err != nil {
+		t.Errorf("second Close: %v", err)
+	}
+	var r slog.Record
+	if err := h.Handle(context.Background(), r); err != errClosed {
+		t.Errorf("Handle after Close: got %v, want %v", err, errClosed)
+	}
+	if got, want := readFiles(t, h), [][]string{{"m0"}}; len(got) != 1 || !slices.Equal(got[0], want[0]) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
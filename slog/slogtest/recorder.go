@@ -0,0 +1,204 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slogtest
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"golang.org/x/exp/slog"
+)
+
+// A Recorder is a [slog.Handler] that records the Records it handles, for
+// making assertions about log output in tests.
+//
+// Attributes added with WithAttrs and groups opened with WithGroup are
+// applied to each recorded Record, so that it contains all the attributes
+// that a Handler writing output would write, nested in the same way.
+// Values are not resolved; use [slog.Value.Resolve] if necessary.
+//
+// The Handlers returned by WithAttrs and WithGroup record into the same
+// list as the Recorder they were derived from.
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	level slog.Leveler
+	s     *recorderState
+	goas  []groupOrAttrs // from WithGroup and WithAttrs, in call order
+}
+
+type recorderState struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+// groupOrAttrs holds either a group name or a list of Attrs.
+type groupOrAttrs struct {
+	group string      // group name if non-empty
+	attrs []slog.Attr // attrs if non-empty
+}
+
+// NewRecorder creates a Recorder that records Records whose level is at least
+// level.Level(). If level is nil, it records Records at all levels.
+func NewRecorder(level slog.Leveler) *Recorder {
+	return &Recorder{level: level, s: &recorderState{}}
+}
+
+// Enabled reports whether l is greater than or equal to the
+// minimum level of the Recorder.
+func (h *Recorder) Enabled(_ context.Context, l slog.Level) bool {
+	return h.level == nil || l >= h.level.Level()
+}
+
+// Handle records a clone of r, with the attributes and groups of h applied.
+func (h *Recorder) Handle(_ context.Context, r slog.Record) error {
+	var as []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		as = append(as, a)
+		return true
+	})
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		goa := h.goas[i]
+		if goa.group != "" {
+			// A group with no attributes is omitted.
+			if len(as) > 0 {
+				as = []slog.Attr{{Key: goa.group, Value: slog.GroupValue(as...)}}
+			}
+		} else {
+			as = append(goa.attrs[:len(goa.attrs):len(goa.attrs)], as...)
+		}
+	}
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(as...)
+
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.records = append(h.s.records, nr)
+	return nil
+}
+
+// WithAttrs returns a Recorder that adds attrs to each Record it records.
+func (h *Recorder) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup returns a Recorder that nests the attributes of each Record it
+// records in a group with the given name.
+func (h *Recorder) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.withGroupOrAttrs(groupOrAttrs{group: name})
+}
+
+func (h *Recorder) withGroupOrAttrs(goa groupOrAttrs) *Recorder {
+	h2 := *h
+	h2.goas = make([]groupOrAttrs, len(h.goas)+1)
+	copy(h2.goas, h.goas)
+	h2.goas[len(h2.goas)-1] = goa
+	return &h2
+}
+
+// Records returns a copy of the list of recorded Records, in the order they
+// were handled.
+func (h *Recorder) Records() []slog.Record {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	rs := make([]slog.Record, len(h.s.records))
+	for i, r := range h.s.records {
+		rs[i] = r.Clone()
+	}
+	return rs
+}
+
+// Reset discards all recorded Records.
+func (h *Recorder) Reset() {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	h.s.records = nil
+}
+
+// Contains reports whether a Record with the given level and message, and
+// containing each of attrs, has been recorded.
+//
+// A non-group attribute in attrs matches an attribute of the Record with
+// the same key and an equal value, after resolving both values.
+// A group attribute in attrs matches a group of the Record with the same
+// key that contains each of its attributes; the group may have other
+// attributes as well.
+// As with the built-in Handlers, the attributes of a group with an empty key
+// are treated as if they appeared at the level of the group, both in the
+// Record and in attrs.
+// Values of kind [slog.KindAny] are compared with [reflect.DeepEqual].
+func (h *Recorder) Contains(level slog.Level, msg string, attrs ...slog.Attr) bool {
+	h.s.mu.Lock()
+	defer h.s.mu.Unlock()
+	for _, r := range h.s.records {
+		if r.Level != level || r.Message != msg {
+			continue
+		}
+		var as []slog.Attr
+		r.Attrs(func(a slog.Attr) bool {
+			as = append(as, a)
+			return true
+		})
+		if containsAll(flatten(as), flatten(attrs)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll reports whether each attribute of want matches an attribute of
+// got.
+func containsAll(got, want []slog.Attr) bool {
+	for _, w := range want {
+		found := false
+		for _, g := range got {
+			if matchAttr(g, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAttr(got, want slog.Attr) bool {
+	if got.Key != want.Key {
+		return false
+	}
+	gv, wv := got.Value.Resolve(), want.Value.Resolve()
+	switch wv.Kind() {
+	case slog.KindGroup:
+		return gv.Kind() == slog.KindGroup && containsAll(flatten(gv.Group()), flatten(wv.Group()))
+	case slog.KindAny:
+		// Value.Equal panics on non-comparable values like slices and maps.
+		return gv.Kind() == slog.KindAny && reflect.DeepEqual(gv.Any(), wv.Any())
+	}
+	return gv.Equal(wv)
+}
+
+// flatten returns as with the attributes of each group with an empty key
+// inlined, as the built-in Handlers do.
+func flatten(as []slog.Attr) []slog.Attr {
+	var res []slog.Attr
+	for _, a := range as {
+		if a.Key == "" {
+			if v := a.Value.Resolve(); v.Kind() == slog.KindGroup {
+				res = append(res, flatten(v.Group())...)
+				continue
+			}
+		}
+		res = append(res, a)
+	}
+	return res
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slogtest_test
+
+import (
+	"sync"
+	"testing"
+
+	"golang.org/x/exp/slog"
+	"golang.org/x/exp/slog/slogtest"
+)
+
+func TestRecorder(t *testing.T) {
+	r := slogtest.NewRecorder(slog.LevelInfo)
+	l := slog.New(r)
+	l.Debug("dropped")
+	l.Info("hello", "count", 3)
+	l2 := l.With("a", 1).WithGroup("req").With("method", "GET")
+	l2.Warn("slow", "ms", 250)
+	l.WithGroup("empty").Error("oops")
+
+	recs := r.Records()
+	if got, want := len(recs), 3; got != want {
+		t.Fatalf("got %d records, want %d", got, want)
+	}
+	if got, want := attrsString(recs[1]), "a=1 req=[method=GET ms=250]"; got != want {
+		t.Errorf("attrs: got %q, want %q", got, want)
+	}
+	if got, want := attrsString(recs[2]), ""; got != want {
+		t.Errorf("empty group: got %q, want %q", got, want)
+	}
+
+	for _, test := range []struct {
+		level slog.Level
+		msg   string
+		attrs []slog.Attr
+		want  bool
+	}{
+		{slog.LevelInfo, "hello", nil, true},
+		{slog.LevelInfo, "hello", []slog.Attr{slog.Int("count", 3)}, true},
+		{slog.LevelInfo, "hello", []slog.Attr{slog.Int("count", 4)}, false},
+		{slog.LevelWarn, "hello", nil, false},
+		{slog.LevelDebug, "dropped", nil, false},
+		{slog.LevelWarn, "slow", []slog.Attr{slog.Int("a", 1)}, true},
+		{slog.LevelWarn, "slow", []slog.Attr{slog.Group("req", "ms", 250)}, true},
+		{slog.LevelWarn, "slow", []slog.Attr{slog.Group("req", "method", "GET", "ms", 250)}, true},
+		{slog.LevelWarn, "slow", []slog.Attr{slog.Group("req", "method", "PUT")}, false},
+		{slog.LevelWarn, "slow", []slog.Attr{slog.Int("ms", 250)}, false},
+	} {
+		if got := r.Contains(test.level, test.msg, test.attrs...); got != test.want {
+			t.Errorf("Contains(%v, %q, %v) = %t, want %t", test.level, test.msg, test.attrs, got, test.want)
+		}
+	}
+
+	r.Reset()
+	l.Info("inline", slog.Group("", "a", 1, slog.Group("g", slog.Group("", "b", 2))))
+	l.Info("any", "s", []int{1, 2}, "m", map[string]int{"x": 1})
+	for _, test := range []struct {
+		msg   string
+		attrs []slog.Attr
+		want  bool
+	}{
+		{"inline", []slog.Attr{slog.Int("a", 1)}, true},
+		{"inline", []slog.Attr{slog.Group("g", "b", 2)}, true},
+		{"inline", []slog.Attr{slog.Group("", "a", 1)}, true},
+		{"inline", []slog.Attr{slog.Int("b", 2)}, false},
+		{"any", []slog.Attr{slog.Any("s", []int{1, 2})}, true},
+		{"any", []slog.Attr{slog.Any("s", []int{1, 3})}, false},
+		{"any", []slog.Attr{slog.Any("m", map[string]int{"x": 1})}, true},
+		{"any", []slog.Attr{slog.Any("m", []int{1})}, false},
+	} {
+		if got := r.Contains(slog.LevelInfo, test.msg, test.attrs...); got != test.want {
+			t.Errorf("Contains(%q, %v) = %t, want %t", test.msg, test.attrs, got, test.want)
+		}
+	}
+
+	r.Reset()
+	if got := len(r.Records()); got != 0 {
+		t.Errorf("after Reset: got %d records, want 0", got)
+	}
+}
+
+func TestRecorderConcurrent(t *testing.T) {
+	r := slogtest.NewRecorder(nil)
+	l := slog.New(r)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.With("i", i).Info("msg")
+		}(i)
+	}
+	wg.Wait()
+	if got, want := len(r.Records()), 10; got != want {
+		t.Errorf("got %d records, want %d", got, want)
+	}
+}
+
+// TestRecorderConformance checks that the Records recorded by a Recorder
+// contain what a Handler producing output should produce.
+func TestRecorderConformance(t *testing.T) {
+	r := slogtest.NewRecorder(nil)
+	results := func() []map[string]any {
+		var ms []map[string]any
+		for _, rec := range r.Records() {
+			m := map[string]any{
+				slog.LevelKey:   rec.Level,
+				slog.MessageKey: rec.Message,
+			}
+			if !rec.Time.IsZero() {
+				m[slog.TimeKey] = rec.Time
+			}
+			rec.Attrs(func(a slog.Attr) bool {
+				addAttr(m, a)
+				return true
+			})
+			ms = append(ms, m)
+		}
+		return ms
+	}
+	if err := slogtest.TestHandler(r, results); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// addAttr adds a to m in the way a Handler would output it.
+func addAttr(m map[string]any, a slog.Attr) {
+	v := a.Value.Resolve()
+	if a.Key == "" && v.Any() == nil {
+		return
+	}
+	if v.Kind() != slog.KindGroup {
+		m[a.Key] = v.Any()
+		return
+	}
+	as := v.Group()
+	if len(as) == 0 {
+		return
+	}
+	if a.Key != "" {
+		sub := map[string]any{}
+		m[a.Key] = sub
+		m = sub
+	}
+	for _, a := range as {
+		addAttr(m, a)
+	}
+}
+
+func attrsString(r slog.Record) string {
+	s := ""
+	r.Attrs(func(a slog.Attr) bool {
+		if s != "" {
+			s += " "
+		}
+		s += a.String()
+		return true
+	})
+	return s
+}
+
+var _ slog.Handler = (*slogtest.Recorder)(nil)
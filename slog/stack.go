@@ -0,0 +1,123 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"encoding/json"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackKey is the key used by StackTrace.
+const StackKey = "stack"
+
+// maxStackDepth is the maximum number of frames recorded by StackTrace.
+const maxStackDepth = 64
+
+// StackTrace returns an Attr whose value is the stack of the goroutine that
+// makes the log call, starting with the function that called the Logger
+// method. It is meant to be passed to a log call, typically one at
+// LevelError:
+//
+//	logger.Error("request failed", "err", err, slog.StackTrace())
+//
+// The stack is captured lazily, when a Handler resolves the value, so a log
+// call whose level is disabled pays nothing for it. Because of that, the
+// Attr should not be passed to Logger.With, where it would be resolved at
+// the time With is called.
+//
+// If the Logger skips frames because of [Logger.WithCallerSkip], the stack
+// skips them too, so that it starts at the same function as the source of the
+// record.
+//
+// The value is a list of frames, each of the form "function file:line".
+// JSONHandler renders it as an array of strings. TextHandler renders it as a
+// single quoted string on the line of the record, in which each frame is
+// preceded by an escaped newline and tab, "\n\t".
+func StackTrace() Attr {
+	return Any(StackKey, stackTracer{})
+}
+
+// stackTracer is a LogValuer that captures the stack when it is resolved.
+type stackTracer struct {
+	skip int // frames to skip after the Logger method, from WithCallerSkip
+}
+
+// setStackSkip makes the StackTrace attributes of r skip the given number of
+// frames after the Logger method. The Logger calls it before handling r,
+// which it owns.
+func (r *Record) setStackSkip(skip int) {
+	set := func(a *Attr) {
+		if _, ok := a.Value.any.(stackTracer); ok {
+			a.Value = AnyValue(stackTracer{skip: skip})
+		}
+	}
+	for i := 0; i < r.nFront; i++ {
+		set(&r.front[i])
+	}
+	for i := range r.back {
+		set(&r.back[i])
+	}
+}
+
+func (st stackTracer) LogValue() Value {
+	var pcs [maxStackDepth + 16]uintptr
+	// Skip runtime.Callers and LogValue.
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	// A Handler is called, directly or indirectly, from Logger.log or
+	// Logger.logAttrs, which are called from the Logger method or top-level
+	// function that the user called. Frames up to and including that one,
+	// and the frames skipped with WithCallerSkip, are dropped. If there are
+	// no such frames, for example because the Handler was called directly,
+	// the whole stack is kept.
+	var all []runtime.Frame
+	start := 0
+	for {
+		f, more := frames.Next()
+		all = append(all, f)
+		switch f.Function {
+		case "golang.org/x/exp/slog.(*Logger).log", "golang.org/x/exp/slog.(*Logger).logAttrs":
+			start = len(all) + 1 + st.skip
+		}
+		if !more {
+			break
+		}
+	}
+	if start > len(all) {
+		start = len(all)
+	}
+	all = all[start:]
+	if len(all) > maxStackDepth {
+		all = all[:maxStackDepth]
+	}
+
+	s := make(stackFrames, len(all))
+	for i, f := range all {
+		s[i] = f.Function + " " + f.File + ":" + strconv.Itoa(f.Line)
+	}
+	return AnyValue(s)
+}
+
+// stackFrames is a captured stack trace, one frame per element.
+type stackFrames []string
+
+// MarshalText renders the stack with each frame on its own line, indented
+// by a tab.
+func (s stackFrames) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	for _, f := range s {
+		b.WriteString("\n\t")
+		b.WriteString(f)
+	}
+	return []byte(b.String()), nil
+}
+
+// MarshalJSON renders the stack as an array of strings.
+func (s stackFrames) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]string(s))
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestStackTrace(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(NewTextHandler(&buf, nil))
+		l.Error("failed", StackTrace())
+		got := buf.String()
+		// The value is quoted, so the frames are separated by an escaped
+		// newline and tab, and the record is on a single line.
+		if strings.Count(got, "\n") != 1 {
+			t.Errorf("got %q, want a single line", got)
+		}
+		frames := strings.Split(got, `\n\t`)
+		if len(frames) < 2 || !strings.HasSuffix(frames[0], ` stack="`) {
+			t.Fatalf("got %q, want a stack of indented frames", got)
+		}
+		if !strings.HasPrefix(frames[1], "golang.org/x/exp/slog.TestStackTrace.func1 ") ||
+			!strings.Contains(frames[1], "stack_test.go:") {
+			t.Errorf("first frame: got %q, want the calling function", frames[1])
+		}
+		if strings.Contains(got, "(*Logger)") {
+			t.Errorf("stack contains Logger frames:\n%s", got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(NewJSONHandler(&buf, nil))
+		l.Error("failed", StackTrace())
+		var m struct {
+			Stack []string `json:"stack"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Stack) == 0 || !strings.HasPrefix(m.Stack[0], "golang.org/x/exp/slog.TestStackTrace.func2 ") {
+			t.Errorf("got %q, want a stack starting with the calling function", m.Stack)
+		}
+	})
+
+	t.Run("caller skip", func(t *testing.T) {
+		var buf bytes.Buffer
+		// errorf is a logging helper, whose frame is skipped.
+		l := New(NewJSONHandler(&buf, nil)).WithCallerSkip(1)
+		errorf := func(msg string) {
+			l.Error(msg, StackTrace())
+		}
+		errorf("failed")
+		var m struct {
+			Stack []string `json:"stack"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+			t.Fatal(err)
+		}
+		const want = "golang.org/x/exp/slog.TestStackTrace.func3 "
+		if len(m.Stack) == 0 || !strings.HasPrefix(m.Stack[0], want) {
+			t.Errorf("got %q, want a stack starting with %q", m.Stack, want)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := New(NewTextHandler(&buf, &HandlerOptions{Level: LevelError}))
+		ctx := context.Background()
+		allocs := testing.AllocsPerRun(10, func() {
+			l.LogAttrs(ctx, LevelInfo, "ignored", StackTrace())
+		})
+		if allocs != 0 {
+			t.Errorf("got %v allocs for a disabled log call, want 0", allocs)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("got output %q for a disabled log call", buf.String())
+		}
+	})
+}
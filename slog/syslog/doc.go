@@ -0,0 +1,9 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package syslog provides a slog.Handler that writes to the system log
+// service using the log/syslog package.
+//
+// Like log/syslog, this package is not implemented on Windows or Plan 9.
+package syslog
@@ -0,0 +1,119 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+
+package syslog
+
+import (
+	"bytes"
+	"context"
+	"log/syslog"
+	"sync"
+
+	"golang.org/x/exp/slog"
+)
+
+// Writer is the subset of the methods of *syslog.Writer used by a Handler.
+// The facility and tag of the messages are those of the Writer.
+type Writer interface {
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+}
+
+var _ Writer = (*syslog.Writer)(nil)
+
+// Handler is a slog.Handler that writes records to a syslog Writer.
+//
+// Each record is written as a single message in the same key=value format
+// as slog.TextHandler, without the time and level, which syslog records
+// itself. The level sets the priority of the message:
+//
+//	slog.LevelDebug and below   syslog.LOG_DEBUG
+//	slog.LevelInfo              syslog.LOG_INFO
+//	slog.LevelWarn              syslog.LOG_WARNING
+//	slog.LevelError and above   syslog.LOG_ERR
+//
+// Levels between these use the priority of the level below them.
+type Handler struct {
+	out  *output
+	text slog.Handler
+}
+
+// output is shared by a Handler and the Handlers derived from it.
+type output struct {
+	w Writer
+
+	mu    sync.Mutex
+	level slog.Level // of the record being written
+}
+
+// NewHandler returns a Handler that writes to w, using the given options.
+// A nil opts is the same as the zero HandlerOptions.
+// Time and level attributes are removed before opts.ReplaceAttr is called.
+func NewHandler(w Writer, opts *slog.HandlerOptions) *Handler {
+	var o slog.HandlerOptions
+	if opts != nil {
+		o = *opts
+	}
+	replace := o.ReplaceAttr
+	o.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) == 0 && (a.Key == slog.TimeKey || a.Key == slog.LevelKey) {
+			return slog.Attr{}
+		}
+		if replace != nil {
+			return replace(groups, a)
+		}
+		return a
+	}
+	out := &output{w: w}
+	return &Handler{out: out, text: slog.NewTextHandler(out, &o)}
+}
+
+// Enabled reports whether the handler handles records at the given level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.text.Enabled(ctx, level)
+}
+
+// Handle writes r to the syslog Writer with the priority for its level.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	h.out.level = r.Level
+	return h.text.Handle(ctx, r)
+}
+
+// WithAttrs returns a new Handler whose attributes consist of
+// both the receiver's attributes and the arguments.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{out: h.out, text: h.text.WithAttrs(attrs)}
+}
+
+// WithGroup returns a new Handler with the given group appended to
+// the receiver's existing groups.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{out: h.out, text: h.text.WithGroup(name)}
+}
+
+// Write writes a formatted record. It is called with o.mu held.
+func (o *output) Write(p []byte) (int, error) {
+	m := string(bytes.TrimSuffix(p, []byte("\n")))
+	var err error
+	switch {
+	case o.level < slog.LevelInfo:
+		err = o.w.Debug(m)
+	case o.level < slog.LevelWarn:
+		err = o.w.Info(m)
+	case o.level < slog.LevelError:
+		err = o.w.Warning(m)
+	default:
+		err = o.w.Err(m)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !windows && !plan9
+
+package syslog
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/slog"
+)
+
+// fakeWriter records the messages written to it, prefixed by their
+// priority.
+type fakeWriter struct {
+	msgs []string
+}
+
+func (w *fakeWriter) add(pri, m string) error {
+	w.msgs = append(w.msgs, pri+": "+m)
+	return nil
+}
+
+func (w *fakeWriter) Debug(m string) error   { return w.add("debug", m) }
+func (w *fakeWriter) Info(m string) error    { return w.add("info", m) }
+func (w *fakeWriter) Warning(m string) error { return w.add("warning", m) }
+func (w *fakeWriter) Err(m string) error     { return w.add("err", m) }
+
+func TestHandler(t *testing.T) {
+	var w fakeWriter
+	l := slog.New(NewHandler(&w, &slog.HandlerOptions{Level: slog.Level(-8)}))
+	l = l.With("a", 1).WithGroup("g")
+
+	for _, test := range []struct {
+		level slog.Level
+		want  string
+	}{
+		{-8, "debug"},
+		{slog.LevelDebug, "debug"},
+		{-1, "debug"},
+		{slog.LevelInfo, "info"},
+		{1, "info"},
+		{slog.LevelWarn, "warning"},
+		{slog.LevelError, "err"},
+		{12, "err"},
+	} {
+		w.msgs = nil
+		l.Log(context.Background(), test.level, "hello", "b", "two words")
+		want := fmt.Sprint([]string{test.want + `: msg=hello a=1 g.b="two words"`})
+		if got := fmt.Sprint(w.msgs); got != want {
+			t.Errorf("%v:\ngot  %s\nwant %s", test.level, got, want)
+		}
+	}
+}
+
+func TestHandlerOptions(t *testing.T) {
+	var w fakeWriter
+	h := NewHandler(&w, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.MessageKey {
+				a.Key = "message"
+			}
+			return a
+		},
+	})
+	l := slog.New(h)
+	l.Debug("not logged")
+	l.Info("logged")
+	want := []string{"info: message=logged"}
+	if got, want := fmt.Sprint(w.msgs), fmt.Sprint(want); got != want {
+		t.Errorf("\ngot  %s\nwant %s", got, want)
+	}
+}
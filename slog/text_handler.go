@@ -99,6 +99,8 @@ func appendTextValue(s *handleState, v Value) error {
 		s.appendString(v.str())
 	case KindTime:
 		s.appendTime(v.time())
+	case KindBinary:
+		s.appendBinary(v.binary())
 	case KindAny:
 		if tm, ok := v.any.(encoding.TextMarshaler); ok {
 			data, err := tm.MarshalText()
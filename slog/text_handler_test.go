@@ -121,6 +121,43 @@ func (t text) MarshalText() ([]byte, error) {
 	return []byte(fmt.Sprintf("text{%q}", t.s)), nil
 }
 
+func TestTextHandlerQuoting(t *testing.T) {
+	for _, test := range []struct {
+		attr Attr
+		want string
+	}{
+		{String("a", "simple"), `a=simple`},
+		{String("a", "C:\\dir"), `a=C:\dir`},
+		{String("a", ""), `a=""`},
+		{String("a", "two words"), `a="two words"`},
+		{String("a", "x=y"), `a="x=y"`},
+		{String("a", `say "hi"`), `a="say \"hi\""`},
+		{String("a", "line1\nline2"), `a="line1\nline2"`},
+		{String("a", "cr\r"), `a="cr\r"`},
+		{String("a", "tab\t"), `a="tab\t"`},
+		{String("a", "nbsp\u00a0"), `a="nbsp\u00a0"`},
+		{String("a", "bad\xff"), `a="bad\xff"`},
+		{String("a", "µåπ"), `a=µåπ`},
+		{String("a b", "c"), `"a b"=c`},
+		{String("a=b", "c"), `"a=b"=c`},
+		{String("a\nb", "c"), `"a\nb"=c`},
+		{String("", "c"), `""=c`},
+		{Group("g h", String("a", "b c")), `"g h.a"="b c"`},
+		{Any("e", errors.New("no such file")), `e="no such file"`},
+	} {
+		var buf bytes.Buffer
+		h := NewTextHandler(&buf, &HandlerOptions{ReplaceAttr: removeKeys(TimeKey, LevelKey, MessageKey)})
+		r := NewRecord(testTime, LevelInfo, "", 0)
+		r.AddAttrs(test.attr)
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSuffix(buf.String(), "\n"); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.attr, got, test.want)
+		}
+	}
+}
+
 func TestTextHandlerPreformatted(t *testing.T) {
 	var buf bytes.Buffer
 	var h Handler = NewTextHandler(&buf, nil)
@@ -138,6 +175,33 @@ func TestTextHandlerPreformatted(t *testing.T) {
 	}
 }
 
+func TestTextHandlerBinary(t *testing.T) {
+	for _, test := range []struct {
+		max  int
+		b    []byte
+		want string
+	}{
+		{0, []byte{0x00, 0x1f, 0xab, 0xff}, `b=001fabff`},
+		{0, nil, `b=""`},
+		{4, []byte{1, 2, 3, 4}, `b=01020304`},
+		{2, []byte{1, 2, 3, 4}, `b="0102...(4 bytes)"`},
+		{-1, []byte{1, 2, 3, 4}, `b=01020304`},
+	} {
+		var buf bytes.Buffer
+		h := NewTextHandler(&buf, &HandlerOptions{MaxBinaryLen: test.max})
+		r := NewRecord(time.Time{}, LevelInfo, "m", 0)
+		r.AddAttrs(Binary("b", test.b))
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatal(err)
+		}
+		got := strings.TrimSuffix(buf.String(), "\n")
+		want := "level=INFO msg=m " + test.want
+		if got != want {
+			t.Errorf("MaxBinaryLen=%d, % x:\ngot  %s\nwant %s", test.max, test.b, got, want)
+		}
+	}
+}
+
 func TestTextHandlerAlloc(t *testing.T) {
 	r := NewRecord(time.Now(), LevelInfo, "msg", 0)
 	for i := 0; i < 10; i++ {
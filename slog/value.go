@@ -5,6 +5,7 @@
 package slog
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 	"runtime"
@@ -30,9 +31,11 @@ type Value struct {
 	// is not preserved).
 	// If any is of type stringptr, then the Kind is String and the string value
 	// consists of the length in num and the pointer in any.
+	// If any is of type bytesptr, then the Kind is Binary and the []byte value
+	// consists of the length in num and the pointer in any.
 	// Otherwise, the Kind is Any and any is the value.
-	// (This implies that Attrs cannot store values of type Kind, *time.Location
-	// or stringptr.)
+	// (This implies that Attrs cannot store values of type Kind, *time.Location,
+	// stringptr or bytesptr.)
 	any any
 }
 
@@ -53,6 +56,7 @@ const (
 	KindUint64
 	KindGroup
 	KindLogValuer
+	KindBinary
 )
 
 var kindStrings = []string{
@@ -66,6 +70,7 @@ var kindStrings = []string{
 	"Uint64",
 	"Group",
 	"LogValuer",
+	"Binary",
 }
 
 func (k Kind) String() string {
@@ -90,6 +95,8 @@ func (v Value) Kind() Kind {
 		return KindTime
 	case groupptr:
 		return KindGroup
+	case bytesptr:
+		return KindBinary
 	case LogValuer:
 		return KindLogValuer
 	case kind: // a kind is just a wrapper for a Kind
@@ -242,6 +249,8 @@ func (v Value) Any() any {
 		return v.duration()
 	case KindTime:
 		return v.time()
+	case KindBinary:
+		return v.binary()
 	default:
 		panic(fmt.Sprintf("bad kind: %s", v.Kind()))
 	}
@@ -342,6 +351,15 @@ func (v Value) group() []Attr {
 	return unsafe.Slice((*Attr)(v.any.(groupptr)), v.num)
 }
 
+// Binary returns v's value as a []byte. It panics
+// if v is not a Binary value.
+func (v Value) Binary() []byte {
+	if g, w := v.Kind(), KindBinary; g != w {
+		panic(fmt.Sprintf("Value kind is %s, not %s", g, w))
+	}
+	return v.binary()
+}
+
 //////////////// Other
 
 // Equal reports whether v and w represent the same Go value.
@@ -364,6 +382,8 @@ func (v Value) Equal(w Value) bool {
 		return v.any == w.any // may panic if non-comparable
 	case KindGroup:
 		return slices.EqualFunc(v.group(), w.group(), Attr.Equal)
+	case KindBinary:
+		return bytes.Equal(v.binary(), w.binary())
 	default:
 		panic(fmt.Sprintf("bad kind: %s", k1))
 	}
@@ -389,6 +409,8 @@ func (v Value) append(dst []byte) []byte {
 		return append(dst, v.time().String()...)
 	case KindGroup:
 		return fmt.Append(dst, v.group())
+	case KindBinary:
+		return appendHex(dst, v.binary())
 	case KindAny, KindLogValuer:
 		return fmt.Append(dst, v.any)
 	default:
@@ -396,6 +418,14 @@ func (v Value) append(dst []byte) []byte {
 	}
 }
 
+// appendHex appends the lowercase hexadecimal encoding of b to dst.
+func appendHex(dst, b []byte) []byte {
+	for _, c := range b {
+		dst = append(dst, hex[c>>4], hex[c&0xF])
+	}
+	return dst
+}
+
 // A LogValuer is any Go value that can convert itself into a Value for logging.
 //
 // This mechanism may be used to defer expensive operations until they are
@@ -14,6 +14,7 @@ import (
 type (
 	stringptr unsafe.Pointer // used in Value.any when the Value is a string
 	groupptr  unsafe.Pointer // used in Value.any when the Value is a []Attr
+	bytesptr  unsafe.Pointer // used in Value.any when the Value is a Binary []byte
 )
 
 // StringValue returns a new Value for a string.
@@ -51,3 +52,21 @@ func GroupValue(as ...Attr) Value {
 	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&as))
 	return Value{num: uint64(hdr.Len), any: groupptr(hdr.Data)}
 }
+
+// BinaryValue returns a new Value for a byte slice. Unlike AnyValue, which
+// formats a []byte as a list of numbers, TextHandler outputs a Binary value
+// in hexadecimal and JSONHandler outputs it in standard base64.
+// The caller must not subsequently mutate the argument slice.
+func BinaryValue(b []byte) Value {
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	return Value{num: uint64(hdr.Len), any: bytesptr(hdr.Data)}
+}
+
+func (v Value) binary() []byte {
+	var b []byte
+	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	hdr.Data = uintptr(v.any.(bytesptr))
+	hdr.Len = int(v.num)
+	hdr.Cap = int(v.num)
+	return b
+}
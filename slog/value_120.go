@@ -11,6 +11,7 @@ import "unsafe"
 type (
 	stringptr *byte // used in Value.any when the Value is a string
 	groupptr  *Attr // used in Value.any when the Value is a []Attr
+	bytesptr  *byte // used in Value.any when the Value is a Binary []byte
 )
 
 // StringValue returns a new Value for a string.
@@ -24,6 +25,14 @@ func GroupValue(as ...Attr) Value {
 	return Value{num: uint64(len(as)), any: groupptr(unsafe.SliceData(as))}
 }
 
+// BinaryValue returns a new Value for a byte slice. Unlike AnyValue, which
+// formats a []byte as a list of numbers, TextHandler outputs a Binary value
+// in hexadecimal and JSONHandler outputs it in standard base64.
+// The caller must not subsequently mutate the argument slice.
+func BinaryValue(b []byte) Value {
+	return Value{num: uint64(len(b)), any: bytesptr(unsafe.SliceData(b))}
+}
+
 // String returns Value's value as a string, formatted like fmt.Sprint. Unlike
 // the methods Int64, Float64, and so on, which panic if v is of the
 // wrong kind, String never panics.
@@ -37,3 +46,7 @@ func (v Value) String() string {
 func (v Value) str() string {
 	return unsafe.String(v.any.(stringptr), v.num)
 }
+
+func (v Value) binary() []byte {
+	return unsafe.Slice((*byte)(v.any.(bytesptr)), v.num)
+}
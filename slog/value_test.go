@@ -5,6 +5,7 @@
 package slog
 
 import (
+	"bytes"
 	"fmt"
 	"reflect"
 	"strings"
@@ -27,6 +28,8 @@ func TestValueEqual(t *testing.T) {
 		AnyValue(&x),
 		AnyValue(&y),
 		GroupValue(Bool("b", true), Int("i", 3)),
+		BinaryValue([]byte{1, 2}),
+		BinaryValue([]byte{1, 2, 3}),
 	}
 	for i, v1 := range vals {
 		for j, v2 := range vals {
@@ -61,6 +64,7 @@ func TestValueString(t *testing.T) {
 		{TimeValue(testTime), "2000-01-02 03:04:05 +0000 UTC"},
 		{AnyValue(time.Duration(3 * time.Second)), "3s"},
 		{GroupValue(Int("a", 1), Bool("b", true)), "[a=1 b=true]"},
+		{BinaryValue([]byte{0x0a, 0xbc}), "0abc"},
 	} {
 		if got := test.v.String(); got != test.want {
 			t.Errorf("%#v:\ngot  %q\nwant %q", test.v, got, test.want)
@@ -134,6 +138,23 @@ func TestAnyValue(t *testing.T) {
 	}
 }
 
+func TestBinaryValue(t *testing.T) {
+	b := []byte{1, 2, 3}
+	v := BinaryValue(b)
+	if got, want := v.Kind(), KindBinary; got != want {
+		t.Errorf("Kind: got %s, want %s", got, want)
+	}
+	if got := v.Binary(); !bytes.Equal(got, b) {
+		t.Errorf("Binary: got %v, want %v", got, b)
+	}
+	if got, ok := v.Any().([]byte); !ok || !bytes.Equal(got, b) {
+		t.Errorf("Any: got %#v, want %v", v.Any(), b)
+	}
+	if !panics(func() { StringValue("s").Binary() }) {
+		t.Error("Binary on a String Value did not panic")
+	}
+}
+
 func TestValueAny(t *testing.T) {
 	for _, want := range []any{
 		nil,
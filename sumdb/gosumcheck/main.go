@@ -39,7 +39,6 @@ import (
 	"os"
 	"os/exec"
 	"strings"
-	"sync"
 	"time"
 
 	"golang.org/x/exp/sumdb/internal/sumweb"
@@ -100,41 +99,22 @@ func checkGoSum(conn *sumweb.Conn, name string, data []byte) {
 	lines = lines[:len(lines)-1]
 
 	errs := make([]string, len(lines))
-	var wg sync.WaitGroup
+	var pairs []sumweb.ModVer
+	var pairLine []int // index in lines of each pair
 	for i, line := range lines {
-		wg.Add(1)
-		go func(i int, line string) {
-			defer wg.Done()
-			f := strings.Fields(line)
-			if len(f) != 3 {
-				errs[i] = "invalid number of fields"
-				return
-			}
-
-			dbLines, err := conn.Lookup(f[0], f[1])
-			if err != nil {
-				if err == sumweb.ErrGONOSUMDB {
-					errs[i] = fmt.Sprintf("%s@%s: %v", f[0], f[1], err)
-				} else {
-					// Otherwise Lookup properly adds the prefix itself.
-					errs[i] = err.Error()
-				}
-				return
-			}
-			hashAlgPrefix := f[0] + " " + f[1] + " " + f[2][:strings.Index(f[2], ":")+1]
-			for _, dbLine := range dbLines {
-				if dbLine == line {
-					return
-				}
-				if strings.HasPrefix(dbLine, hashAlgPrefix) {
-					errs[i] = fmt.Sprintf("%s@%s hash mismatch: have %s, want %s", f[0], f[1], line, dbLine)
-					return
-				}
-			}
-			errs[i] = fmt.Sprintf("%s@%s hash algorithm mismatch: have %s, want one of:\n\t%s", f[0], f[1], line, strings.Join(dbLines, "\n\t"))
-		}(i, line)
-	}
-	wg.Wait()
+		f := strings.Fields(line)
+		if len(f) != 3 {
+			errs[i] = "invalid number of fields"
+			continue
+		}
+		pairs = append(pairs, sumweb.ModVer{Path: f[0], Version: f[1]})
+		pairLine = append(pairLine, i)
+	}
+
+	dbLines, dbErrs := conn.LookupBatch(pairs)
+	for j, i := range pairLine {
+		errs[i] = checkLine(lines[i], dbLines[j], dbErrs[j])
+	}
 
 	for i, err := range errs {
 		if err != "" {
@@ -143,6 +123,30 @@ func checkGoSum(conn *sumweb.Conn, name string, data []byte) {
 	}
 }
 
+// checkLine checks a go.sum line against the lines dbLines for the same
+// module version looked up in the database, returning a description of
+// any problem.
+func checkLine(line string, dbLines []string, err error) string {
+	f := strings.Fields(line)
+	if err != nil {
+		if err == sumweb.ErrGONOSUMDB {
+			return fmt.Sprintf("%s@%s: %v", f[0], f[1], err)
+		}
+		// Otherwise Lookup properly adds the prefix itself.
+		return err.Error()
+	}
+	hashAlgPrefix := f[0] + " " + f[1] + " " + f[2][:strings.Index(f[2], ":")+1]
+	for _, dbLine := range dbLines {
+		if dbLine == line {
+			return ""
+		}
+		if strings.HasPrefix(dbLine, hashAlgPrefix) {
+			return fmt.Sprintf("%s@%s hash mismatch: have %s, want %s", f[0], f[1], line, dbLine)
+		}
+	}
+	return fmt.Sprintf("%s@%s hash algorithm mismatch: have %s, want one of:\n\t%s", f[0], f[1], line, strings.Join(dbLines, "\n\t"))
+}
+
 type client struct{}
 
 func (*client) ReadConfig(file string) ([]byte, error) {
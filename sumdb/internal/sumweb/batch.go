@@ -0,0 +1,207 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sumweb
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/sumdb/internal/note"
+	"golang.org/x/exp/sumdb/internal/tlog"
+)
+
+// A ModVer is a module path and version, as passed to Lookup.
+type ModVer struct {
+	Path    string
+	Version string
+}
+
+// maxBatchFetches is the maximum number of record fetches
+// that LookupBatch has in flight at once.
+const maxBatchFetches = 10
+
+// LookupBatch is like calling Lookup for each of pairs,
+// returning the lines and error for pairs[i] in lines[i] and errs[i].
+//
+// LookupBatch reads each record only once, even if it is needed for
+// several pairs (such as a version and its /go.mod), with a bounded
+// number of reads in flight at once. Instead of authenticating each
+// record against the tree head that came with it, as a sequence of
+// Lookups would, LookupBatch first moves the Conn's latest tree head
+// forward to the newest head seen in the batch and then authenticates
+// all the records against that one tree. This reads far fewer tiles
+// than authenticating against each intermediate tree in turn.
+func (c *Conn) LookupBatch(pairs []ModVer) (lines [][]string, errs []error) {
+	atomic.StoreUint32(&c.didLookup, 1)
+
+	if err := c.init(); err == nil {
+		c.prefetchRecords(pairs)
+	}
+	// The records are now in c.record, so Lookup does no further fetches
+	// and takes care of GONOSUMDB, error annotation and line extraction.
+	lines = make([][]string, len(pairs))
+	errs = make([]error, len(pairs))
+	for i, p := range pairs {
+		lines[i], errs[i] = c.Lookup(p.Path, p.Version)
+	}
+	return lines, errs
+}
+
+// A batchRecord is a record being fetched by prefetchRecords.
+type batchRecord struct {
+	file, remotePath string
+
+	data       []byte
+	writeCache bool // data came from the server, not the on-disk cache
+	id         int64
+	text       []byte
+	tree       tlog.Tree
+	treeMsg    []byte
+	err        error
+}
+
+// prefetchRecords fetches and authenticates the records needed to look up
+// pairs and stores them in c.record.
+// Records that are already in c.record, or that cannot be named,
+// are left for Lookup to deal with.
+func (c *Conn) prefetchRecords(pairs []ModVer) {
+	var recs []*batchRecord
+	seen := make(map[string]bool)
+	for _, p := range pairs {
+		if c.skip(p.Path) {
+			continue
+		}
+		file, remotePath, err := c.lookupPaths(p.Path, p.Version)
+		if err != nil || seen[file] || c.record.Get(file) != nil {
+			continue
+		}
+		seen[file] = true
+		recs = append(recs, &batchRecord{file: file, remotePath: remotePath})
+	}
+	if len(recs) == 0 {
+		return
+	}
+
+	// Fetch and parse the records, without validating them yet.
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxBatchFetches)
+	for _, r := range recs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *batchRecord) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			c.fetchRecord(r)
+		}(r)
+	}
+	wg.Wait()
+
+	// Merge the tree heads, newest first, so that the Conn's timeline
+	// moves forward at most once and the older heads are checked against
+	// tiles of the newest tree, which are then already cached.
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].tree.N > recs[j].tree.N })
+	merged := make(map[string]error)
+	for _, r := range recs {
+		if r.err != nil {
+			continue
+		}
+		err, ok := merged[string(r.treeMsg)]
+		if !ok {
+			err = c.mergeLatest(r.treeMsg)
+			merged[string(r.treeMsg)] = err
+		}
+		r.err = err
+	}
+
+	// Authenticate all the remaining records against the latest tree
+	// with a single read of their hashes.
+	var check []*batchRecord
+	for _, r := range recs {
+		if r.err == nil {
+			check = append(check, r)
+		}
+	}
+	if len(check) > 0 {
+		c.checkRecords(check)
+	}
+
+	for _, r := range recs {
+		if r.err == nil && r.writeCache {
+			c.client.WriteCache(r.file, r.data)
+		}
+		r := r
+		c.record.Do(r.file, func() interface{} {
+			if r.err != nil {
+				return cachedRecord{nil, r.err}
+			}
+			return cachedRecord{r.data, nil}
+		})
+	}
+}
+
+// fetchRecord reads r from the on-disk cache or the server and parses it.
+func (c *Conn) fetchRecord(r *batchRecord) {
+	data, err := c.client.ReadCache(r.file)
+	if err != nil {
+		before, beforeMsg := c.latestHead()
+		data, err = c.client.ReadRemote(r.remotePath)
+		if err != nil {
+			r.err = err
+			return
+		}
+		if err := c.checkRollback(before, beforeMsg, data); err != nil {
+			r.err = err
+			return
+		}
+		r.writeCache = true
+	}
+	r.data = data
+	r.id, r.text, r.treeMsg, r.err = tlog.ParseRecord(data)
+	if r.err != nil || len(r.treeMsg) == 0 {
+		return
+	}
+	// Parse the tree head only to learn its size; mergeLatest checks it
+	// again in full.
+	n, err := note.Open(r.treeMsg, c.verifiers)
+	if err != nil {
+		return
+	}
+	r.tree, _ = tlog.ParseTree([]byte(n.Text))
+}
+
+// checkRecords is like checkRecord for each of recs,
+// setting r.err for the records that cannot be authenticated.
+func (c *Conn) checkRecords(recs []*batchRecord) {
+	c.latestMu.Lock()
+	latest := c.latest
+	c.latestMu.Unlock()
+
+	var indexes []int64
+	var ok []*batchRecord
+	for _, r := range recs {
+		if r.id >= latest.N {
+			r.err = fmt.Errorf("cannot validate record %d in tree of size %d", r.id, latest.N)
+			continue
+		}
+		indexes = append(indexes, tlog.StoredHashIndex(0, r.id))
+		ok = append(ok, r)
+	}
+	if len(ok) == 0 {
+		return
+	}
+	hashes, err := tlog.TileHashReader(latest, &c.tileReader).ReadHashes(indexes)
+	for i, r := range ok {
+		switch {
+		case err != nil:
+			r.err = err
+		case hashes[i] != tlog.RecordHash(r.text):
+			r.err = fmt.Errorf("cannot authenticate record data in server response")
+		}
+	}
+}
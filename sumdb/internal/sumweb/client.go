@@ -88,6 +88,7 @@ type Conn struct {
 	tileReader tileReader
 	tileHeight int
 	nosumdb    string
+	noRollback bool // reject older tree heads from the server
 
 	record    parCache // cache of record lookup, keyed by path@vers
 	tileCache parCache // cache of c.readTile, keyed by tile
@@ -179,6 +180,28 @@ func (c *Conn) SetGONOSUMDB(list string) {
 	c.nosumdb = list
 }
 
+// SetRejectRollback makes the Conn treat a server that answers a lookup
+// with a tree head older than the latest one the Conn already knew
+// as misbehaving, as if it had rolled back its log.
+//
+// Rejecting rollbacks is not the default because lookup responses
+// legitimately carry older tree heads: a lookup result is immutable,
+// so servers, proxies and CDNs cache it along with the tree head
+// that was current when it was first served, and a Conn that has
+// since seen a newer tree head from another response would reject it.
+// Without SetRejectRollback, the Conn still checks that every tree head
+// it sees is consistent with the latest one, which detects a server
+// that forks its log.
+// SetRejectRollback is appropriate for a client, such as a monitor,
+// that talks to the server directly, bypassing any caches.
+// Any call to SetRejectRollback must happen before the first call to Lookup.
+func (c *Conn) SetRejectRollback() {
+	if atomic.LoadUint32(&c.didLookup) != 0 {
+		panic("SetRejectRollback used after Lookup")
+	}
+	c.noRollback = true
+}
+
 // ErrGONOSUMDB is returned by Lookup for paths that match
 // a pattern listed in the GONOSUMDB list (set by SetGONOSUMDB,
 // usually from the environment variable).
@@ -253,34 +276,28 @@ func (c *Conn) Lookup(path, vers string) (lines []string, err error) {
 	}
 
 	// Prepare encoded cache filename / URL.
-	epath, err := encodePath(path)
-	if err != nil {
-		return nil, err
-	}
-	evers, err := encodeVersion(strings.TrimSuffix(vers, "/go.mod"))
+	file, remotePath, err := c.lookupPaths(path, vers)
 	if err != nil {
 		return nil, err
 	}
-	file := c.name + "/lookup/" + epath + "@" + evers
-	remotePath := "/lookup/" + epath + "@" + evers
 
 	// Fetch the data.
 	// The lookupCache avoids redundant ReadCache/GetURL operations
 	// (especially since go.sum lines tend to come in pairs for a given
 	// path and version) and also avoids having multiple of the same
 	// request in flight at once.
-	type cached struct {
-		data []byte
-		err  error
-	}
 	result := c.record.Do(file, func() interface{} {
 		// Try the on-disk cache, or else get from web.
 		writeCache := false
 		data, err := c.client.ReadCache(file)
 		if err != nil {
+			before, beforeMsg := c.latestHead()
 			data, err = c.client.ReadRemote(remotePath)
 			if err != nil {
-				return cached{nil, err}
+				return cachedRecord{nil, err}
+			}
+			if err := c.checkRollback(before, beforeMsg, data); err != nil {
+				return cachedRecord{nil, err}
 			}
 			writeCache = true
 		}
@@ -288,13 +305,13 @@ func (c *Conn) Lookup(path, vers string) (lines []string, err error) {
 		// Validate the record before using it for anything.
 		id, text, treeMsg, err := tlog.ParseRecord(data)
 		if err != nil {
-			return cached{nil, err}
+			return cachedRecord{nil, err}
 		}
 		if err := c.mergeLatest(treeMsg); err != nil {
-			return cached{nil, err}
+			return cachedRecord{nil, err}
 		}
 		if err := c.checkRecord(id, text); err != nil {
-			return cached{nil, err}
+			return cachedRecord{nil, err}
 		}
 
 		// Now that we've validated the record,
@@ -303,8 +320,8 @@ func (c *Conn) Lookup(path, vers string) (lines []string, err error) {
 			c.client.WriteCache(file, data)
 		}
 
-		return cached{data, nil}
-	}).(cached)
+		return cachedRecord{data, nil}
+	}).(cachedRecord)
 	if result.err != nil {
 		return nil, result.err
 	}
@@ -321,6 +338,88 @@ func (c *Conn) Lookup(path, vers string) (lines []string, err error) {
 	return hashes, nil
 }
 
+// LatestTreeHead returns the signed tree head that the Conn has most recently
+// verified, or an empty result if it has yet to see one.
+// The Client stores the same note using WriteConfig(name+"/latest", ...);
+// LatestTreeHead lets a caller whose Client discards configuration writes
+// persist it in some other way and supply it from ReadConfig next time,
+// so that a later Conn can detect a server that forks its log
+// or, with SetRejectRollback, rolls it back.
+func (c *Conn) LatestTreeHead() ([]byte, error) {
+	if err := c.init(); err != nil {
+		return nil, err
+	}
+	c.latestMu.Lock()
+	defer c.latestMu.Unlock()
+	return c.latestMsg, nil
+}
+
+// latestHead returns the Conn's latest known tree and its signed note.
+func (c *Conn) latestHead() (tlog.Tree, []byte) {
+	c.latestMu.Lock()
+	defer c.latestMu.Unlock()
+	return c.latest, c.latestMsg
+}
+
+// checkRollback checks, if the Conn is set to reject rollbacks,
+// that the tree head sent by the server with the
+// lookup response data is not older than before, the latest tree known
+// before the request was made, which was signed in beforeMsg.
+// A server can only extend its log, so an older tree is evidence of
+// misbehavior. In that case checkRollback calls c.client.SecurityError
+// and then returns ErrSecurity.
+// Any other problem with data is left for the caller to report.
+func (c *Conn) checkRollback(before tlog.Tree, beforeMsg, data []byte) error {
+	if !c.noRollback {
+		return nil
+	}
+	_, _, treeMsg, err := tlog.ParseRecord(data)
+	if err != nil || len(treeMsg) == 0 {
+		return nil
+	}
+	n, err := note.Open(treeMsg, c.verifiers)
+	if err != nil {
+		return nil
+	}
+	tree, err := tlog.ParseTree([]byte(n.Text))
+	if err != nil || tree.N >= before.N {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "SECURITY ERROR\n")
+	fmt.Fprintf(&buf, "go.sum database server rolled back its log!\n\n")
+	indent := func(b []byte) []byte {
+		return bytes.Replace(b, []byte("\n"), []byte("\n\t"), -1)
+	}
+	fmt.Fprintf(&buf, "known database:\n\t%s\n", indent(beforeMsg))
+	fmt.Fprintf(&buf, "served database:\n\t%s\n", indent(treeMsg))
+	c.client.SecurityError(buf.String())
+	return ErrSecurity
+}
+
+// A cachedRecord is the result of a record lookup, as held in Conn.record.
+type cachedRecord struct {
+	data []byte
+	err  error
+}
+
+// lookupPaths returns the cache file name and the remote path
+// of the record for the given module path and version.
+func (c *Conn) lookupPaths(path, vers string) (file, remotePath string, err error) {
+	epath, err := encodePath(path)
+	if err != nil {
+		return "", "", err
+	}
+	evers, err := encodeVersion(strings.TrimSuffix(vers, "/go.mod"))
+	if err != nil {
+		return "", "", err
+	}
+	file = c.name + "/lookup/" + epath + "@" + evers
+	remotePath = "/lookup/" + epath + "@" + evers
+	return file, remotePath, nil
+}
+
 // mergeLatest merges the tree head in msg
 // with the Conn's current latest tree head,
 // ensuring the result is a consistent timeline.
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"golang.org/x/exp/sumdb/internal/note"
@@ -49,6 +50,57 @@ func TestConnLookup(t *testing.T) {
 	tc.mustHaveLatest(4)
 }
 
+func TestConnLookupBatch(t *testing.T) {
+	tc := newTestClient(t)
+	tc.addRecord("rsc.io/pkg1@v1.0.0", `rsc.io/pkg1 v1.0.0 h1:hash!=
+rsc.io/pkg1 v1.0.0/go.mod h1:gomodhash!=
+`)
+	tc.addRecord("rsc.io/pkg2@v1.0.0", `rsc.io/pkg2 v1.0.0 h1:hash!=
+`)
+	tc2 := tc.fork()
+
+	pairs := []ModVer{
+		{"rsc.io/quote", "v1.5.2"},
+		{"rsc.io/quote", "v1.5.2/go.mod"},
+		{"golang.org/x/text", "v0.0.0-20170915032832-14c0d48ead0c"},
+		{"rsc.io/sampler", "v1.3.0"},
+		{"rsc.io/sampler", "v1.3.0/go.mod"},
+		{"rsc.io/Quote", "v1.5.2"},
+		{"rsc.io/pkg1", "v1.0.0"},
+		{"rsc.io/pkg1", "v1.0.0/go.mod"},
+		{"rsc.io/pkg2", "v1.0.0"},
+		{"rsc.io/missing", "v1.0.0"},
+	}
+
+	// Look up the pairs one at a time with tc, and all at once with tc2.
+	var want []string
+	for _, p := range pairs {
+		lines, err := tc.conn.Lookup(p.Path, p.Version)
+		want = append(want, fmt.Sprint(lines, err))
+	}
+	lines, errs := tc2.conn.LookupBatch(pairs)
+	for i, p := range pairs {
+		if got := fmt.Sprint(lines[i], errs[i]); got != want[i] {
+			t.Errorf("LookupBatch %s@%s = %s, want %s", p.Path, p.Version, got, want[i])
+		}
+	}
+	if tc2.reads >= tc.reads {
+		t.Errorf("LookupBatch made %d remote reads, Lookup made %d; want fewer", tc2.reads, tc.reads)
+	}
+	tc2.mustHaveLatest(tc.treeSize)
+
+	// Everything is now cached.
+	tc2.getOK = false
+	tc2.mustLookup("rsc.io/pkg1", "v1.0.0/go.mod", "rsc.io/pkg1 v1.0.0/go.mod h1:gomodhash!=")
+	tc2.newConn()
+	lines, errs = tc2.conn.LookupBatch(pairs[:len(pairs)-1])
+	for i, err := range errs {
+		if err != nil || len(lines[i]) == 0 {
+			t.Errorf("cached LookupBatch %v = %v, %v", pairs[i], lines[i], err)
+		}
+	}
+}
+
 func TestConnBadTiles(t *testing.T) {
 	tc := newTestClient(t)
 
@@ -154,6 +206,47 @@ func TestConnFork(t *testing.T) {
 	}
 }
 
+func TestConnRollback(t *testing.T) {
+	tc := newTestClient(t)
+	tc.conn.SetRejectRollback()
+
+	// The lookup of rsc.io/Quote comes with the tree of size 4.
+	tc.mustLookup("rsc.io/Quote", "v1.5.2", "rsc.io/Quote v1.5.2 h1:uppercase!=")
+	head, err := tc.conn.LatestTreeHead()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(head, tc.signTree(4)) {
+		t.Fatalf("LatestTreeHead() = %q, want tree 4", head)
+	}
+
+	// The server answers a lookup with the smaller tree of size 3,
+	// as if it had rolled back its log.
+	_, err = tc.conn.Lookup("rsc.io/sampler", "v1.3.0")
+	tc.mustError(err, ErrSecurity.Error())
+	if text := tc.security.String(); !strings.Contains(text, "rolled back its log") {
+		t.Fatalf("security text does not report a rollback:\n%s", text)
+	}
+
+	// A new Conn that starts from the persisted head, as a later run would,
+	// sees the rollback too.
+	tc.security.Reset()
+	tc.newConn()
+	tc.conn.SetRejectRollback()
+	tc.config[testName+"/latest"] = head
+	lines, errs := tc.conn.LookupBatch([]ModVer{{"rsc.io/quote", "v1.5.2"}})
+	tc.mustError(errs[0], ErrSecurity.Error())
+	if lines[0] != nil || tc.security.Len() == 0 {
+		t.Fatalf("LookupBatch after rollback = %v, security text %q", lines[0], tc.security.String())
+	}
+
+	// Without SetRejectRollback, older tree heads are allowed
+	// as long as they are consistent with the latest one.
+	tc.newConn()
+	tc.mustLookup("rsc.io/sampler", "v1.3.0", "rsc.io/sampler v1.3.0 h1:7uVkIFmeBqHfdjD+gZwtXXI+RODJ2Wc4O7MPEh/QiW4=")
+	tc.mustHaveLatest(4)
+}
+
 func TestConnGONOSUMDB(t *testing.T) {
 	tc := newTestClient(t)
 	tc.conn.SetGONOSUMDB("p,*/q")
@@ -195,6 +288,7 @@ type testClient struct {
 	tileHeight int        // tile height to use (default 2)
 	getOK      bool       // should tc.GetURL succeed?
 	getTileOK  bool       // should tc.GetURL of tiles succeed?
+	reads      int64      // number of calls to tc.ReadRemote, accessed atomically
 	treeSize   int64
 	hashes     []tlog.Hash
 	remote     map[string][]byte
@@ -387,6 +481,7 @@ func (tc *testClient) signTree(size int64) []byte {
 func (tc *testClient) ReadRemote(path string) ([]byte, error) {
 	// No mutex here because only the Client should be running
 	// and the Client cannot change tc.get.
+	atomic.AddInt64(&tc.reads, 1)
 	if !tc.getOK {
 		return nil, fmt.Errorf("disallowed remote read %s", path)
 	}
@@ -0,0 +1,73 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typeparams
+
+import (
+	"go/types"
+)
+
+// CoreType returns the core type of typ, or nil if typ does not have a core
+// type.
+//
+// For types whose underlying type is not an interface, the core type is the
+// underlying type. For interfaces and type parameters, the core type is the
+// single underlying type shared by all types in the type set. For example, in
+//
+//	type T[P interface{ ~[]byte; []byte }] int
+//
+// the core type of P is []byte, while in
+//
+//	type T[P interface{ ~int|~int8 }] int
+//
+// P has no core type. A type set made of channel types of the same element
+// type also has a core type, which is the most restrictive of their
+// directions.
+//
+// See https://go.dev/ref/spec#Core_types for the definition of a core type.
+func CoreType(typ types.Type) types.Type {
+	U := typ.Underlying()
+	if _, ok := U.(*types.Interface); !ok {
+		return U
+	}
+
+	terms, err := NormalTerms(U)
+	if len(terms) == 0 || err != nil {
+		// An interface with no terms has all types in its type set, and an
+		// error means the type set is empty or could not be computed. In
+		// neither case is there a core type.
+		return nil
+	}
+
+	U = terms[0].Type().Underlying()
+	i := 1
+	for ; i < len(terms); i++ {
+		if !types.Identical(U, terms[i].Type().Underlying()) {
+			break
+		}
+	}
+	if i == len(terms) {
+		return U
+	}
+
+	// The underlying types differ. They may still be channels with identical
+	// element types, in which case the core type is a channel of the one
+	// direction present, if there is one.
+	ch, ok := U.(*types.Chan)
+	if !ok {
+		return nil
+	}
+	for ; i < len(terms); i++ {
+		curr, ok := terms[i].Type().Underlying().(*types.Chan)
+		if !ok || !types.Identical(ch.Elem(), curr.Elem()) {
+			return nil
+		}
+		if ch.Dir() == types.SendRecv {
+			ch = curr
+		} else if curr.Dir() != types.SendRecv && curr.Dir() != ch.Dir() {
+			return nil
+		}
+	}
+	return ch
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package typeparams_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	. "golang.org/x/exp/typeparams"
+)
+
+func TestCoreType(t *testing.T) {
+	if !Enabled() {
+		t.Skip("typeparams are not enabled")
+	}
+
+	// In the following tests, src must define a type T with (at least) one type
+	// parameter. We will compute the core type of the first type parameter.
+	tests := []struct {
+		src  string
+		want string // or "<nil>" if there is no core type
+	}{
+		{"package emptyinterface; type T[P interface{}] int", "<nil>"},
+		{"package methods; type T[P interface{ m() }] int", "<nil>"},
+		{"package singleton; type T[P interface{ int }] int", "int"},
+		{"package under; type T[P interface{ ~int }] int", "int"},
+		{"package differ; type T[P interface{ ~int | ~int8 }] int", "<nil>"},
+		{"package named; type T[P interface{ ~int | N }] int; type N int", "int"},
+		{"package slice; type T[P interface{ ~[]byte; []byte }] int", "[]byte"},
+		{"package methodsandterms; type T[P interface{ ~string; m() }] int", "string"},
+		{"package emptyintersection; type T[P interface{ ~int; string }] int", "<nil>"},
+
+		{"package chan0; type T[P interface{ chan int | chan<- int }] int", "chan<- int"},
+		{"package chan1; type T[P interface{ <-chan int | chan int }] int", "<-chan int"},
+		{"package chan2; type T[P interface{ <-chan int | chan<- int }] int", "<nil>"},
+		{"package chan3; type T[P interface{ chan int | chan string }] int", "<nil>"},
+
+		{`// package example is taken from the docstring for StructuralTerms
+package example
+
+type A interface{ ~string|~[]byte }
+
+type B interface{ int|string }
+
+type C interface { ~string|~int }
+
+type T[P interface{ A|B; C }] int
+`, "<nil>"},
+		{`package example2
+
+type A interface{ ~string|~[]byte }
+
+type B interface{ []byte|string }
+
+type C interface { ~[]byte|~int }
+
+type T[P interface{ A|B; C }] int
+`, "[]byte"},
+	}
+
+	for _, test := range tests {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "p.go", test.src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Run(f.Name.Name, func(t *testing.T) {
+			conf := types.Config{
+				Error: func(error) {}, // keep going on errors
+			}
+			pkg, _ := conf.Check("", fset, []*ast.File{f}, nil)
+			obj := pkg.Scope().Lookup("T")
+			if obj == nil {
+				t.Fatal("type T not found")
+			}
+			T := ForNamed(obj.Type().(*types.Named)).At(0)
+			got := "<nil>"
+			if ct := CoreType(T); ct != nil {
+				got = types.TypeString(ct, types.RelativeTo(pkg))
+			}
+			if got != test.want {
+				t.Errorf("CoreType(%s) = %s, want %s", T, got, test.want)
+			}
+		})
+	}
+}